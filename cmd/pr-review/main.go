@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -10,7 +11,10 @@ import (
 	"pr-review/internal/http/server"
 	"pr-review/internal/repository/postgres"
 	"pr-review/internal/usecases"
+	"sync"
 	"syscall"
+	// база часовых поясов для REVIEW_TIMEZONE: в образе alpine её нет
+	_ "time/tzdata"
 )
 
 func main() {
@@ -27,7 +31,7 @@ func main() {
 	}))
 
 	db := postgres.New(cfg.DatabaseConfig)
-	uc := usecases.New(log, db)
+	uc := usecases.New(log, cfg.ReviewConfig, db)
 	m := middlewares.New(log)
 	h := handlers.New(log, uc)
 
@@ -36,11 +40,20 @@ func main() {
 	signCh := make(chan os.Signal, 1)
 	signal.Notify(signCh, syscall.SIGTERM, syscall.SIGINT)
 
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Go(func() { uc.RunAbsenceWorker(workerCtx, cfg.ReviewConfig.AbsenceCheckInterval) })
+	workers.Go(func() { uc.RunBackfillWorker(workerCtx, cfg.ReviewConfig.BackfillInterval) })
+	workers.Go(func() { uc.RunEscalationWorker(workerCtx, cfg.ReviewConfig.EscalationCheckInterval) })
+
 	log.Info("starting http server", slog.Any("config", cfg))
 	go s.Run()
 
 	sign := <-signCh
 	log.Info("stopping http server", slog.String("signal", sign.String()))
+	stopWorkers()
+	// фоновые задачи дописывают текущий проход до закрытия пула соединений
+	workers.Wait()
 	db.Stop()
 	s.Stop()
 }
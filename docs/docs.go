@@ -15,23 +15,23 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/pullRequest/create": {
+        "/ownership/add": {
             "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "PullRequests"
+                    "Ownership"
                 ],
-                "summary": "Создать PR и автоматически назначить до 2 ревьюверов из команды автора",
+                "summary": "Добавить правило владения путями в конец списка правил",
                 "parameters": [
                     {
-                        "description": "PR",
+                        "description": "Шаблон пути в формате CODEOWNERS, команды и пользователи-владельцы",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreatePRRequest"
+                            "$ref": "#/definitions/dto.AddOwnershipRuleRequest"
                         }
                     }
                 ],
@@ -39,17 +39,62 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.CreatePRResponse"
+                            "$ref": "#/definitions/dto.AddOwnershipRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Автор не найден",
+                        "description": "Команда или пользователь не найдены",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "PR уже существует",
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ownership/delete": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ownership"
+                ],
+                "summary": "Удалить правило владения путями",
+                "parameters": [
+                    {
+                        "description": "Id правила",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteOwnershipRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Правило удалено"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правило не найдено",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -63,35 +108,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/pullRequest/merge": {
+        "/ownership/import": {
             "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "PullRequests"
+                    "Ownership"
                 ],
-                "summary": "Пометить PR как MERGED (идемпотентная операция)",
+                "summary": "Заменить все правила владения путями правилами из файла CODEOWNERS. Владелец @name ищется среди команд, затем среди username пользователей",
                 "parameters": [
                     {
-                        "description": "PR id",
+                        "description": "Содержимое файла CODEOWNERS",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.MergePRRequest"
+                            "$ref": "#/definitions/dto.ImportCodeownersRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "PR в состоянии MERGED",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.MergePRResponse"
+                            "$ref": "#/definitions/dto.OwnershipRulesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или неверный формат файла",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "PR не найден",
+                        "description": "Владелец не найден",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -105,7 +156,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/pullRequest/reassign": {
+        "/ownership/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ownership"
+                ],
+                "summary": "Получить правила владения путями в порядке применения (при совпадении нескольких правил применяется последнее)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OwnershipRulesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/addReviewers": {
             "post": {
                 "produces": [
                     "application/json"
@@ -113,15 +189,15 @@ const docTemplate = `{
                 "tags": [
                     "PullRequests"
                 ],
-                "summary": "Переназначить конкретного ревьювера на другого из его команды",
+                "summary": "Назначить на PR конкретных ревьюверов, в том числе из других команд, сверх автоматического подбора (источник REQUESTED). Уже назначенные пропускаются",
                 "parameters": [
                     {
-                        "description": "PR id \u0026 old reviewer id",
+                        "description": "PR id \u0026 reviewer ids",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ReassignPRRequest"
+                            "$ref": "#/definitions/dto.AddReviewersRequest"
                         }
                     }
                 ],
@@ -129,7 +205,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.ReassignPRResponse"
+                            "$ref": "#/definitions/dto.AddReviewersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
@@ -139,7 +221,7 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Нет доступных кандидатов",
+                        "description": "Ревьювер - автор PR'а, неактивен или запрещён правилом команды; автор PR'а не состоит в команде",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -153,7 +235,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/pullRequest/statistics": {
+        "/pullRequest/assignmentExplain": {
             "get": {
                 "produces": [
                     "application/json"
@@ -161,26 +243,21 @@ const docTemplate = `{
                 "tags": [
                     "PullRequests"
                 ],
-                "summary": "Получить статистику по количеству PR'ов у авторов",
+                "summary": "Объяснить подбор ревьюверов PR'а: для каждого подбора - seed, стратегия, кандидаты в порядке стратегии и исключённые кандидаты с причиной",
                 "parameters": [
                     {
-                        "type": "number",
-                        "description": "Страница",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Лимит на страницу",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Идентификатор PR'а",
+                        "name": "pull_request_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.StatisticsResponse"
+                            "$ref": "#/definitions/dto.AssignmentExplainResponse"
                         }
                     },
                     "400": {
@@ -189,6 +266,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка",
                         "schema": {
@@ -198,35 +281,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/add": {
+        "/pullRequest/close": {
             "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Teams"
+                    "PullRequests"
                 ],
-                "summary": "Создать команду с участниками (создаёт/обновляет пользователей)",
+                "summary": "Закрыть PR без merge (из DRAFT или OPEN), ревьюверы с PR'а снимаются",
                 "parameters": [
                     {
-                        "description": "Команда",
+                        "description": "PR id",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.AddTeamRequest"
+                            "$ref": "#/definitions/dto.ChangePRStatusRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.AddTeamResponse"
+                            "$ref": "#/definitions/dto.ChangePRStatusResponse"
                         }
                     },
                     "400": {
-                        "description": "Неверный запрос или команда с таким team_name уже существует",
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "PR уже MERGED или CLOSED",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -240,39 +335,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/get": {
-            "get": {
+        "/pullRequest/create": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Teams"
+                    "PullRequests"
                 ],
-                "summary": "Получить команду с участниками",
+                "summary": "Создать PR и автоматически назначить ревьюверов: сначала владельцев изменённых файлов (files), затем из команды автора (до reviewers_per_pr команды, по умолчанию 2)",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Название команды",
-                        "name": "team_name",
-                        "in": "query",
-                        "required": true
+                        "description": "PR",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePRRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetTeamResponse"
+                            "$ref": "#/definitions/dto.CreatePRResponse"
                         }
                     },
-                    "400": {
-                        "description": "Неверный запрос",
+                    "404": {
+                        "description": "Автор не найден",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Команда не найдена",
+                    "409": {
+                        "description": "PR уже существует, у автора нет основной команды или он не состоит в указанной",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -286,20 +383,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/getReview": {
+        "/pullRequest/get": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "PullRequests"
                 ],
-                "summary": "Получить PR'ы, где пользователь назначен ревьювером",
+                "summary": "Получить PR: статус, ревьюверы с username, командой и активностью, автор с командой, need_more_reviewers, время создания и merge",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Идентификатор пользователя",
-                        "name": "user_id",
+                        "description": "Идентификатор PR'а",
+                        "name": "pull_request_id",
                         "in": "query",
                         "required": true
                     }
@@ -308,7 +405,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetReviewResponse"
+                            "$ref": "#/definitions/dto.GetPRResponse"
                         }
                     },
                     "400": {
@@ -318,7 +415,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Пользователь не найден",
+                        "description": "PR не найден",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -332,31 +429,29 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/setIsActive": {
-            "post": {
+        "/pullRequest/history": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "PullRequests"
                 ],
-                "summary": "Установить флаг активности пользователя",
+                "summary": "История PR'а: создание, назначения и снятия ревьюверов, смены статуса, автора и need_more_reviewers с операцией, выполнившим её (заголовок X-Actor) и временем",
                 "parameters": [
                     {
-                        "description": "Установка пользователя активным/неактивным",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.SetIsActiveRequest"
-                        }
+                        "type": "string",
+                        "description": "Идентификатор PR'а",
+                        "name": "pull_request_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.SetIsActiveResponse"
+                            "$ref": "#/definitions/dto.PRHistoryResponse"
                         }
                     },
                     "400": {
@@ -366,7 +461,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Пользователь не найден",
+                        "description": "PR не найден",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -379,79 +474,2584 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AddTeamRequest": {
-            "type": "object",
-            "properties": {
-                "members": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Member"
-                    }
-                },
-                "team_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "dto.AddTeamResponse": {
-            "type": "object",
-            "properties": {
-                "team": {
-                    "$ref": "#/definitions/dto.Team"
-                }
-            }
-        },
-        "dto.CreatePRRequest": {
-            "type": "object",
-            "properties": {
-                "author_id": {
-                    "type": "string"
-                },
-                "pull_request_id": {
-                    "type": "string"
-                },
-                "pull_request_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "dto.CreatePRResponse": {
-            "type": "object",
-            "properties": {
-                "pr": {
-                    "$ref": "#/definitions/models.PullRequest"
-                }
-            }
-        },
-        "dto.ErrorField": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
-                }
-            }
         },
-        "dto.ErrorResponse": {
+        "/pullRequest/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Список PR'ов с фильтрами и курсорной пагинацией. Для следующей страницы передаётся next_cursor из ответа с теми же sort и order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Статусы через запятую: DRAFT, OPEN, MERGED, CLOSED",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор",
+                        "name": "author_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Команда автора",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Назначенный ревьювер",
+                        "name": "reviewer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Не хватает ревьюверов до квоты команды",
+                        "name": "need_more_reviewers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создан не раньше (RFC 3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создан раньше (RFC 3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Смержен не раньше (RFC 3339)",
+                        "name": "merged_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Смержен раньше (RFC 3339)",
+                        "name": "merged_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока названия без учёта регистра",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поле сортировки: created_at (по умолчанию), merged_at (только смерженные PR'ы), title",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Порядок: desc (по умолчанию) или asc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы, от 1 до 100, по умолчанию 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Позиция следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListPRsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/merge": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Пометить PR как MERGED (идемпотентная операция). Если в команде автора задано required_approvals, нужны одобрения назначенных ревьюверов",
+                "parameters": [
+                    {
+                        "description": "PR id",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MergePRRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "PR в состоянии MERGED",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MergePRResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Недостаточно одобрений или PR в статусе DRAFT/CLOSED",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/overdue": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Открытые PR'ы авторов команды, назначенные ревьюверы которых не приняли решение за SLA команды (review_sla_hours рабочих часов, выходные не учитываются)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OverduePRsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/ready": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Перевести черновик PR'а (DRAFT) в OPEN и назначить ревьюверов",
+                "parameters": [
+                    {
+                        "description": "PR id",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePRStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePRStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "PR не в статусе DRAFT",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/reassign": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Переназначить конкретного ревьювера на другого из его команды",
+                "parameters": [
+                    {
+                        "description": "PR id \u0026 old reviewer id",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReassignPRRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReassignPRResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR или пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Нет доступных кандидатов",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/removeReviewer": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Снять ревьювера с PR'а. С backfill недостающие до квоты команды ревьюверы сразу подбираются заново, без него место остаётся пустым (need_more_reviewers_reason REVIEWER_REMOVED, фоновое дозаполнение такой PR не трогает)",
+                "parameters": [
+                    {
+                        "description": "PR id, reviewer id, backfill",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RemoveReviewerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RemoveReviewerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь не был назначен ревьювером",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/reopen": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Переоткрыть закрытый PR (CLOSED -\u003e OPEN), ревьюверы назначаются заново",
+                "parameters": [
+                    {
+                        "description": "PR id",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePRStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePRStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "PR не в статусе CLOSED",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/review": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Сохранить решение назначенного ревьювера по открытому PR'у (APPROVED, CHANGES_REQUESTED, COMMENTED). Учитывается последнее решение ревьювера",
+                "parameters": [
+                    {
+                        "description": "PR id, id ревьювера, решение и комментарий",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReviewPRRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReviewPRResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "PR не открыт (MERGED, CLOSED, DRAFT) или пользователь не назначен ревьювером",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/statistics": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Получить статистику по количеству PR'ов у авторов",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Страница",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Лимит на страницу",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StatisticsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/update": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Изменить название и/или автора PR'а. При смене автора открытого PR'а новый автор снимается с ревью, а ревьюверы добираются до квоты его команды; если команда другая, reviewers_policy решает, оставить ли текущих ревьюверов (KEEP, по умолчанию) или подобрать заново (REPLACE)",
+                "parameters": [
+                    {
+                        "description": "PR id, название, автор",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePRRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePRResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR или автор не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Нельзя менять автора после MERGED или новый автор не состоит в команде",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/add": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Создать команду с участниками (создаёт/обновляет пользователей)",
+                "parameters": [
+                    {
+                        "description": "Команда",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddTeamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddTeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или команда с таким team_name уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Один из участников уволен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/addMembers": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Добавить участников в существующую команду (создаёт/обновляет пользователей, участники другой команды переходят в эту). С неактивных участников снимаются открытые ревью",
+                "parameters": [
+                    {
+                        "description": "Команда и участники",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamMembersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новый состав команды",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или пользователь с таким username уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Один из участников уволен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/addRule": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Добавить команде правило: EXCLUDE - никогда не назначать ревьювера на PR'ы автора, INCLUDE - всегда назначать. Применяется при создании PR'а и переназначении ревьювера",
+                "parameters": [
+                    {
+                        "description": "Команда автора, вид правила, автор и ревьювер",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddReviewerRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddReviewerRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, автор не в команде или правило для пары уже есть",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда или пользователь не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/delete": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Удалить команду вместе с её правилами ревьюверов и владением путями. С move_members_to участники переводятся в указанную команду и сохраняют свои ревью, без него - деактивируются, остаются без команды, а их открытые ревью переназначаются. Без move_members_to команду с открытыми PR'ами или черновиками удалить нельзя",
+                "parameters": [
+                    {
+                        "description": "Команда и команда для её участников",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteTeamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteTeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда или команда для участников не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "У команды есть открытые PR'ы, а move_members_to не указан",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/deleteRule": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Удалить правило команды для пары автор - ревьювер",
+                "parameters": [
+                    {
+                        "description": "Id правила",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteReviewerRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Правило удалено"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правило не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/get": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Получить команду с участниками",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/members": {
+            "put": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Заменить состав команды (идемпотентно): участники не из списка исключаются с переназначением их открытых ревью, остальные добавляются/обновляются",
+                "parameters": [
+                    {
+                        "description": "Команда и её новый состав",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamMembersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новый состав команды",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или пользователь с таким username уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Один из участников уволен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/merge": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Слить команду в другую: участники, правила ревьюверов, владение путями и резервные команды переходят в into_team_name, исходная команда удаляется. Настройки целевой команды не меняются",
+                "parameters": [
+                    {
+                        "description": "Исходная и целевая команды",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MergeTeamsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новый состав целевой команды",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Исходная или целевая команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/removeMembers": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Исключить участников из команды, их открытые ревью переназначаются. Исключённые пользователи остаются без команды",
+                "parameters": [
+                    {
+                        "description": "Команда и id участников",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RemoveTeamMembersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новый состав команды",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetTeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена или пользователь не состоит в ней",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/rename": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Переименовать команду",
+                "parameters": [
+                    {
+                        "description": "Текущее и новое название команды",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RenameTeamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RenameTeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или команда с таким названием уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/rules": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Получить правила команды для пар автор - ревьювер",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReviewerRulesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/settings": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Получить настройки назначения ревьюверов команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название команды",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Изменить настройки назначения ревьюверов команды (передаются только изменяемые поля)",
+                "parameters": [
+                    {
+                        "description": "Настройки команды",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamSettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда или резервная команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/addAbsence": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Добавить пользователю период отсутствия. Внутри периода пользователь считается неактивным, а при его начале открытые ревью пользователя переназначаются",
+                "parameters": [
+                    {
+                        "description": "Пользователь, начало и конец периода (RFC 3339), причина",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddAbsenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AddAbsenceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/bulkSetIsActive": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Установить флаг активности списку пользователей или всем участникам команды в одной транзакции. Открытые ревью деактивированных переназначаются, в ответе - результат по каждому пользователю",
+                "parameters": [
+                    {
+                        "description": "Пользователи (user_ids или team_name) и флаг активности",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkSetIsActiveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkSetIsActiveResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь или команда не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Уволенного пользователя нельзя активировать",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/deleteAbsence": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Удалить период отсутствия. Снятые с пользователя ревью обратно не возвращаются",
+                "parameters": [
+                    {
+                        "description": "Id периода отсутствия",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteAbsenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Период отсутствия удалён"
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Период отсутствия не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/getAbsences": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить текущие и будущие периоды отсутствия пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Id пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetAbsencesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/getReview": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить PR'ы, где пользователь назначен ревьювером",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/offboard": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Уволить пользователя: деактивировать, исключить из команд и переназначить его открытые ревью в одной транзакции. К его открытым PR'ам применяется политика authored_prs_policy: KEEP (по умолчанию) - оставить, CLOSE - закрыть, TRANSFER - передать пользователю transfer_to. История пользователя сохраняется",
+                "parameters": [
+                    {
+                        "description": "Пользователь и политика для его PR'ов",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.OffboardUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OffboardUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь или получатель PR'ов не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь или получатель PR'ов уже уволен, получатель неактивен или у него нет основной команды",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/setIsActive": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Установить флаг активности пользователя. Открытые ревью деактивированного пользователя переназначаются, в ответе - на кого",
+                "parameters": [
+                    {
+                        "description": "Установка пользователя активным/неактивным",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetIsActiveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetIsActiveResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Уволенного пользователя нельзя активировать",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/setMaxOpenReviews": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Задать пользователю ограничение открытых ревью (0 - брать ограничение команды). Кандидаты, достигшие ограничения, не назначаются",
+                "parameters": [
+                    {
+                        "description": "Ограничение открытых ревью",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetMaxOpenReviewsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetMaxOpenReviewsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/setPrimaryTeam": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Сделать одну из команд пользователя основной. В ней по умолчанию открываются его PR'ы",
+                "parameters": [
+                    {
+                        "description": "Основная команда",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetPrimaryTeamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetPrimaryTeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден или не состоит в команде",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/setTags": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Заменить теги экспертизы пользователя (go, sql, frontend...), по ним ревьюверы подбираются под метки PR'а",
+                "parameters": [
+                    {
+                        "description": "Теги пользователя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetTagsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AddAbsenceRequest": {
+            "type": "object",
+            "properties": {
+                "ends_at": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AddAbsenceResponse": {
+            "type": "object",
+            "properties": {
+                "absence": {
+                    "$ref": "#/definitions/models.Absence"
+                }
+            }
+        },
+        "dto.AddOwnershipRuleRequest": {
+            "type": "object",
+            "properties": {
+                "pattern": {
+                    "type": "string"
+                },
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.AddOwnershipRuleResponse": {
+            "type": "object",
+            "properties": {
+                "rule": {
+                    "$ref": "#/definitions/models.OwnershipRule"
+                }
+            }
+        },
+        "dto.AddReviewerRuleRequest": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AddReviewerRuleResponse": {
+            "type": "object",
+            "properties": {
+                "rule": {
+                    "$ref": "#/definitions/models.ReviewerRule"
+                }
+            }
+        },
+        "dto.AddReviewersRequest": {
+            "type": "object",
+            "properties": {
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "reviewer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.AddReviewersResponse": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "description": "Added ревьюверы, назначенные этим запросом (уже назначенные не повторяются)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                }
+            }
+        },
+        "dto.AddTeamRequest": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Member"
+                    }
+                },
+                "reviewer_strategy": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AddTeamResponse": {
+            "type": "object",
+            "properties": {
+                "team": {
+                    "$ref": "#/definitions/dto.Team"
+                }
+            }
+        },
+        "dto.AssignmentExplainResponse": {
+            "type": "object",
+            "properties": {
+                "decisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AssignmentDecision"
+                    }
+                },
+                "pull_request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BulkSetIsActiveRequest": {
+            "type": "object",
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "team_name": {
+                    "type": "string"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.BulkSetIsActiveResponse": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "description": "Users результат для каждого пользователя в порядке id",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ActivationChange"
+                    }
+                }
+            }
+        },
+        "dto.ChangePRStatusRequest": {
+            "type": "object",
+            "properties": {
+                "pull_request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ChangePRStatusResponse": {
+            "type": "object",
+            "properties": {
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                }
+            }
+        },
+        "dto.CreatePRRequest": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "draft": {
+                    "description": "Draft создать черновик: ревьюверы назначаются, когда он станет готов к ревью",
+                    "type": "boolean"
+                },
+                "files": {
+                    "description": "Files изменённые файлы PR'а, по ним подбираются владельцы путей",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "labels": {
+                    "description": "Labels метки PR'а, ревьюверы подбираются так, чтобы каждую метку покрывал тег хотя бы одного из них",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "pull_request_name": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "description": "TeamName команда автора, из которой подбираются ревьюверы. По умолчанию - основная команда автора",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreatePRResponse": {
+            "type": "object",
+            "properties": {
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                }
+            }
+        },
+        "dto.DeleteAbsenceRequest": {
+            "type": "object",
+            "properties": {
+                "absence_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeleteOwnershipRuleRequest": {
+            "type": "object",
+            "properties": {
+                "rule_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeleteReviewerRuleRequest": {
+            "type": "object",
+            "properties": {
+                "rule_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeleteTeamRequest": {
+            "type": "object",
+            "properties": {
+                "move_members_to": {
+                    "description": "MoveMembersTo команда, в которую переводятся участники; если не задана, участники деактивируются",
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeleteTeamResponse": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "description": "Members id переведённых или, если move_members_to не задана, деактивированных участников (не состоявших в других командах)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "move_members_to": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "USER_OFFBOARDED",
+                "TRANSFER_TARGET_INACTIVE"
+            ],
+            "x-enum-varnames": [
+                "ErrCodeUserOffboarded",
+                "ErrCodeTransferTargetInactive"
+            ]
+        },
+        "dto.ErrorField": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/dto.ErrorCode"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/dto.ErrorField"
+                }
+            }
+        },
+        "dto.GetAbsencesResponse": {
+            "type": "object",
+            "properties": {
+                "absences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Absence"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetPRResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "description": "Author автор PR'а с его командой",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "need_more_reviewers": {
+                    "type": "boolean"
+                },
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                },
+                "reviewers": {
+                    "description": "Reviewers назначенные ревьюверы с username, командой и активностью",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                }
+            }
+        },
+        "dto.GetReviewResponse": {
+            "type": "object",
+            "properties": {
+                "pull_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PullRequest"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetTeamResponse": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Member"
+                    }
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ImportCodeownersRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ListPRsResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "NextCursor передаётся в cursor для получения следующей страницы, пусто - страница последняя",
+                    "type": "string"
+                },
+                "pull_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PullRequest"
+                    }
+                }
+            }
+        },
+        "dto.MergePRRequest": {
+            "type": "object",
+            "properties": {
+                "pull_request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MergePRResponse": {
+            "type": "object",
+            "properties": {
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                }
+            }
+        },
+        "dto.MergeTeamsRequest": {
+            "type": "object",
+            "properties": {
+                "into_team_name": {
+                    "description": "IntoName команда, в которую она сливается",
+                    "type": "string"
+                },
+                "team_name": {
+                    "description": "Name команда, которая сливается и удаляется",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OffboardUserRequest": {
+            "type": "object",
+            "properties": {
+                "authored_prs_policy": {
+                    "type": "string"
+                },
+                "transfer_to": {
+                    "description": "TransferTo кому передать открытые PR'ы при политике TRANSFER",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OffboardUserResponse": {
+            "type": "object",
+            "properties": {
+                "authored_prs": {
+                    "description": "AuthoredPRs открытые PR'ы пользователя, к которым применена политика",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "authored_prs_policy": {
+                    "type": "string"
+                },
+                "reassigned_prs": {
+                    "description": "ReassignedPRs PR'ы, с ревью которых снят пользователь",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "dto.OverduePRsResponse": {
+            "type": "object",
+            "properties": {
+                "pull_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OverduePR"
+                    }
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OwnershipRulesResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OwnershipRule"
+                    }
+                }
+            }
+        },
+        "dto.PRHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PREvent"
+                    }
+                },
+                "pull_request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReassignPRRequest": {
+            "type": "object",
+            "properties": {
+                "old_reviewer_id": {
+                    "type": "string"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReassignPRResponse": {
+            "type": "object",
+            "properties": {
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                },
+                "replaced_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RemoveReviewerRequest": {
+            "type": "object",
+            "properties": {
+                "backfill": {
+                    "description": "Backfill сразу подобрать недостающих до квоты команды ревьюверов",
+                    "type": "boolean"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RemoveReviewerResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "$ref": "#/definitions/dto.ErrorField"
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                },
+                "replaced_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RemoveTeamMembersRequest": {
+            "type": "object",
+            "properties": {
+                "team_name": {
+                    "type": "string"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.RenameTeamRequest": {
+            "type": "object",
+            "properties": {
+                "new_team_name": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RenameTeamResponse": {
+            "type": "object",
+            "properties": {
+                "previous_team_name": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReviewPRRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "decision": {
+                    "type": "string"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReviewPRResponse": {
+            "type": "object",
+            "properties": {
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                },
+                "review": {
+                    "$ref": "#/definitions/models.Review"
+                }
+            }
+        },
+        "dto.ReviewerRulesResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewerRule"
+                    }
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SetIsActiveRequest": {
+            "type": "object",
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SetIsActiveResponse": {
+            "type": "object",
+            "properties": {
+                "reassigned_reviews": {
+                    "description": "Reassigned снятые с деактивированного пользователя ревью и назначенные вместо него ревьюверы",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewReassignment"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "dto.SetMaxOpenReviewsRequest": {
+            "type": "object",
+            "properties": {
+                "max_open_reviews": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SetMaxOpenReviewsResponse": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "dto.SetPrimaryTeamRequest": {
+            "type": "object",
+            "properties": {
+                "team_name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SetPrimaryTeamResponse": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "dto.SetTagsRequest": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SetTagsResponse": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "dto.StatisticsResponse": {
+            "type": "object",
+            "properties": {
+                "authors_count": {
+                    "type": "integer"
+                },
+                "prs": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.Team": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Member"
+                    }
+                },
+                "reviewer_strategy": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TeamMembersRequest": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Member"
+                    }
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TeamSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "fallback_teams": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_open_reviews": {
+                    "description": "MaxOpenReviews ограничение открытых ревью по умолчанию для участников команды, 0 - без ограничения",
+                    "type": "integer"
+                },
+                "required_approvals": {
+                    "description": "RequiredApprovals сколько одобрений нужно для merge PR'а, 0 - без ограничения",
+                    "type": "integer"
+                },
+                "review_sla_hours": {
+                    "description": "ReviewSLAHours SLA ревью в рабочих часах, 0 - без SLA",
+                    "type": "integer"
+                },
+                "reviewer_strategy": {
+                    "type": "string"
+                },
+                "reviewers_per_pr": {
+                    "type": "integer"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TeamSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "settings": {
+                    "$ref": "#/definitions/models.TeamSettings"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UpdatePRRequest": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "pull_request_name": {
+                    "type": "string"
+                },
+                "reviewers_policy": {
+                    "description": "ReviewersPolicy что делать с ревьюверами, если новый автор из другой команды, по умолчанию KEEP",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UpdatePRResponse": {
+            "type": "object",
+            "properties": {
+                "pr": {
+                    "$ref": "#/definitions/models.PullRequest"
+                }
+            }
+        },
+        "models.Absence": {
+            "type": "object",
+            "properties": {
+                "absence_id": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "dto.GetReviewResponse": {
+        "models.ActivationChange": {
             "type": "object",
             "properties": {
-                "pull_requests": {
+                "changed": {
+                    "description": "Changed false - пользователь уже был в нужном состоянии",
+                    "type": "boolean"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "reassigned_reviews": {
+                    "description": "Reassigned снятые с деактивированного пользователя ревью и назначенные вместо него ревьюверы",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.PullRequest"
+                        "$ref": "#/definitions/models.ReviewReassignment"
                     }
                 },
                 "user_id": {
@@ -459,115 +3059,230 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.GetTeamResponse": {
+        "models.Assignment": {
             "type": "object",
             "properties": {
-                "members": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Member"
-                    }
+                "assigned_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decision": {
+                    "description": "Decision последнее решение ревьювера, пусто - ещё не ревьюил",
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
                 },
                 "team_name": {
+                    "description": "TeamName команда, из которой взят ревьювер, если это не команда PR'а",
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.MergePRRequest": {
+        "models.AssignmentDecision": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "decision_id": {
+                    "type": "string"
+                },
                 "pull_request_id": {
                     "type": "string"
+                },
+                "seed": {
+                    "type": "integer"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DecisionStep"
+                    }
+                },
+                "trigger": {
+                    "type": "string"
                 }
             }
         },
-        "dto.MergePRResponse": {
-            "type": "object",
-            "properties": {
-                "pr": {
-                    "$ref": "#/definitions/models.PullRequest"
-                }
-            }
-        },
-        "dto.ReassignPRRequest": {
+        "models.DecisionStep": {
             "type": "object",
             "properties": {
-                "old_reviewer_id": {
+                "assigned": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "candidates": {
+                    "description": "Candidates кандидаты в порядке, в котором их расставила стратегия",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "excluded": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExcludedCandidate"
+                    }
+                },
+                "source": {
                     "type": "string"
                 },
-                "pull_request_id": {
+                "strategy": {
+                    "type": "string"
+                },
+                "team_name": {
                     "type": "string"
+                },
+                "uncovered_labels": {
+                    "description": "Labels метки PR'а, ещё не покрытые перед этим шагом",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "dto.ReassignPRResponse": {
+        "models.ExcludedCandidate": {
             "type": "object",
             "properties": {
-                "pr": {
-                    "$ref": "#/definitions/models.PullRequest"
+                "reason": {
+                    "type": "string"
                 },
-                "replaced_by": {
+                "rule_id": {
+                    "description": "RuleId правило команды, запретившее назначение, для причины RULE",
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.SetIsActiveRequest": {
+        "models.Member": {
             "type": "object",
             "properties": {
                 "is_active": {
                     "type": "boolean"
                 },
+                "max_open_reviews": {
+                    "description": "MaxOpenReviews сколько открытых ревью может быть у пользователя одновременно, nil - берётся значение команды",
+                    "type": "integer"
+                },
+                "tags": {
+                    "description": "Tags области экспертизы пользователя (go, sql, frontend...), по ним подбираются ревьюверы под метки PR'а",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "user_id": {
                     "type": "string"
+                },
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "dto.SetIsActiveResponse": {
+        "models.OverduePR": {
             "type": "object",
             "properties": {
-                "user": {
-                    "$ref": "#/definitions/models.User"
+                "author_id": {
+                    "type": "string"
+                },
+                "overdue_reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OverdueReview"
+                    }
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "pull_request_name": {
+                    "type": "string"
                 }
             }
         },
-        "dto.StatisticsResponse": {
+        "models.OverdueReview": {
             "type": "object",
             "properties": {
-                "authors_count": {
-                    "type": "integer"
+                "assigned_at": {
+                    "type": "string"
                 },
-                "prs": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "deadline": {
+                    "description": "Deadline до какого момента ревьювер должен был принять решение",
+                    "type": "string"
+                },
+                "escalated_at": {
+                    "description": "EscalatedAt когда назначение эскалировано, если замену найти не удалось и ревьювер остался",
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
                 }
             }
         },
-        "dto.Team": {
+        "models.OwnershipRule": {
             "type": "object",
             "properties": {
-                "members": {
+                "pattern": {
+                    "type": "string"
+                },
+                "rule_id": {
+                    "type": "string"
+                },
+                "teams": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Member"
+                        "type": "string"
                     }
                 },
-                "team_name": {
-                    "type": "string"
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.Member": {
+        "models.PREvent": {
             "type": "object",
             "properties": {
-                "is_active": {
-                    "type": "boolean"
+                "actor": {
+                    "description": "Actor кто выполнил операцию (заголовок X-Actor запроса), пусто - неизвестно или фоновая задача",
+                    "type": "string"
                 },
-                "user_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "username": {
+                "event_id": {
+                    "type": "integer"
+                },
+                "from": {
+                    "description": "From и To прежнее и новое значение: статус, автор или причина need_more_reviewers",
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "UserId ревьювер, назначенный или снятый с PR'а",
                     "type": "string"
                 }
             }
@@ -575,18 +3290,44 @@ const docTemplate = `{
         "models.PullRequest": {
             "type": "object",
             "properties": {
+                "applied_rules": {
+                    "description": "AppliedRules правила команды, которые запретили или потребовали назначить ревьювера при последнем подборе",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewerRule"
+                    }
+                },
                 "assigned_reviewers": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "assignments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Assignment"
+                    }
+                },
                 "author_id": {
                     "type": "string"
                 },
+                "created_at": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "merged_at": {
                     "type": "string"
                 },
+                "need_more_reviewers_reason": {
+                    "description": "NeedMoreReviewersReason почему PR'у не хватает ревьюверов до квоты команды, пусто - хватает",
+                    "type": "string"
+                },
                 "pull_request_id": {
                     "type": "string"
                 },
@@ -595,6 +3336,98 @@ const docTemplate = `{
                 },
                 "status": {
                     "type": "string"
+                },
+                "team_name": {
+                    "description": "TeamName команда PR'а, из которой подбираются ревьюверы, пусто - у автора не было команды или она удалена",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Review": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decision": {
+                    "type": "string"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "review_id": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReviewReassignment": {
+            "type": "object",
+            "properties": {
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "replaced_by": {
+                    "description": "ReplacedBy назначенные вместо пользователя, пусто - замена не нашлась",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ReviewerRule": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "rule_id": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TeamSettings": {
+            "type": "object",
+            "properties": {
+                "fallback_teams": {
+                    "description": "FallbackTeams команды, из которых по порядку добираются ревьюверы, если в команде не хватает кандидатов",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_open_reviews": {
+                    "description": "MaxOpenReviews ограничение открытых ревью для участников команды, у которых не задано своё",
+                    "type": "integer"
+                },
+                "required_approvals": {
+                    "description": "RequiredApprovals сколько назначенных ревьюверов должны одобрить PR, чтобы его можно было смержить, 0 - без ограничения",
+                    "type": "integer"
+                },
+                "review_sla_hours": {
+                    "description": "ReviewSLAHours за сколько рабочих часов назначенный ревьювер должен принять решение, иначе ревью эскалируется",
+                    "type": "integer"
+                },
+                "reviewer_strategy": {
+                    "type": "string"
+                },
+                "reviewers_per_pr": {
+                    "type": "integer"
                 }
             }
         },
@@ -604,9 +3437,31 @@ const docTemplate = `{
                 "is_active": {
                     "type": "boolean"
                 },
+                "max_open_reviews": {
+                    "description": "MaxOpenReviews собственное ограничение открытых ревью пользователя, без учёта значения по умолчанию команды",
+                    "type": "integer"
+                },
+                "offboarded_at": {
+                    "description": "OffboardedAt время offboarding'а, nil - пользователь не уволен",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "team_name": {
+                    "description": "TeamName основная команда пользователя, пусто - не задана",
                     "type": "string"
                 },
+                "teams": {
+                    "description": "Teams все команды пользователя в порядке названий",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "user_id": {
                     "type": "string"
                 },
@@ -0,0 +1,172 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAbsence проверяет, что при добавлении начавшегося периода отсутствия ревью пользователя переназначаются,
+// внутри периода он не назначается ревьювером, а после удаления периода снова считается активным
+func TestAbsence(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	teamName := gofakeit.Name() + uuid.NewString()
+	authorId := uuid.NewString()
+	reviewerIds := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+	members := []*models.Member{
+		{
+			Id:       authorId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		},
+	}
+	for _, id := range reviewerIds {
+		members = append(members, &models.Member{
+			Id:       id,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		})
+	}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    teamName,
+		Members: members,
+	})
+	require.Equal(t, 201, code)
+
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+	require.Equal(t, 201, code)
+	require.Len(t, response.PR.Reviewers, 2)
+	absentId := response.PR.Reviewers[0]
+
+	// 1. Конец периода раньше начала отклоняется
+	now := time.Now()
+	_, code = addAbsence(t, st, &dto.AddAbsenceRequest{
+		UserId:   absentId,
+		StartsAt: now.Add(time.Hour),
+		EndsAt:   now,
+	})
+	require.Equal(t, 400, code)
+
+	// 2. Начавшийся период сразу снимает ревью с пользователя, замена - третий участник
+	added, code := addAbsence(t, st, &dto.AddAbsenceRequest{
+		UserId:   absentId,
+		StartsAt: now.Add(-time.Hour),
+		EndsAt:   now.Add(time.Hour),
+		Reason:   "vacation",
+	})
+	require.Equal(t, 201, code)
+	require.Equal(t, absentId, added.Absence.UserId)
+	require.Equal(t, "vacation", added.Absence.Reason)
+
+	reviews, code := getUserReviews(t, st, absentId)
+	require.Equal(t, 200, code)
+	require.Empty(t, reviews.PullRequests)
+
+	explain, code := assignmentExplain(t, st, response.PR.Id)
+	require.Equal(t, 200, code)
+	require.Len(t, explain.Decisions, 2)
+	require.Equal(t, models.TriggerAbsence, explain.Decisions[1].Trigger)
+	require.Len(t, explain.Decisions[1].Steps[0].Assigned, 1)
+	require.NotEqual(t, absentId, explain.Decisions[1].Steps[0].Assigned[0])
+
+	// 3. Внутри периода пользователь неактивен и не назначается
+	team, code := getTeam(t, st, teamName)
+	require.Equal(t, 200, code)
+	for _, m := range team.Members {
+		require.Equal(t, m.Id != absentId, m.IsActive)
+	}
+	for range 5 {
+		response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+		require.Equal(t, 201, code)
+		require.NotContains(t, response.PR.Reviewers, absentId)
+	}
+
+	// 4. Будущий период не влияет на активность
+	future, code := addAbsence(t, st, &dto.AddAbsenceRequest{
+		UserId:   absentId,
+		StartsAt: now.Add(24 * time.Hour),
+		EndsAt:   now.Add(48 * time.Hour),
+	})
+	require.Equal(t, 201, code)
+
+	absences, code := getAbsences(t, st, absentId)
+	require.Equal(t, 200, code)
+	require.Len(t, absences.Absences, 2)
+	require.Equal(t, added.Absence.Id, absences.Absences[0].Id)
+	require.Equal(t, future.Absence.Id, absences.Absences[1].Id)
+
+	// 5. После удаления текущего периода пользователь снова активен
+	require.Equal(t, 204, deleteAbsence(t, st, added.Absence.Id))
+	require.Equal(t, 404, deleteAbsence(t, st, added.Absence.Id))
+
+	team, code = getTeam(t, st, teamName)
+	require.Equal(t, 200, code)
+	for _, m := range team.Members {
+		require.True(t, m.IsActive)
+	}
+}
+
+func addAbsence(t *testing.T, st *Suite, reqBody *dto.AddAbsenceRequest) (*dto.AddAbsenceResponse, int) {
+	body, _ := json.Marshal(reqBody)
+	httpReq := httptest.NewRequestWithContext(t.Context(), "POST", "/users/addAbsence", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.AddAbsenceResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+func getAbsences(t *testing.T, st *Suite, userId string) (*dto.GetAbsencesResponse, int) {
+	httpReq := httptest.NewRequestWithContext(t.Context(), "GET", "/users/getAbsences?user_id="+userId, nil)
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.GetAbsencesResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+func deleteAbsence(t *testing.T, st *Suite, id string) int {
+	body, _ := json.Marshal(&dto.DeleteAbsenceRequest{Id: id})
+	httpReq := httptest.NewRequestWithContext(t.Context(), "POST", "/users/deleteAbsence", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	return recorder.Result().StatusCode
+}
+
+func getUserReviews(t *testing.T, st *Suite, userId string) (*dto.GetReviewResponse, int) {
+	httpReq := httptest.NewRequestWithContext(t.Context(), "GET", "/users/getReview?user_id="+userId, nil)
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.GetReviewResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
@@ -0,0 +1,82 @@
+package e2e
+
+import (
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"testing"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBackfillAfterActivation проверяет, что PR, которому не хватило ревьюверов,
+// дозаполняется сразу после активации участника команды
+func TestBackfillAfterActivation(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	authorId := uuid.NewString()
+	activeId := uuid.NewString()
+	inactiveId := uuid.NewString()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name: gofakeit.Name() + uuid.NewString(),
+		Members: []*models.Member{
+			{
+				Id:       authorId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+			{
+				Id:       activeId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+			{
+				Id:       inactiveId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: false,
+			},
+		},
+	})
+	require.Equal(t, 201, code)
+
+	// 1. Второго ревьювера не хватает
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+	require.Equal(t, 201, code)
+	require.Equal(t, []string{activeId}, response.PR.Reviewers)
+	require.Equal(t, models.ReasonNoCandidates, response.PR.NeedMoreReviewersReason)
+	prId := response.PR.Id
+
+	// 2. После активации участник сразу назначается на PR
+	isActive := true
+	_, code = setIsActive(t, st, &dto.SetIsActiveRequest{
+		UserId:   inactiveId,
+		IsActive: &isActive,
+	})
+	require.Equal(t, 200, code)
+
+	reviews, code := getUserReviews(t, st, inactiveId)
+	require.Equal(t, 200, code)
+	require.Len(t, reviews.PullRequests, 1)
+	pr := reviews.PullRequests[0]
+	require.Equal(t, prId, pr.Id)
+	require.ElementsMatch(t, []string{activeId, inactiveId}, pr.Reviewers)
+	require.Empty(t, pr.NeedMoreReviewersReason)
+
+	explain, code := assignmentExplain(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Len(t, explain.Decisions, 2)
+	require.Equal(t, models.TriggerBackfill, explain.Decisions[1].Trigger)
+
+	history, code := getPRHistory(t, st, prId)
+	require.Equal(t, 200, code)
+	last := history.Events[len(history.Events)-1]
+	require.Equal(t, models.EventNeedMoreReviewersChanged, last.Kind)
+	require.Equal(t, string(models.ReasonNoCandidates), last.From)
+	require.Empty(t, last.To)
+	require.Equal(t, models.TriggerBackfill, last.Reason)
+}
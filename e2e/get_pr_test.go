@@ -0,0 +1,111 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"testing"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetPR проверяет, что PR отдаётся с автором, его командой и ревьюверами с username, командой и активностью
+func TestGetPR(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	newMember := func() *models.Member {
+		return &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		}
+	}
+	teamName := gofakeit.Name() + uuid.NewString()
+	author, reviewer := newMember(), newMember()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    teamName,
+		Members: []*models.Member{author, reviewer},
+	})
+	require.Equal(t, 201, code)
+	outsiderTeamName := gofakeit.Name() + uuid.NewString()
+	outsider := newMember()
+	_, code = createTeam(t, st, &dto.AddTeamRequest{
+		Name:    outsiderTeamName,
+		Members: []*models.Member{outsider},
+	})
+	require.Equal(t, 201, code)
+
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+	require.Equal(t, 201, code)
+	prId := response.PR.Id
+
+	// 1. Ревьюверов меньше квоты команды
+	details, code := getPR(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Equal(t, prId, details.PR.Id)
+	require.Equal(t, models.StatusOpen, details.PR.Status)
+	require.False(t, details.PR.CreatedAt.IsZero())
+	require.Nil(t, details.PR.MergedAt)
+	require.True(t, details.NeedMoreReviewers)
+	require.Equal(t, author.Id, details.Author.Id)
+	require.Equal(t, author.Username, details.Author.Username)
+	require.Equal(t, teamName, details.Author.TeamName)
+	require.Len(t, details.Reviewers, 1)
+	require.Equal(t, reviewer.Id, details.Reviewers[0].Id)
+	require.Equal(t, reviewer.Username, details.Reviewers[0].Username)
+	require.Equal(t, teamName, details.Reviewers[0].TeamName)
+	require.True(t, details.Reviewers[0].IsActive)
+
+	// 2. Ревьювер из другой команды отдаётся со своей командой
+	_, code = addReviewers(t, st, &dto.AddReviewersRequest{
+		PullRequestID: prId,
+		ReviewerIDs:   []string{outsider.Id},
+	})
+	require.Equal(t, 200, code)
+
+	details, code = getPR(t, st, prId)
+	require.Equal(t, 200, code)
+	require.False(t, details.NeedMoreReviewers)
+	require.Len(t, details.Reviewers, 2)
+	for i, reviewerId := range details.PR.Reviewers {
+		require.Equal(t, reviewerId, details.Reviewers[i].Id)
+		if reviewerId == outsider.Id {
+			require.Equal(t, outsiderTeamName, details.Reviewers[i].TeamName)
+		}
+	}
+
+	// 3. После merge есть время merge
+	_, code = mergePR(t, st, &dto.MergePRRequest{PullRequestID: prId})
+	require.Equal(t, 200, code)
+
+	details, code = getPR(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Equal(t, models.StatusMerged, details.PR.Status)
+	require.NotNil(t, details.PR.MergedAt)
+
+	// 4. Неизвестный и неверный id
+	_, code = getPR(t, st, uuid.NewString())
+	require.Equal(t, 404, code)
+	_, code = getPR(t, st, "not-uuid")
+	require.Equal(t, 400, code)
+}
+
+func getPR(t *testing.T, st *Suite, prId string) (*dto.GetPRResponse, int) {
+	httpReq := httptest.NewRequestWithContext(t.Context(), "GET", "/pullRequest/get?pull_request_id="+prId, nil)
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.GetPRResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
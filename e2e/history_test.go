@@ -0,0 +1,133 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"testing"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPRHistory проверяет, что создание, переназначение и закрытие PR'а записываются в его историю
+// с операцией и выполнившим её из заголовка X-Actor
+func TestPRHistory(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	authorId := uuid.NewString()
+	members := []*models.Member{
+		{
+			Id:       authorId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		},
+	}
+	for range 3 {
+		members = append(members, &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		})
+	}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    gofakeit.Name() + uuid.NewString(),
+		Members: members,
+	})
+	require.Equal(t, 201, code)
+
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+	require.Equal(t, 201, code)
+	require.Len(t, response.PR.Reviewers, 2)
+	prId := response.PR.Id
+
+	// 1. Создание: PR и два назначения
+	history, code := getPRHistory(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Len(t, history.Events, 3)
+	require.Equal(t, models.EventCreated, history.Events[0].Kind)
+	require.Equal(t, string(models.StatusOpen), history.Events[0].To)
+	assigned := make([]string, 0, 2)
+	for _, event := range history.Events {
+		require.Equal(t, models.TriggerCreate, event.Reason)
+		require.Empty(t, event.Actor)
+		if event.Kind == models.EventAssigned {
+			assigned = append(assigned, event.UserId)
+		}
+	}
+	require.ElementsMatch(t, response.PR.Reviewers, assigned)
+
+	// 2. Переназначение от имени X-Actor: снятие старого ревьювера и назначение нового
+	oldReviewerId := response.PR.Reviewers[0]
+	code = postWithActor(t, st, "/pullRequest/reassign", "ci-bot", &dto.ReassignPRRequest{
+		PullRequestID: prId,
+		OldReviewerID: oldReviewerId,
+	})
+	require.Equal(t, 200, code)
+
+	history, code = getPRHistory(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Len(t, history.Events, 5)
+	unassigned, reassigned := history.Events[3], history.Events[4]
+	require.Equal(t, models.EventUnassigned, unassigned.Kind)
+	require.Equal(t, oldReviewerId, unassigned.UserId)
+	require.Equal(t, models.EventAssigned, reassigned.Kind)
+	require.NotEqual(t, oldReviewerId, reassigned.UserId)
+	for _, event := range history.Events[3:] {
+		require.Equal(t, models.TriggerReassign, event.Reason)
+		require.Equal(t, "ci-bot", event.Actor)
+	}
+
+	// 3. Закрытие: смена статуса и снятие обоих ревьюверов, следы прежних ревьюверов остаются в истории
+	_, code = changePRStatus(t, st, "/pullRequest/close", prId)
+	require.Equal(t, 200, code)
+
+	history, code = getPRHistory(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Len(t, history.Events, 8)
+	require.Equal(t, models.EventStatusChanged, history.Events[5].Kind)
+	require.Equal(t, string(models.StatusOpen), history.Events[5].From)
+	require.Equal(t, string(models.StatusClosed), history.Events[5].To)
+	for _, event := range history.Events[5:] {
+		require.Equal(t, models.TriggerClose, event.Reason)
+	}
+	require.Equal(t, models.EventUnassigned, history.Events[6].Kind)
+	require.Equal(t, models.EventUnassigned, history.Events[7].Kind)
+
+	// 4. Несуществующий PR
+	_, code = getPRHistory(t, st, uuid.NewString())
+	require.Equal(t, 404, code)
+}
+
+func getPRHistory(t *testing.T, st *Suite, prId string) (*dto.PRHistoryResponse, int) {
+	httpReq := httptest.NewRequestWithContext(t.Context(), "GET", "/pullRequest/history?pull_request_id="+prId, nil)
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.PRHistoryResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+// postWithActor отправляет POST-запрос от имени actor и возвращает код ответа
+func postWithActor(t *testing.T, st *Suite, path string, actor string, reqBody any) int {
+	body, _ := json.Marshal(reqBody)
+	httpReq := httptest.NewRequestWithContext(t.Context(), "POST", path, bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Actor", actor)
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	return recorder.Result().StatusCode
+}
@@ -0,0 +1,152 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListPRs проверяет фильтры, поиск по названию, сортировку и курсорную пагинацию списка PR'ов
+func TestListPRs(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	teamName := gofakeit.Name() + uuid.NewString()
+	authorId := uuid.NewString()
+	reviewerId := uuid.NewString()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name: teamName,
+		Members: []*models.Member{
+			{Id: authorId, Username: gofakeit.Name() + uuid.NewString(), IsActive: true},
+			{Id: reviewerId, Username: gofakeit.Name() + uuid.NewString(), IsActive: true},
+		},
+	})
+	require.Equal(t, 201, code)
+
+	// PR'ы создаются по порядку, последний - черновик
+	token := uuid.NewString()
+	titles := []string{"alpha " + token, "beta " + token, "gamma " + token, "delta " + token}
+	prIds := make([]string, 0, len(titles))
+	for i, title := range titles {
+		response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{
+			AuthorID: authorId,
+			Draft:    i == len(titles)-1,
+		})
+		require.Equal(t, 201, code)
+		_, code = updatePR(t, st, &dto.UpdatePRRequest{PullRequestID: response.PR.Id, Title: &title})
+		require.Equal(t, 200, code)
+		prIds = append(prIds, response.PR.Id)
+	}
+	_, code = mergePR(t, st, &dto.MergePRRequest{PullRequestID: prIds[0]})
+	require.Equal(t, 200, code)
+
+	ids := func(prs []*models.PullRequest) []string {
+		result := make([]string, 0, len(prs))
+		for _, pr := range prs {
+			result = append(result, pr.Id)
+		}
+		return result
+	}
+
+	// 1. По умолчанию - от новых к старым
+	list, code := listPRs(t, st, url.Values{"team_name": {teamName}})
+	require.Equal(t, 200, code)
+	require.Equal(t, []string{prIds[3], prIds[2], prIds[1], prIds[0]}, ids(list.PullRequests))
+	require.Empty(t, list.NextCursor)
+
+	// 2. Страницы по курсору не пересекаются и покрывают весь список
+	query := url.Values{"team_name": {teamName}, "order": {"asc"}, "limit": {"3"}}
+	list, code = listPRs(t, st, query)
+	require.Equal(t, 200, code)
+	require.Equal(t, prIds[:3], ids(list.PullRequests))
+	require.NotEmpty(t, list.NextCursor)
+
+	query.Set("cursor", list.NextCursor)
+	list, code = listPRs(t, st, query)
+	require.Equal(t, 200, code)
+	require.Equal(t, prIds[3:], ids(list.PullRequests))
+	require.Empty(t, list.NextCursor)
+
+	// 3. Курсор другой сортировки и испорченный курсор отклоняются
+	query.Set("order", "desc")
+	_, code = listPRs(t, st, query)
+	require.Equal(t, 400, code)
+	_, code = listPRs(t, st, url.Values{"cursor": {"garbage"}})
+	require.Equal(t, 400, code)
+
+	// 4. Фильтры
+	list, code = listPRs(t, st, url.Values{"team_name": {teamName}, "status": {"draft,merged"}})
+	require.Equal(t, 200, code)
+	require.ElementsMatch(t, []string{prIds[0], prIds[3]}, ids(list.PullRequests))
+
+	list, code = listPRs(t, st, url.Values{"reviewer_id": {reviewerId}, "status": {"OPEN"}})
+	require.Equal(t, 200, code)
+	require.ElementsMatch(t, []string{prIds[1], prIds[2]}, ids(list.PullRequests))
+
+	list, code = listPRs(t, st, url.Values{"author_id": {authorId}, "q": {"GAMMA " + token}})
+	require.Equal(t, 200, code)
+	require.Equal(t, []string{prIds[2]}, ids(list.PullRequests))
+
+	list, code = listPRs(t, st, url.Values{"q": {"%" + token}})
+	require.Equal(t, 200, code)
+	require.Empty(t, list.PullRequests)
+
+	list, code = listPRs(t, st, url.Values{
+		"team_name":    {teamName},
+		"created_from": {time.Now().Add(time.Hour).Format(time.RFC3339)},
+	})
+	require.Equal(t, 200, code)
+	require.Empty(t, list.PullRequests)
+
+	// 5. Сортировка по merged_at - только смерженные, по названию - по алфавиту
+	list, code = listPRs(t, st, url.Values{"team_name": {teamName}, "sort": {"merged_at"}})
+	require.Equal(t, 200, code)
+	require.Equal(t, []string{prIds[0]}, ids(list.PullRequests))
+	require.NotNil(t, list.PullRequests[0].MergedAt)
+
+	list, code = listPRs(t, st, url.Values{"team_name": {teamName}, "sort": {"title"}, "order": {"asc"}, "limit": {"2"}})
+	require.Equal(t, 200, code)
+	require.Equal(t, []string{prIds[0], prIds[1]}, ids(list.PullRequests))
+	list, code = listPRs(t, st, url.Values{"team_name": {teamName}, "sort": {"title"}, "order": {"asc"}, "limit": {"2"}, "cursor": {list.NextCursor}})
+	require.Equal(t, 200, code)
+	require.Equal(t, []string{prIds[3], prIds[2]}, ids(list.PullRequests))
+
+	// 6. Неверные параметры
+	for _, bad := range []url.Values{
+		{"status": {"UNKNOWN"}},
+		{"author_id": {"not-uuid"}},
+		{"need_more_reviewers": {"maybe"}},
+		{"created_to": {"yesterday"}},
+		{"sort": {"author"}},
+		{"order": {"up"}},
+		{"limit": {"0"}},
+		{"limit": {"101"}},
+	} {
+		_, code = listPRs(t, st, bad)
+		require.Equal(t, 400, code, bad.Encode())
+	}
+}
+
+func listPRs(t *testing.T, st *Suite, query url.Values) (*dto.ListPRsResponse, int) {
+	httpReq := httptest.NewRequestWithContext(t.Context(), "GET", "/pullRequest/list?"+query.Encode(), nil)
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.ListPRsResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
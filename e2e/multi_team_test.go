@@ -0,0 +1,112 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"slices"
+	"testing"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMultiTeam проверяет, что пользователь может состоять в нескольких командах,
+// а ревьюверы подбираются из команды PR'а: указанной при создании или основной команды автора
+func TestMultiTeam(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	newMember := func() *models.Member {
+		return &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		}
+	}
+	author := newMember()
+	firstName, secondName := gofakeit.Name()+uuid.NewString(), gofakeit.Name()+uuid.NewString()
+	firstReviewer, secondReviewer := newMember(), newMember()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{Name: firstName, Members: []*models.Member{author, firstReviewer}})
+	require.Equal(t, 201, code)
+	_, code = createTeam(t, st, &dto.AddTeamRequest{Name: secondName, Members: []*models.Member{author, secondReviewer}})
+	require.Equal(t, 201, code)
+
+	// 1. Автор состоит в обеих командах, основная - первая
+	for _, name := range []string{firstName, secondName} {
+		team, code := getTeam(t, st, name)
+		require.Equal(t, 200, code)
+		require.True(t, slices.ContainsFunc(team.Members, func(m *models.Member) bool { return m.Id == author.Id }))
+	}
+
+	// 2. По умолчанию PR открывается в основной команде автора
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+	require.Equal(t, 201, code)
+	require.Equal(t, firstName, response.PR.TeamName)
+	require.Equal(t, []string{firstReviewer.Id}, response.PR.Reviewers)
+
+	details, code := getPR(t, st, response.PR.Id)
+	require.Equal(t, 200, code)
+	require.Equal(t, firstName, details.Author.TeamName)
+	require.ElementsMatch(t, []string{firstName, secondName}, details.Author.Teams)
+
+	// 3. Явно указанная команда
+	response, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id, TeamName: secondName})
+	require.Equal(t, 201, code)
+	require.Equal(t, secondName, response.PR.TeamName)
+	require.Equal(t, []string{secondReviewer.Id}, response.PR.Reviewers)
+	secondPrId := response.PR.Id
+
+	// 4. В чужой команде PR открыть нельзя
+	_, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: firstReviewer.Id, TeamName: secondName})
+	require.Equal(t, 409, code)
+
+	// 5. Смена основной команды меняет команду по умолчанию
+	user, code := setPrimaryTeam(t, st, &dto.SetPrimaryTeamRequest{UserId: author.Id, TeamName: secondName})
+	require.Equal(t, 200, code)
+	require.Equal(t, secondName, user.User.TeamName)
+	response, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+	require.Equal(t, 201, code)
+	require.Equal(t, secondName, response.PR.TeamName)
+
+	_, code = setPrimaryTeam(t, st, &dto.SetPrimaryTeamRequest{UserId: firstReviewer.Id, TeamName: secondName})
+	require.Equal(t, 404, code)
+
+	// 6. Исключение из одной команды не снимает с ревью PR'ов другой
+	_, code = addReviewers(t, st, &dto.AddReviewersRequest{PullRequestID: secondPrId, ReviewerIDs: []string{firstReviewer.Id}})
+	require.Equal(t, 200, code)
+	thirdName := gofakeit.Name() + uuid.NewString()
+	_, code = createTeam(t, st, &dto.AddTeamRequest{Name: thirdName, Members: []*models.Member{firstReviewer}})
+	require.Equal(t, 201, code)
+	_, code = changeTeamMembers(t, st, "POST", "/team/removeMembers", &dto.RemoveTeamMembersRequest{
+		Name:    thirdName,
+		UserIds: []string{firstReviewer.Id},
+	})
+	require.Equal(t, 200, code)
+
+	details, code = getPR(t, st, secondPrId)
+	require.Equal(t, 200, code)
+	require.Contains(t, details.PR.Reviewers, firstReviewer.Id)
+}
+
+func setPrimaryTeam(t *testing.T, st *Suite, reqBody *dto.SetPrimaryTeamRequest) (*dto.SetPrimaryTeamResponse, int) {
+	body, err := json.Marshal(reqBody)
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), "POST", "/users/setPrimaryTeam", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	var res dto.SetPrimaryTeamResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
@@ -0,0 +1,171 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"slices"
+	"testing"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOffboardUser проверяет увольнение пользователя: переназначение его ревью, политики для его открытых PR'ов
+// и сохранение истории
+func TestOffboardUser(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	newMember := func() *models.Member {
+		return &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		}
+	}
+	teamName := gofakeit.Name() + uuid.NewString()
+	author, leaver, third, heir := newMember(), newMember(), newMember(), newMember()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    teamName,
+		Members: []*models.Member{author, leaver, third},
+	})
+	require.Equal(t, 201, code)
+
+	// 1. Ревью уволенного переходят к оставшемуся в команде, он пропадает из состава команды
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+	require.Equal(t, 201, code)
+	require.ElementsMatch(t, []string{leaver.Id, third.Id}, response.PR.Reviewers)
+	prId := response.PR.Id
+	_, code = mergePR(t, st, &dto.MergePRRequest{PullRequestID: prId})
+	require.Equal(t, 200, code)
+	response, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: third.Id})
+	require.Equal(t, 201, code)
+	openPrId := response.PR.Id
+
+	res, code := offboardUser(t, st, &dto.OffboardUserRequest{UserId: leaver.Id})
+	require.Equal(t, 200, code)
+	require.Equal(t, models.OffboardKeep, res.Policy)
+	require.NotNil(t, res.User.OffboardedAt)
+	require.False(t, res.User.IsActive)
+	require.Empty(t, res.User.Teams)
+	require.Equal(t, []string{openPrId}, res.ReassignedPRs)
+
+	team, code := getTeam(t, st, teamName)
+	require.Equal(t, 200, code)
+	require.False(t, slices.ContainsFunc(team.Members, func(m *models.Member) bool { return m.Id == leaver.Id }))
+
+	pr, code := getPR(t, st, openPrId)
+	require.Equal(t, 200, code)
+	require.Equal(t, []string{author.Id}, pr.PR.Reviewers)
+	history, code := getPRHistory(t, st, openPrId)
+	require.Equal(t, 200, code)
+	require.True(t, slices.ContainsFunc(history.Events, func(e *models.PREvent) bool {
+		return e.Kind == models.EventUnassigned && e.UserId == leaver.Id && e.Reason == models.TriggerOffboarding
+	}))
+
+	// 2. Назначение в смерженном PR'е сохраняется
+	pr, code = getPR(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Contains(t, pr.PR.Reviewers, leaver.Id)
+
+	_, code = offboardUser(t, st, &dto.OffboardUserRequest{UserId: leaver.Id})
+	require.Equal(t, 409, code)
+
+	// Уволенного нельзя снова активировать или добавить в команду
+	isActive := true
+	_, code = setIsActive(t, st, &dto.SetIsActiveRequest{UserId: leaver.Id, IsActive: &isActive})
+	require.Equal(t, 409, code)
+	_, code = bulkSetIsActive(t, st, &dto.BulkSetIsActiveRequest{UserIds: []string{author.Id, leaver.Id}, IsActive: &isActive})
+	require.Equal(t, 409, code)
+	_, code = changeTeamMembers(t, st, "POST", "/team/addMembers", &dto.TeamMembersRequest{
+		Name:    teamName,
+		Members: []*models.Member{leaver},
+	})
+	require.Equal(t, 409, code)
+	_, code = createTeam(t, st, &dto.AddTeamRequest{
+		Name:    gofakeit.Name() + uuid.NewString(),
+		Members: []*models.Member{leaver},
+	})
+	require.Equal(t, 409, code)
+	team, code = getTeam(t, st, teamName)
+	require.Equal(t, 200, code)
+	require.False(t, slices.ContainsFunc(team.Members, func(m *models.Member) bool { return m.Id == leaver.Id }))
+	pr, code = getPR(t, st, prId)
+	require.Equal(t, 200, code)
+	require.True(t, slices.ContainsFunc(pr.Reviewers, func(u *models.User) bool { return u.Id == leaver.Id && !u.IsActive }))
+
+	// 3. CLOSE закрывает открытые PR'ы уволенного
+	res, code = offboardUser(t, st, &dto.OffboardUserRequest{UserId: third.Id, Policy: models.OffboardClose})
+	require.Equal(t, 200, code)
+	require.Equal(t, []string{openPrId}, res.AuthoredPRs)
+	pr, code = getPR(t, st, openPrId)
+	require.Equal(t, 200, code)
+	require.Equal(t, models.StatusClosed, pr.PR.Status)
+	require.Empty(t, pr.PR.Reviewers)
+
+	// 4. TRANSFER передаёт открытые PR'ы другому пользователю
+	_, code = changeTeamMembers(t, st, "POST", "/team/addMembers", &dto.TeamMembersRequest{
+		Name:    teamName,
+		Members: []*models.Member{heir},
+	})
+	require.Equal(t, 200, code)
+	response, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+	require.Equal(t, 201, code)
+	require.Equal(t, []string{heir.Id}, response.PR.Reviewers)
+	transferredId := response.PR.Id
+
+	_, code = offboardUser(t, st, &dto.OffboardUserRequest{UserId: author.Id, Policy: models.OffboardTransfer, TransferTo: uuid.NewString()})
+	require.Equal(t, 404, code)
+	_, code = offboardUser(t, st, &dto.OffboardUserRequest{UserId: author.Id, Policy: models.OffboardTransfer})
+	require.Equal(t, 400, code)
+	_, code = offboardUser(t, st, &dto.OffboardUserRequest{UserId: author.Id, Policy: models.OffboardTransfer, TransferTo: leaver.Id})
+	require.Equal(t, 409, code)
+	inactive := newMember()
+	inactive.IsActive = false
+	_, code = changeTeamMembers(t, st, "POST", "/team/addMembers", &dto.TeamMembersRequest{
+		Name:    teamName,
+		Members: []*models.Member{inactive},
+	})
+	require.Equal(t, 200, code)
+	_, code = offboardUser(t, st, &dto.OffboardUserRequest{UserId: author.Id, Policy: models.OffboardTransfer, TransferTo: inactive.Id})
+	require.Equal(t, 409, code)
+	pr, code = getPR(t, st, transferredId)
+	require.Equal(t, 200, code)
+	require.Equal(t, author.Id, pr.PR.AuthorId)
+
+	res, code = offboardUser(t, st, &dto.OffboardUserRequest{UserId: author.Id, Policy: models.OffboardTransfer, TransferTo: heir.Id})
+	require.Equal(t, 200, code)
+	require.Equal(t, []string{transferredId}, res.AuthoredPRs)
+	pr, code = getPR(t, st, transferredId)
+	require.Equal(t, 200, code)
+	require.Equal(t, heir.Id, pr.PR.AuthorId)
+	require.Equal(t, models.StatusOpen, pr.PR.Status)
+	require.NotContains(t, pr.PR.Reviewers, heir.Id)
+
+	// 5. Несуществующий пользователь
+	_, code = offboardUser(t, st, &dto.OffboardUserRequest{UserId: uuid.NewString()})
+	require.Equal(t, 404, code)
+}
+
+func offboardUser(t *testing.T, st *Suite, reqBody *dto.OffboardUserRequest) (*dto.OffboardUserResponse, int) {
+	body, err := json.Marshal(reqBody)
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), "POST", "/users/offboard", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	var res dto.OffboardUserResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
@@ -0,0 +1,272 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"testing"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreatePROwners проверяет, что владельцы изменённых файлов назначаются ревьюверами раньше команды автора
+func TestCreatePROwners(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	ownerTeamName := gofakeit.Name() + uuid.NewString()
+	ownerId := uuid.NewString()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name: ownerTeamName,
+		Members: []*models.Member{
+			{
+				Id:       ownerId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+		},
+	})
+	require.Equal(t, 201, code)
+
+	authorId := uuid.NewString()
+	teamMemberIds := []string{uuid.NewString(), uuid.NewString()}
+	_, code = createTeam(t, st, &dto.AddTeamRequest{
+		Name: gofakeit.Name() + uuid.NewString(),
+		Members: []*models.Member{
+			{
+				Id:       authorId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+			{
+				Id:       teamMemberIds[0],
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+			{
+				Id:       teamMemberIds[1],
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+		},
+	})
+	require.Equal(t, 201, code)
+
+	dir := uuid.NewString()
+	rule, code := addOwnershipRule(t, st, &dto.AddOwnershipRuleRequest{
+		Pattern: "/" + dir + "/",
+		Teams:   []string{ownerTeamName},
+	})
+	require.Equal(t, 201, code)
+	require.Equal(t, []string{ownerTeamName}, rule.Rule.Teams)
+
+	// 1. PR затрагивает файлы владельца - один ревьювер от владельца, второй из команды автора
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId, Files: []string{dir + "/main.go", "README.md"}})
+	require.Equal(t, 201, code)
+	require.Len(t, response.PR.Reviewers, 2)
+	require.Contains(t, response.PR.Reviewers, ownerId)
+	for _, a := range response.PR.Assignments {
+		if a.UserId == ownerId {
+			require.Equal(t, models.SourceOwner, a.Source)
+			require.Equal(t, ownerTeamName, a.TeamName)
+		} else {
+			require.Contains(t, teamMemberIds, a.UserId)
+			require.Equal(t, models.SourceTeam, a.Source)
+		}
+	}
+
+	// 2. PR не затрагивает файлы владельца - ревьюверы только из команды автора
+	response, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId, Files: []string{"other/" + dir + "/main.go"}})
+	require.Equal(t, 201, code)
+	require.ElementsMatch(t, teamMemberIds, response.PR.Reviewers)
+
+	// 3. После удаления правила владелец больше не назначается
+	code = deleteOwnershipRule(t, st, rule.Rule.Id)
+	require.Equal(t, 204, code)
+	code = deleteOwnershipRule(t, st, rule.Rule.Id)
+	require.Equal(t, 404, code)
+
+	response, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId, Files: []string{dir + "/main.go"}})
+	require.Equal(t, 201, code)
+	require.ElementsMatch(t, teamMemberIds, response.PR.Reviewers)
+}
+
+// TestReassignKeepsOwnerCoverage проверяет, что при замене ревьювера из команды автора не назначается второй владелец,
+// если группа владельцев уже покрыта
+func TestReassignKeepsOwnerCoverage(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	newMember := func() *models.Member {
+		return &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		}
+	}
+	ownerTeamName := gofakeit.Name() + uuid.NewString()
+	owners := []*models.Member{newMember(), newMember()}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{Name: ownerTeamName, Members: owners})
+	require.Equal(t, 201, code)
+	author := newMember()
+	teamMembers := []*models.Member{newMember(), newMember()}
+	_, code = createTeam(t, st, &dto.AddTeamRequest{
+		Name:    gofakeit.Name() + uuid.NewString(),
+		Members: append([]*models.Member{author}, teamMembers...),
+	})
+	require.Equal(t, 201, code)
+
+	dir := uuid.NewString()
+	_, code = addOwnershipRule(t, st, &dto.AddOwnershipRuleRequest{
+		Pattern: "/" + dir + "/",
+		Teams:   []string{ownerTeamName},
+	})
+	require.Equal(t, 201, code)
+
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id, Files: []string{dir + "/main.go"}})
+	require.Equal(t, 201, code)
+	require.Len(t, response.PR.Reviewers, 2)
+	var ownerReviewer, teamReviewer string
+	for _, a := range response.PR.Assignments {
+		if a.Source == models.SourceOwner {
+			ownerReviewer = a.UserId
+		} else {
+			teamReviewer = a.UserId
+		}
+	}
+	require.Contains(t, memberIdsOf(owners), ownerReviewer)
+	require.Contains(t, memberIdsOf(teamMembers), teamReviewer)
+
+	body, code := reassignPR(t, st, &dto.ReassignPRRequest{PullRequestID: response.PR.Id, OldReviewerID: teamReviewer})
+	require.Equal(t, 200, code)
+	var reassigned dto.ReassignPRResponse
+	require.NoError(t, json.Unmarshal(body, &reassigned))
+	require.Contains(t, memberIdsOf(teamMembers), reassigned.ReplacedBy)
+	require.NotEqual(t, teamReviewer, reassigned.ReplacedBy)
+	require.Contains(t, reassigned.PR.Reviewers, ownerReviewer)
+}
+
+func memberIdsOf(members []*models.Member) []string {
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.Id)
+	}
+	return ids
+}
+
+// TestImportCodeowners проверяет импорт правил из файла CODEOWNERS
+func TestImportCodeowners(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	teamName := gofakeit.Name() + uuid.NewString()
+	userId := uuid.NewString()
+	username := uuid.NewString()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name: teamName,
+		Members: []*models.Member{
+			{
+				Id:       userId,
+				Username: username,
+				IsActive: true,
+			},
+		},
+	})
+	require.Equal(t, 201, code)
+
+	// 1. Неизвестный владелец
+	_, code = importCodeowners(t, st, "*.go @"+uuid.NewString())
+	require.Equal(t, 404, code)
+
+	// 2. Владельцы ищутся среди команд, затем среди пользователей
+	content := fmt.Sprintf("# comment\n*.go @org/%s\n\n/docs/ @%s # docs\n", teamName, username)
+	res, code := importCodeowners(t, st, content)
+	require.Equal(t, 200, code)
+	require.Len(t, res.Rules, 2)
+	require.Equal(t, "*.go", res.Rules[0].Pattern)
+	require.Equal(t, []string{teamName}, res.Rules[0].Teams)
+	require.Empty(t, res.Rules[0].Users)
+	require.Equal(t, "/docs/", res.Rules[1].Pattern)
+	require.Empty(t, res.Rules[1].Teams)
+	require.Equal(t, []string{userId}, res.Rules[1].Users)
+
+	list, code := getOwnershipRules(t, st)
+	require.Equal(t, 200, code)
+	require.Equal(t, res.Rules, list.Rules)
+
+	// 3. Повторный импорт заменяет правила
+	res, code = importCodeowners(t, st, "/"+uuid.NewString()+"/ @"+username)
+	require.Equal(t, 200, code)
+	require.Len(t, res.Rules, 1)
+}
+
+func addOwnershipRule(t *testing.T, st *Suite, reqBody *dto.AddOwnershipRuleRequest) (*dto.AddOwnershipRuleResponse, int) {
+	body, err := json.Marshal(reqBody)
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), "POST", "/ownership/add", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	var res dto.AddOwnershipRuleResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+func deleteOwnershipRule(t *testing.T, st *Suite, id string) int {
+	body, err := json.Marshal(&dto.DeleteOwnershipRuleRequest{Id: id})
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), "POST", "/ownership/delete", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	return recorder.Result().StatusCode
+}
+
+func importCodeowners(t *testing.T, st *Suite, content string) (*dto.OwnershipRulesResponse, int) {
+	body, err := json.Marshal(&dto.ImportCodeownersRequest{Content: content})
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), "POST", "/ownership/import", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	var res dto.OwnershipRulesResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+func getOwnershipRules(t *testing.T, st *Suite) (*dto.OwnershipRulesResponse, int) {
+	req := httptest.NewRequestWithContext(t.Context(), "GET", "/ownership/list", nil)
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	var res dto.OwnershipRulesResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"net/http/httptest"
 	"pr-review/internal/http/dto"
 	"pr-review/internal/models"
 	"pr-review/internal/usecases"
+	"pr-review/internal/utils"
+	"slices"
+	"sort"
+	"sync"
 	"testing"
 
 	"github.com/brianvoe/gofakeit"
@@ -296,6 +301,24 @@ func createPR(t *testing.T, st *Suite, authorId string) (*dto.CreatePRResponse,
 	return &resp, recorder.Result().StatusCode, id, name
 }
 
+// createPRWithRequest создаёт PR, подставляя в запрос случайные id и название
+func createPRWithRequest(t *testing.T, st *Suite, reqBody *dto.CreatePRRequest) (*dto.CreatePRResponse, int) {
+	reqBody.Id = uuid.NewString()
+	reqBody.Title = gofakeit.City()
+	body, err := json.Marshal(reqBody)
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), "POST", "/pullRequest/create", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+	var resp dto.CreatePRResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &resp)
+	require.NoError(t, err)
+
+	return &resp, recorder.Result().StatusCode
+}
+
 func reassignPR(t *testing.T, st *Suite, reqBody *dto.ReassignPRRequest) ([]byte, int) {
 	body, err := json.Marshal(reqBody)
 	require.NoError(t, err)
@@ -332,3 +355,932 @@ func statistics(t *testing.T, st *Suite) ([]byte, int) {
 
 	return recorder.Body.Bytes(), recorder.Result().StatusCode
 }
+
+// TestCreatePRRoundRobin проверяет, что при стратегии ROUND_ROBIN ревьюверы назначаются по кругу
+func TestCreatePRRoundRobin(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	authorId := uuid.NewString()
+	reviewerIds := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+	sort.Strings(reviewerIds)
+
+	members := []*models.Member{
+		{
+			Id:       authorId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		},
+	}
+	for _, id := range reviewerIds {
+		members = append(members, &models.Member{
+			Id:       id,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		})
+	}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:             gofakeit.Name() + uuid.NewString(),
+		Members:          members,
+		ReviewerStrategy: models.StrategyRoundRobin,
+	})
+	require.Equal(t, 201, code)
+
+	expected := [][]string{
+		{reviewerIds[0], reviewerIds[1]},
+		{reviewerIds[2], reviewerIds[0]},
+		{reviewerIds[1], reviewerIds[2]},
+	}
+	for _, reviewers := range expected {
+		response, code, _, _ := createPR(t, st, authorId)
+		require.Equal(t, 201, code)
+		require.ElementsMatch(t, reviewers, response.PR.Reviewers)
+	}
+}
+
+// TestAddTeamUnknownStrategy проверяет, что нельзя создать команду с неизвестной стратегией выбора ревьюверов
+// TestCreatePRWeightedRandom проверяет, что WEIGHTED_RANDOM выбирает ревьювера с весом, обратно пропорциональным
+// количеству его открытых ревью: загруженный участник с весом 1/21 против 1 у свободного выбирается редко
+func TestCreatePRWeightedRandom(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	newMember := func() *models.Member {
+		return &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		}
+	}
+	author, busy, idle := newMember(), newMember(), newMember()
+	teamName := gofakeit.Name() + uuid.NewString()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:             teamName,
+		Members:          []*models.Member{author, busy, idle},
+		ReviewerStrategy: models.StrategyWeightedRandom,
+	})
+	require.Equal(t, 201, code)
+	reviewersPerPR := 1
+	_, code = updateTeamSettings(t, st, &dto.TeamSettingsRequest{Name: teamName, ReviewersPerPR: &reviewersPerPR})
+	require.Equal(t, 200, code)
+
+	// загружаем busy 20 открытыми ревью PR'ов другой команды, где он единственный кандидат
+	otherAuthor := newMember()
+	_, code = createTeam(t, st, &dto.AddTeamRequest{
+		Name:    gofakeit.Name() + uuid.NewString(),
+		Members: []*models.Member{otherAuthor, busy},
+	})
+	require.Equal(t, 201, code)
+	for range 20 {
+		response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: otherAuthor.Id})
+		require.Equal(t, 201, code)
+		require.Equal(t, []string{busy.Id}, response.PR.Reviewers)
+	}
+
+	// каждый PR закрывается, чтобы нагрузка не менялась между попытками: busy выбирается с вероятностью 1/22
+	const attempts = 60
+	picked := make(map[string]int)
+	for range attempts {
+		response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+		require.Equal(t, 201, code)
+		require.Len(t, response.PR.Reviewers, 1)
+		picked[response.PR.Reviewers[0]]++
+
+		_, code = changePRStatus(t, st, "/pullRequest/close", response.PR.Id)
+		require.Equal(t, 200, code)
+	}
+	require.Equal(t, attempts, picked[busy.Id]+picked[idle.Id])
+	require.Less(t, picked[busy.Id], attempts/4)
+}
+
+func TestAddTeamUnknownStrategy(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	data, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:             gofakeit.Name() + uuid.NewString(),
+		ReviewerStrategy: "UNKNOWN",
+	})
+	require.Equal(t, 400, code)
+
+	var res dto.ErrorResponse
+	err := json.Unmarshal(data, &res)
+	require.NoError(t, err)
+	require.Equal(t, dto.ErrUnknownReviewerStrategy, &res)
+}
+
+// TestCreatePRLeastLoaded проверяет, что при стратегии LEAST_LOADED первым назначается наименее загруженный участник
+func TestCreatePRLeastLoaded(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	authorId := uuid.NewString()
+	members := []*models.Member{
+		{
+			Id:       authorId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		},
+	}
+	for range 3 {
+		members = append(members, &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		})
+	}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:             gofakeit.Name() + uuid.NewString(),
+		Members:          members,
+		ReviewerStrategy: models.StrategyLeastLoaded,
+	})
+	require.Equal(t, 201, code)
+
+	first, code, _, _ := createPR(t, st, authorId)
+	require.Equal(t, 201, code)
+	require.Len(t, first.PR.Reviewers, 2)
+
+	var idleId string
+	for _, m := range members[1:] {
+		if !slices.Contains(first.PR.Reviewers, m.Id) {
+			idleId = m.Id
+		}
+	}
+
+	second, code, _, _ := createPR(t, st, authorId)
+	require.Equal(t, 201, code)
+	require.Len(t, second.PR.Reviewers, 2)
+	require.Contains(t, second.PR.Reviewers, idleId)
+}
+
+// TestCreatePRLeastLoadedConcurrent проверяет, что параллельные создания PR'ов не выбирают одного и того же свободного
+// ревьювера: кандидаты блокируются, поэтому нагрузка остаётся равномерной
+func TestCreatePRLeastLoadedConcurrent(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	authorId := uuid.NewString()
+	members := []*models.Member{
+		{
+			Id:       authorId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		},
+	}
+	for range 4 {
+		members = append(members, &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		})
+	}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:             gofakeit.Name() + uuid.NewString(),
+		Members:          members,
+		ReviewerStrategy: models.StrategyLeastLoaded,
+	})
+	require.Equal(t, 201, code)
+
+	// 6 PR'ов по 2 ревьювера на 4 участников - ровно по 3 ревью на каждого
+	const prsCount = 6
+	results := make([]*dto.CreatePRResponse, prsCount)
+	codes := make([]int, prsCount)
+	var wg sync.WaitGroup
+	for i := range prsCount {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, _ := json.Marshal(&dto.CreatePRRequest{
+				Id:       uuid.NewString(),
+				Title:    gofakeit.City(),
+				AuthorID: authorId,
+			})
+			req := httptest.NewRequestWithContext(t.Context(), "POST", "/pullRequest/create", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+
+			st.srv.TestReq(req, recorder)
+
+			var resp dto.CreatePRResponse
+			assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+			results[i], codes[i] = &resp, recorder.Result().StatusCode
+		}()
+	}
+	wg.Wait()
+
+	loads := make(map[string]int)
+	for i := range prsCount {
+		require.Equal(t, 201, codes[i])
+		require.Len(t, results[i].PR.Reviewers, 2)
+		for _, reviewerId := range results[i].PR.Reviewers {
+			loads[reviewerId]++
+		}
+	}
+	for _, m := range members[1:] {
+		require.Equal(t, 3, loads[m.Id])
+	}
+}
+
+// TestCreatePRFallbackTeam проверяет, что недостающие ревьюверы добираются из резервной команды
+func TestCreatePRFallbackTeam(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	fallbackTeamName := gofakeit.Name() + uuid.NewString()
+	fallbackMemberId := uuid.NewString()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name: fallbackTeamName,
+		Members: []*models.Member{
+			{
+				Id:       fallbackMemberId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+		},
+	})
+	require.Equal(t, 201, code)
+
+	teamName := gofakeit.Name() + uuid.NewString()
+	authorId := uuid.NewString()
+	teamMemberId := uuid.NewString()
+	_, code = createTeam(t, st, &dto.AddTeamRequest{
+		Name: teamName,
+		Members: []*models.Member{
+			{
+				Id:       authorId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+			{
+				Id:       teamMemberId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+		},
+	})
+	require.Equal(t, 201, code)
+
+	_, code = updateTeamSettings(t, st, &dto.TeamSettingsRequest{
+		Name:          teamName,
+		FallbackTeams: &[]string{fallbackTeamName},
+	})
+	require.Equal(t, 200, code)
+
+	response, code, _, _ := createPR(t, st, authorId)
+	require.Equal(t, 201, code)
+	require.ElementsMatch(t, []string{teamMemberId, fallbackMemberId}, response.PR.Reviewers)
+	for _, a := range response.PR.Assignments {
+		if a.UserId == fallbackMemberId {
+			require.Equal(t, models.SourceFallback, a.Source)
+			require.Equal(t, fallbackTeamName, a.TeamName)
+		} else {
+			require.Equal(t, models.SourceTeam, a.Source)
+		}
+	}
+}
+
+// TestCreatePRLabels проверяет, что ревьюверы подбираются так, чтобы каждую метку PR'а покрывал тег хотя бы одного из них
+func TestCreatePRLabels(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	teamName := gofakeit.Name() + uuid.NewString()
+	authorId := uuid.NewString()
+	sqlId := uuid.NewString()
+	frontendId := uuid.NewString()
+	members := []*models.Member{
+		{
+			Id:       authorId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		},
+		{
+			Id:       sqlId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+			Tags:     []string{"go", "sql"},
+		},
+		{
+			Id:       frontendId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		},
+	}
+	for range 3 {
+		members = append(members, &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		})
+	}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    teamName,
+		Members: members,
+	})
+	require.Equal(t, 201, code)
+
+	// 1. Теги задаются отдельным запросом и видны в команде
+	res, code := setTags(t, st, &dto.SetTagsRequest{
+		UserId: frontendId,
+		Tags:   []string{" Frontend ", "frontend"},
+	})
+	require.Equal(t, 200, code)
+	require.Equal(t, []string{"frontend"}, res.User.Tags)
+
+	team, code := getTeam(t, st, teamName)
+	require.Equal(t, 200, code)
+	for _, m := range team.Members {
+		switch m.Id {
+		case sqlId:
+			require.Equal(t, []string{"go", "sql"}, m.Tags)
+		case frontendId:
+			require.Equal(t, []string{"frontend"}, m.Tags)
+		default:
+			require.Empty(t, m.Tags)
+		}
+	}
+
+	// 2. Обе метки покрываются, остальные участники не назначаются
+	for range 5 {
+		response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{
+			AuthorID: authorId,
+			Labels:   []string{"SQL", "frontend"},
+		})
+		require.Equal(t, 201, code)
+		require.ElementsMatch(t, []string{"sql", "frontend"}, response.PR.Labels)
+		require.ElementsMatch(t, []string{sqlId, frontendId}, response.PR.Reviewers)
+	}
+
+	// 3. Одну метку покрывает один ревьювер, второй выбирается стратегией
+	for range 5 {
+		response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{
+			AuthorID: authorId,
+			Labels:   []string{"go"},
+		})
+		require.Equal(t, 201, code)
+		require.Len(t, response.PR.Reviewers, 2)
+		require.Contains(t, response.PR.Reviewers, sqlId)
+	}
+}
+
+// TestAssignmentExplain проверяет, что каждый подбор ревьюверов записывается и воспроизводится по seed
+func TestAssignmentExplain(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	teamName := gofakeit.Name() + uuid.NewString()
+	authorId := uuid.NewString()
+	inactiveId := uuid.NewString()
+	activeIds := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+	members := []*models.Member{
+		{
+			Id:       authorId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		},
+		{
+			Id:       inactiveId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: false,
+		},
+	}
+	for _, id := range activeIds {
+		members = append(members, &models.Member{
+			Id:       id,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		})
+	}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:             teamName,
+		Members:          members,
+		ReviewerStrategy: models.StrategyRandom,
+	})
+	require.Equal(t, 201, code)
+
+	response, code, prId, _ := createPR(t, st, authorId)
+	require.Equal(t, 201, code)
+	require.Len(t, response.PR.Reviewers, 2)
+
+	// 1. Подбор при создании PR'а
+	explain, code := assignmentExplain(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Equal(t, prId, explain.PullRequestID)
+	require.Len(t, explain.Decisions, 1)
+
+	decision := explain.Decisions[0]
+	require.Equal(t, models.TriggerCreate, decision.Trigger)
+	require.Len(t, decision.Steps, 1)
+	step := decision.Steps[0]
+	require.Equal(t, models.SourceTeam, step.Source)
+	require.Equal(t, teamName, step.TeamName)
+	require.Equal(t, models.StrategyRandom, step.Strategy)
+	require.ElementsMatch(t, []*models.ExcludedCandidate{
+		{UserId: authorId, Reason: models.ExclusionAuthor},
+		{UserId: inactiveId, Reason: models.ExclusionInactive},
+	}, step.Excluded)
+	require.ElementsMatch(t, activeIds, step.Candidates)
+	require.Equal(t, step.Candidates[:2], step.Assigned)
+	require.ElementsMatch(t, response.PR.Reviewers, step.Assigned)
+
+	// порядок кандидатов воспроизводится по seed: кандидаты в порядке id, перемешанные генератором с этим seed
+	expected := slices.Clone(activeIds)
+	sort.Strings(expected)
+	utils.Shuffle(rand.New(rand.NewSource(decision.Seed)), expected)
+	require.Equal(t, expected, step.Candidates)
+
+	// 2. Подбор при переназначении
+	oldReviewerId := step.Assigned[0]
+	_, code = reassignPR(t, st, &dto.ReassignPRRequest{
+		PullRequestID: prId,
+		OldReviewerID: oldReviewerId,
+	})
+	require.Equal(t, 200, code)
+
+	explain, code = assignmentExplain(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Len(t, explain.Decisions, 2)
+
+	decision = explain.Decisions[1]
+	require.Equal(t, models.TriggerReassign, decision.Trigger)
+	require.Len(t, decision.Steps, 1)
+	step = decision.Steps[0]
+	require.ElementsMatch(t, []*models.ExcludedCandidate{
+		{UserId: authorId, Reason: models.ExclusionAuthor},
+		{UserId: inactiveId, Reason: models.ExclusionInactive},
+		{UserId: oldReviewerId, Reason: models.ExclusionReplaced},
+		{UserId: explain.Decisions[0].Steps[0].Assigned[1], Reason: models.ExclusionAlreadyAssigned},
+	}, step.Excluded)
+	require.Equal(t, []string{explain.Decisions[0].Steps[0].Candidates[2]}, step.Candidates)
+	require.Equal(t, step.Candidates, step.Assigned)
+
+	// 3. Неизвестный PR
+	_, code = assignmentExplain(t, st, uuid.NewString())
+	require.Equal(t, 404, code)
+}
+
+func assignmentExplain(t *testing.T, st *Suite, prId string) (*dto.AssignmentExplainResponse, int) {
+	req := httptest.NewRequestWithContext(t.Context(), "GET", "/pullRequest/assignmentExplain?pull_request_id="+prId, nil)
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	var res dto.AssignmentExplainResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+// TestCreatePRCapacity проверяет, что ревьюверы, достигшие ограничения открытых ревью, не назначаются,
+// а ограничение пользователя приоритетнее ограничения команды
+func TestCreatePRCapacity(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	teamName := gofakeit.Name() + uuid.NewString()
+	authorId := uuid.NewString()
+	firstId := uuid.NewString()
+	secondId := uuid.NewString()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name: teamName,
+		Members: []*models.Member{
+			{
+				Id:       authorId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+			{
+				Id:       firstId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+			{
+				Id:       secondId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			},
+		},
+	})
+	require.Equal(t, 201, code)
+
+	limit := 1
+	settings, code := updateTeamSettings(t, st, &dto.TeamSettingsRequest{
+		Name:           teamName,
+		MaxOpenReviews: &limit,
+	})
+	require.Equal(t, 200, code)
+	require.Equal(t, &limit, settings.Settings.MaxOpenReviews)
+
+	// 1. Оба ревьювера свободны
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+	require.Equal(t, 201, code)
+	require.ElementsMatch(t, []string{firstId, secondId}, response.PR.Reviewers)
+	require.Empty(t, response.PR.NeedMoreReviewersReason)
+
+	// 2. Оба ревьювера достигли ограничения команды
+	response, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+	require.Equal(t, 201, code)
+	require.Empty(t, response.PR.Reviewers)
+	require.Equal(t, models.ReasonCapacityExceeded, response.PR.NeedMoreReviewersReason)
+
+	explain, code := assignmentExplain(t, st, response.PR.Id)
+	require.Equal(t, 200, code)
+	require.Len(t, explain.Decisions, 1)
+	require.NotEmpty(t, explain.Decisions[0].Steps)
+	require.ElementsMatch(t, []*models.ExcludedCandidate{
+		{UserId: authorId, Reason: models.ExclusionAuthor},
+		{UserId: firstId, Reason: models.ExclusionAtCapacity},
+		{UserId: secondId, Reason: models.ExclusionAtCapacity},
+	}, explain.Decisions[0].Steps[0].Excluded)
+
+	// 3. Личное ограничение пользователя перекрывает ограничение команды
+	userLimit := 2
+	res, code := setMaxOpenReviews(t, st, &dto.SetMaxOpenReviewsRequest{
+		UserId:         firstId,
+		MaxOpenReviews: &userLimit,
+	})
+	require.Equal(t, 200, code)
+	require.Equal(t, &userLimit, res.User.MaxOpenReviews)
+
+	response, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+	require.Equal(t, 201, code)
+	require.Equal(t, []string{firstId}, response.PR.Reviewers)
+	require.Equal(t, models.ReasonCapacityExceeded, response.PR.NeedMoreReviewersReason)
+
+	// 4. Ограничение вне диапазона отклоняется
+	userLimit = -1
+	_, code = setMaxOpenReviews(t, st, &dto.SetMaxOpenReviewsRequest{
+		UserId:         firstId,
+		MaxOpenReviews: &userLimit,
+	})
+	require.Equal(t, 400, code)
+}
+
+// TestReviewPR проверяет сохранение решений ревьюверов и запрет merge без нужного количества одобрений
+func TestReviewPR(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	teamName := gofakeit.Name() + uuid.NewString()
+	authorId := uuid.NewString()
+	members := []*models.Member{
+		{
+			Id:       authorId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		},
+	}
+	for range 2 {
+		members = append(members, &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		})
+	}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    teamName,
+		Members: members,
+	})
+	require.Equal(t, 201, code)
+
+	requiredApprovals := 2
+	settings, code := updateTeamSettings(t, st, &dto.TeamSettingsRequest{
+		Name:              teamName,
+		RequiredApprovals: &requiredApprovals,
+	})
+	require.Equal(t, 200, code)
+	require.Equal(t, 2, settings.Settings.RequiredApprovals)
+
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+	require.Equal(t, 201, code)
+	require.Len(t, response.PR.Reviewers, 2)
+	prId := response.PR.Id
+	first, second := response.PR.Reviewers[0], response.PR.Reviewers[1]
+
+	// 1. Решение может оставить только назначенный ревьювер
+	_, code = reviewPR(t, st, &dto.ReviewPRRequest{
+		PullRequestID: prId,
+		ReviewerID:    authorId,
+		Decision:      models.DecisionApproved,
+	})
+	require.Equal(t, 409, code)
+	_, code = reviewPR(t, st, &dto.ReviewPRRequest{
+		PullRequestID: prId,
+		ReviewerID:    first,
+		Decision:      "LGTM",
+	})
+	require.Equal(t, 400, code)
+
+	// 2. Одного одобрения недостаточно
+	reviewed, code := reviewPR(t, st, &dto.ReviewPRRequest{
+		PullRequestID: prId,
+		ReviewerID:    first,
+		Decision:      models.DecisionApproved,
+	})
+	require.Equal(t, 200, code)
+	require.Equal(t, models.DecisionApproved, reviewed.Review.Decision)
+	_, code = reviewPR(t, st, &dto.ReviewPRRequest{
+		PullRequestID: prId,
+		ReviewerID:    second,
+		Decision:      models.DecisionChangesRequested,
+		Comment:       "needs tests",
+	})
+	require.Equal(t, 200, code)
+
+	_, code = mergePR(t, st, &dto.MergePRRequest{PullRequestID: prId})
+	require.Equal(t, 409, code)
+
+	// 3. Последнее решение ревьювера видно в его ревью
+	reviews, code := getUserReviews(t, st, second)
+	require.Equal(t, 200, code)
+	require.Len(t, reviews.PullRequests, 1)
+	for _, assignment := range reviews.PullRequests[0].Assignments {
+		require.NotNil(t, assignment.DecidedAt)
+		if assignment.UserId == first {
+			require.Equal(t, models.DecisionApproved, assignment.Decision)
+		} else {
+			require.Equal(t, models.DecisionChangesRequested, assignment.Decision)
+		}
+	}
+
+	// 4. После второго одобрения PR мержится, решения по смерженному PR не принимаются
+	_, code = reviewPR(t, st, &dto.ReviewPRRequest{
+		PullRequestID: prId,
+		ReviewerID:    second,
+		Decision:      models.DecisionApproved,
+	})
+	require.Equal(t, 200, code)
+
+	_, code = mergePR(t, st, &dto.MergePRRequest{PullRequestID: prId})
+	require.Equal(t, 200, code)
+
+	_, code = reviewPR(t, st, &dto.ReviewPRRequest{
+		PullRequestID: prId,
+		ReviewerID:    first,
+		Decision:      models.DecisionCommented,
+	})
+	require.Equal(t, 409, code)
+
+	// 5. Решения принимаются только по открытому PR'у
+	response, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+	require.Equal(t, 201, code)
+	_, code = changePRStatus(t, st, "/pullRequest/close", response.PR.Id)
+	require.Equal(t, 200, code)
+	_, code = reviewPR(t, st, &dto.ReviewPRRequest{
+		PullRequestID: response.PR.Id,
+		ReviewerID:    response.PR.Reviewers[0],
+		Decision:      models.DecisionApproved,
+	})
+	require.Equal(t, 409, code)
+}
+
+func reviewPR(t *testing.T, st *Suite, reqBody *dto.ReviewPRRequest) (*dto.ReviewPRResponse, int) {
+	body, _ := json.Marshal(reqBody)
+	httpReq := httptest.NewRequestWithContext(t.Context(), "POST", "/pullRequest/review", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.ReviewPRResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+// TestPRStatusTransitions проверяет жизненный цикл PR'а: черновик, готовность к ревью, закрытие и переоткрытие
+func TestPRStatusTransitions(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	teamName := gofakeit.Name() + uuid.NewString()
+	authorId := uuid.NewString()
+	members := []*models.Member{
+		{
+			Id:       authorId,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		},
+	}
+	for range 2 {
+		members = append(members, &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		})
+	}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    teamName,
+		Members: members,
+	})
+	require.Equal(t, 201, code)
+
+	// 1. Черновик создаётся без ревьюверов и не мержится
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId, Draft: true})
+	require.Equal(t, 201, code)
+	require.Equal(t, models.StatusDraft, response.PR.Status)
+	require.Empty(t, response.PR.Reviewers)
+	prId := response.PR.Id
+
+	_, code = mergePR(t, st, &dto.MergePRRequest{PullRequestID: prId})
+	require.Equal(t, 409, code)
+	_, code = changePRStatus(t, st, "/pullRequest/reopen", prId)
+	require.Equal(t, 409, code)
+
+	// 2. После перевода в OPEN ревьюверы назначаются
+	changed, code := changePRStatus(t, st, "/pullRequest/ready", prId)
+	require.Equal(t, 200, code)
+	require.Equal(t, models.StatusOpen, changed.PR.Status)
+	require.Len(t, changed.PR.Reviewers, 2)
+	reviewers := changed.PR.Reviewers
+
+	_, code = changePRStatus(t, st, "/pullRequest/ready", prId)
+	require.Equal(t, 409, code)
+
+	// 3. Закрытие снимает ревьюверов, повторно закрыть нельзя
+	changed, code = changePRStatus(t, st, "/pullRequest/close", prId)
+	require.Equal(t, 200, code)
+	require.Equal(t, models.StatusClosed, changed.PR.Status)
+	require.Empty(t, changed.PR.Reviewers)
+	for _, reviewerId := range reviewers {
+		reviews, code := getUserReviews(t, st, reviewerId)
+		require.Equal(t, 200, code)
+		require.Empty(t, reviews.PullRequests)
+	}
+
+	_, code = changePRStatus(t, st, "/pullRequest/close", prId)
+	require.Equal(t, 409, code)
+	_, code = mergePR(t, st, &dto.MergePRRequest{PullRequestID: prId})
+	require.Equal(t, 409, code)
+
+	// 4. Переоткрытый PR снова получает ревьюверов и может быть смержен
+	changed, code = changePRStatus(t, st, "/pullRequest/reopen", prId)
+	require.Equal(t, 200, code)
+	require.Equal(t, models.StatusOpen, changed.PR.Status)
+	require.Len(t, changed.PR.Reviewers, 2)
+
+	_, code = mergePR(t, st, &dto.MergePRRequest{PullRequestID: prId})
+	require.Equal(t, 200, code)
+	_, code = changePRStatus(t, st, "/pullRequest/close", prId)
+	require.Equal(t, 409, code)
+
+	// 5. Несуществующий PR
+	_, code = changePRStatus(t, st, "/pullRequest/close", uuid.NewString())
+	require.Equal(t, 404, code)
+}
+
+func changePRStatus(t *testing.T, st *Suite, path, prId string) (*dto.ChangePRStatusResponse, int) {
+	body, _ := json.Marshal(&dto.ChangePRStatusRequest{PullRequestID: prId})
+	httpReq := httptest.NewRequestWithContext(t.Context(), "POST", path, bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.ChangePRStatusResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+// TestUpdatePR проверяет изменение названия и автора PR'а и пересмотр ревьюверов при смене автора
+func TestUpdatePR(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	newMembers := func(count int) []*models.Member {
+		members := make([]*models.Member, 0, count)
+		for range count {
+			members = append(members, &models.Member{
+				Id:       uuid.NewString(),
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			})
+		}
+		return members
+	}
+	membersA, membersB := newMembers(3), newMembers(3)
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    gofakeit.Name() + uuid.NewString(),
+		Members: membersA,
+	})
+	require.Equal(t, 201, code)
+	_, code = createTeam(t, st, &dto.AddTeamRequest{
+		Name:    gofakeit.Name() + uuid.NewString(),
+		Members: membersB,
+	})
+	require.Equal(t, 201, code)
+
+	authorId := membersA[0].Id
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+	require.Equal(t, 201, code)
+	require.ElementsMatch(t, []string{membersA[1].Id, membersA[2].Id}, response.PR.Reviewers)
+	prId := response.PR.Id
+
+	// 1. Смена названия не трогает ревьюверов
+	title := gofakeit.City()
+	updated, code := updatePR(t, st, &dto.UpdatePRRequest{PullRequestID: prId, Title: &title})
+	require.Equal(t, 200, code)
+	require.Equal(t, title, updated.PR.Title)
+	require.Equal(t, authorId, updated.PR.AuthorId)
+	require.ElementsMatch(t, response.PR.Reviewers, updated.PR.Reviewers)
+
+	// 2. Новый автор из той же команды снимается с ревью и заменяется
+	newAuthorId := membersA[1].Id
+	updated, code = updatePR(t, st, &dto.UpdatePRRequest{PullRequestID: prId, AuthorID: &newAuthorId})
+	require.Equal(t, 200, code)
+	require.Equal(t, newAuthorId, updated.PR.AuthorId)
+	require.ElementsMatch(t, []string{membersA[0].Id, membersA[2].Id}, updated.PR.Reviewers)
+
+	// 3. Автор из другой команды, KEEP: ревьюверы остаются
+	newAuthorId = membersB[0].Id
+	updated, code = updatePR(t, st, &dto.UpdatePRRequest{PullRequestID: prId, AuthorID: &newAuthorId})
+	require.Equal(t, 200, code)
+	require.ElementsMatch(t, []string{membersA[0].Id, membersA[2].Id}, updated.PR.Reviewers)
+
+	// 4. Автор из другой команды, REPLACE: ревьюверы подбираются из его команды
+	newAuthorId = membersA[0].Id
+	updated, code = updatePR(t, st, &dto.UpdatePRRequest{
+		PullRequestID:   prId,
+		AuthorID:        &newAuthorId,
+		ReviewersPolicy: models.PolicyReplace,
+	})
+	require.Equal(t, 200, code)
+	require.ElementsMatch(t, []string{membersA[1].Id, membersA[2].Id}, updated.PR.Reviewers)
+
+	// 5. Ошибки валидации и несуществующие PR и автор
+	_, code = updatePR(t, st, &dto.UpdatePRRequest{PullRequestID: prId})
+	require.Equal(t, 400, code)
+	_, code = updatePR(t, st, &dto.UpdatePRRequest{PullRequestID: prId, AuthorID: &newAuthorId, ReviewersPolicy: "DROP"})
+	require.Equal(t, 400, code)
+	unknownId := uuid.NewString()
+	_, code = updatePR(t, st, &dto.UpdatePRRequest{PullRequestID: prId, AuthorID: &unknownId})
+	require.Equal(t, 404, code)
+	_, code = updatePR(t, st, &dto.UpdatePRRequest{PullRequestID: unknownId, Title: &title})
+	require.Equal(t, 404, code)
+
+	// 6. У смерженного PR'а можно поменять название, но не автора
+	_, code = mergePR(t, st, &dto.MergePRRequest{PullRequestID: prId})
+	require.Equal(t, 200, code)
+	title = gofakeit.City()
+	updated, code = updatePR(t, st, &dto.UpdatePRRequest{PullRequestID: prId, Title: &title})
+	require.Equal(t, 200, code)
+	require.Equal(t, title, updated.PR.Title)
+	newAuthorId = membersB[1].Id
+	_, code = updatePR(t, st, &dto.UpdatePRRequest{PullRequestID: prId, AuthorID: &newAuthorId})
+	require.Equal(t, 409, code)
+}
+
+func updatePR(t *testing.T, st *Suite, reqBody *dto.UpdatePRRequest) (*dto.UpdatePRResponse, int) {
+	body, _ := json.Marshal(reqBody)
+	httpReq := httptest.NewRequestWithContext(t.Context(), "POST", "/pullRequest/update", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.UpdatePRResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
@@ -0,0 +1,142 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"testing"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReviewerRules проверяет, что правило INCLUDE всегда добавляет ревьювера на PR'ы автора,
+// правило EXCLUDE не даёт его назначить, а сработавшие правила возвращаются в ответе
+func TestReviewerRules(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	teamName := gofakeit.Name() + uuid.NewString()
+	authorId := uuid.NewString()
+	mentorId := uuid.NewString()
+	partnerId := uuid.NewString()
+	otherIds := []string{uuid.NewString(), uuid.NewString()}
+	members := make([]*models.Member, 0)
+	for _, id := range append([]string{authorId, mentorId, partnerId}, otherIds...) {
+		members = append(members, &models.Member{
+			Id:       id,
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		})
+	}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    teamName,
+		Members: members,
+	})
+	require.Equal(t, 201, code)
+
+	include, code := addReviewerRule(t, st, &dto.AddReviewerRuleRequest{
+		TeamName:   teamName,
+		Kind:       models.RuleInclude,
+		AuthorId:   authorId,
+		ReviewerId: mentorId,
+	})
+	require.Equal(t, 201, code)
+	exclude, code := addReviewerRule(t, st, &dto.AddReviewerRuleRequest{
+		TeamName:   teamName,
+		Kind:       models.RuleExclude,
+		AuthorId:   authorId,
+		ReviewerId: partnerId,
+	})
+	require.Equal(t, 201, code)
+
+	// 1. Для пары может быть только одно правило
+	_, code = addReviewerRule(t, st, &dto.AddReviewerRuleRequest{
+		TeamName:   teamName,
+		Kind:       models.RuleInclude,
+		AuthorId:   authorId,
+		ReviewerId: partnerId,
+	})
+	require.Equal(t, 400, code)
+
+	rules, code := getReviewerRules(t, st, teamName)
+	require.Equal(t, 200, code)
+	require.ElementsMatch(t, []*models.ReviewerRule{include.Rule, exclude.Rule}, rules.Rules)
+
+	// 2. Наставник назначается всегда, напарник - никогда
+	var prId string
+	for range 5 {
+		response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+		require.Equal(t, 201, code)
+		require.Len(t, response.PR.Reviewers, 2)
+		require.Contains(t, response.PR.Reviewers, mentorId)
+		require.NotContains(t, response.PR.Reviewers, partnerId)
+		require.ElementsMatch(t, []*models.ReviewerRule{include.Rule, exclude.Rule}, response.PR.AppliedRules)
+		prId = response.PR.Id
+	}
+
+	// 3. При переназначении наставника напарник тоже не назначается
+	body, code := reassignPR(t, st, &dto.ReassignPRRequest{
+		PullRequestID: prId,
+		OldReviewerID: mentorId,
+	})
+	require.Equal(t, 200, code)
+	var reassigned dto.ReassignPRResponse
+	require.NoError(t, json.Unmarshal(body, &reassigned))
+	require.ElementsMatch(t, otherIds, reassigned.PR.Reviewers)
+	require.Equal(t, []*models.ReviewerRule{exclude.Rule}, reassigned.PR.AppliedRules)
+
+	// 4. После удаления правила напарник снова может быть назначен
+	require.Equal(t, 204, deleteReviewerRule(t, st, exclude.Rule.Id))
+	require.Equal(t, 404, deleteReviewerRule(t, st, exclude.Rule.Id))
+
+	rules, code = getReviewerRules(t, st, teamName)
+	require.Equal(t, 200, code)
+	require.Equal(t, []*models.ReviewerRule{include.Rule}, rules.Rules)
+}
+
+func addReviewerRule(t *testing.T, st *Suite, reqBody *dto.AddReviewerRuleRequest) (*dto.AddReviewerRuleResponse, int) {
+	body, _ := json.Marshal(reqBody)
+	httpReq := httptest.NewRequestWithContext(t.Context(), "POST", "/team/addRule", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.AddReviewerRuleResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+func getReviewerRules(t *testing.T, st *Suite, teamName string) (*dto.ReviewerRulesResponse, int) {
+	httpReq := httptest.NewRequestWithContext(t.Context(), "GET", "/team/rules?team_name="+url.QueryEscape(teamName), nil)
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.ReviewerRulesResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+func deleteReviewerRule(t *testing.T, st *Suite, id string) int {
+	body, _ := json.Marshal(&dto.DeleteReviewerRuleRequest{Id: id})
+	httpReq := httptest.NewRequestWithContext(t.Context(), "POST", "/team/deleteRule", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	return recorder.Result().StatusCode
+}
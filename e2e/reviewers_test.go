@@ -0,0 +1,200 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"slices"
+	"testing"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExplicitReviewers проверяет явное назначение ревьюверов из любых команд и снятие ревьювера с дозаполнением и без него
+func TestExplicitReviewers(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	newMember := func() *models.Member {
+		return &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		}
+	}
+	author := newMember()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    gofakeit.Name() + uuid.NewString(),
+		Members: []*models.Member{author, newMember(), newMember(), newMember()},
+	})
+	require.Equal(t, 201, code)
+	outsider := newMember()
+	_, code = createTeam(t, st, &dto.AddTeamRequest{
+		Name:    gofakeit.Name() + uuid.NewString(),
+		Members: []*models.Member{outsider},
+	})
+	require.Equal(t, 201, code)
+
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+	require.Equal(t, 201, code)
+	require.Len(t, response.PR.Reviewers, 2)
+	prId := response.PR.Id
+	first, second := response.PR.Reviewers[0], response.PR.Reviewers[1]
+
+	// 1. Ревьювер из другой команды назначается сверх квоты, уже назначенный пропускается
+	body, code := addReviewers(t, st, &dto.AddReviewersRequest{
+		PullRequestID: prId,
+		ReviewerIDs:   []string{outsider.Id, first},
+	})
+	require.Equal(t, 200, code)
+	var added dto.AddReviewersResponse
+	require.NoError(t, json.Unmarshal(body, &added))
+	require.Equal(t, []string{outsider.Id}, added.Added)
+	require.ElementsMatch(t, []string{first, second, outsider.Id}, added.PR.Reviewers)
+	idx := slices.IndexFunc(added.PR.Assignments, func(a *models.Assignment) bool {
+		return a.UserId == outsider.Id
+	})
+	require.NotEqual(t, -1, idx)
+	require.Equal(t, models.SourceRequested, added.PR.Assignments[idx].Source)
+
+	// 2. Автора и несуществующего пользователя назначить нельзя
+	body, code = addReviewers(t, st, &dto.AddReviewersRequest{
+		PullRequestID: prId,
+		ReviewerIDs:   []string{author.Id},
+	})
+	require.Equal(t, 409, code)
+	var errResp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &errResp))
+	require.Equal(t, dto.ErrCodeReviewerUnavailable, errResp.Error.Code)
+
+	_, code = addReviewers(t, st, &dto.AddReviewersRequest{
+		PullRequestID: prId,
+		ReviewerIDs:   []string{uuid.NewString()},
+	})
+	require.Equal(t, 404, code)
+
+	// 3. Снятие сверх квоты не требует замены
+	body, code = removeReviewer(t, st, &dto.RemoveReviewerRequest{
+		PullRequestID: prId,
+		ReviewerID:    outsider.Id,
+	})
+	require.Equal(t, 200, code)
+	var removed dto.RemoveReviewerResponse
+	require.NoError(t, json.Unmarshal(body, &removed))
+	require.ElementsMatch(t, []string{first, second}, removed.PR.Reviewers)
+	require.Empty(t, removed.PR.NeedMoreReviewersReason)
+
+	// 4. Без дозаполнения место остаётся пустым, и фоновое дозаполнение его не занимает
+	body, code = removeReviewer(t, st, &dto.RemoveReviewerRequest{
+		PullRequestID: prId,
+		ReviewerID:    first,
+	})
+	require.Equal(t, 200, code)
+	removed = dto.RemoveReviewerResponse{}
+	require.NoError(t, json.Unmarshal(body, &removed))
+	require.Equal(t, []string{second}, removed.PR.Reviewers)
+	require.Empty(t, removed.ReplacedBy)
+	require.Equal(t, models.ReasonReviewerRemoved, removed.PR.NeedMoreReviewersReason)
+
+	_, err := st.uc.BackfillReviewers(t.Context())
+	require.NoError(t, err)
+	reviews, code := getUserReviews(t, st, second)
+	require.Equal(t, 200, code)
+	require.Len(t, reviews.PullRequests, 1)
+	require.Equal(t, []string{second}, reviews.PullRequests[0].Reviewers)
+
+	// 5. С дозаполнением недостающие до квоты ревьюверы подбираются сразу, кроме снятого
+	body, code = removeReviewer(t, st, &dto.RemoveReviewerRequest{
+		PullRequestID: prId,
+		ReviewerID:    second,
+		Backfill:      true,
+	})
+	require.Equal(t, 200, code)
+	removed = dto.RemoveReviewerResponse{}
+	require.NoError(t, json.Unmarshal(body, &removed))
+	require.Len(t, removed.PR.Reviewers, 2)
+	require.NotContains(t, removed.PR.Reviewers, second)
+	require.Contains(t, removed.PR.Reviewers, removed.ReplacedBy)
+	require.Empty(t, removed.PR.NeedMoreReviewersReason)
+
+	// 6. Снять неназначенного ревьювера нельзя
+	_, code = removeReviewer(t, st, &dto.RemoveReviewerRequest{
+		PullRequestID: prId,
+		ReviewerID:    second,
+	})
+	require.Equal(t, 409, code)
+
+	// 7. После merge ревьюверов не меняют
+	_, code = mergePR(t, st, &dto.MergePRRequest{PullRequestID: prId})
+	require.Equal(t, 200, code)
+
+	body, code = addReviewers(t, st, &dto.AddReviewersRequest{
+		PullRequestID: prId,
+		ReviewerIDs:   []string{outsider.Id},
+	})
+	require.Equal(t, 409, code)
+	errResp = dto.ErrorResponse{}
+	require.NoError(t, json.Unmarshal(body, &errResp))
+	require.Equal(t, dto.ErrCodeCannotReassignMergedPR, errResp.Error.Code)
+
+	body, code = removeReviewer(t, st, &dto.RemoveReviewerRequest{
+		PullRequestID: prId,
+		ReviewerID:    removed.ReplacedBy,
+	})
+	require.Equal(t, 409, code)
+	errResp = dto.ErrorResponse{}
+	require.NoError(t, json.Unmarshal(body, &errResp))
+	require.Equal(t, dto.ErrCodeCannotReassignMergedPR, errResp.Error.Code)
+
+	history, code := getPRHistory(t, st, prId)
+	require.Equal(t, 200, code)
+	require.True(t, slices.ContainsFunc(history.Events, func(e *models.PREvent) bool {
+		return e.Kind == models.EventAssigned && e.UserId == outsider.Id && e.Reason == models.TriggerRequest
+	}))
+	require.True(t, slices.ContainsFunc(history.Events, func(e *models.PREvent) bool {
+		return e.Kind == models.EventUnassigned && e.UserId == first && e.Reason == models.TriggerRemove
+	}))
+
+	// 8. На черновик ревьюверов не назначают
+	response, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id, Draft: true})
+	require.Equal(t, 201, code)
+	body, code = addReviewers(t, st, &dto.AddReviewersRequest{
+		PullRequestID: response.PR.Id,
+		ReviewerIDs:   []string{outsider.Id},
+	})
+	require.Equal(t, 409, code)
+	errResp = dto.ErrorResponse{}
+	require.NoError(t, json.Unmarshal(body, &errResp))
+	require.Equal(t, dto.ErrCodePRDraft, errResp.Error.Code)
+}
+
+func addReviewers(t *testing.T, st *Suite, reqBody *dto.AddReviewersRequest) ([]byte, int) {
+	body, err := json.Marshal(reqBody)
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), "POST", "/pullRequest/addReviewers", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	return recorder.Body.Bytes(), recorder.Result().StatusCode
+}
+
+func removeReviewer(t *testing.T, st *Suite, reqBody *dto.RemoveReviewerRequest) ([]byte, int) {
+	body, err := json.Marshal(reqBody)
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), "POST", "/pullRequest/removeReviewer", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	return recorder.Body.Bytes(), recorder.Result().StatusCode
+}
@@ -0,0 +1,176 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReviewSLAEscalation проверяет, что ревью, нарушившее SLA команды, попадает в список просроченных
+// и переназначается, а если замены нет - ревьювер остаётся и назначение помечается эскалированным
+func TestReviewSLAEscalation(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	// createSLATeam создаёт команду из автора и members активных участников с SLA 24 рабочих часа
+	createSLATeam := func(members int) (string, string) {
+		teamName := gofakeit.Name() + uuid.NewString()
+		authorId := uuid.NewString()
+		request := &dto.AddTeamRequest{
+			Name: teamName,
+			Members: []*models.Member{{
+				Id:       authorId,
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			}},
+		}
+		for range members {
+			request.Members = append(request.Members, &models.Member{
+				Id:       uuid.NewString(),
+				Username: gofakeit.Name() + uuid.NewString(),
+				IsActive: true,
+			})
+		}
+		_, code := createTeam(t, st, request)
+		require.Equal(t, 201, code)
+
+		slaHours := 24
+		settings, code := updateTeamSettings(t, st, &dto.TeamSettingsRequest{
+			Name:           teamName,
+			ReviewSLAHours: &slaHours,
+		})
+		require.Equal(t, 200, code)
+		require.Equal(t, &slaHours, settings.Settings.ReviewSLAHours)
+
+		return teamName, authorId
+	}
+
+	t.Run("reassign", func(t *testing.T) {
+		teamName, authorId := createSLATeam(3)
+		response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+		require.Equal(t, 201, code)
+		require.Len(t, response.PR.Reviewers, 2)
+		prId := response.PR.Id
+		overdueId := response.PR.Reviewers[0]
+
+		// 1. Свежие назначения SLA не нарушают
+		overdue, code := getOverduePRs(t, st, teamName)
+		require.Equal(t, 200, code)
+		require.Empty(t, overdue.PullRequests)
+
+		// 2. Назначение недельной давности просрочено
+		setAssignedAt(t, st, prId, overdueId, time.Now().AddDate(0, 0, -7))
+
+		overdue, code = getOverduePRs(t, st, teamName)
+		require.Equal(t, 200, code)
+		require.Len(t, overdue.PullRequests, 1)
+		require.Equal(t, prId, overdue.PullRequests[0].Id)
+		require.Len(t, overdue.PullRequests[0].Reviews, 1)
+		review := overdue.PullRequests[0].Reviews[0]
+		require.Equal(t, overdueId, review.ReviewerId)
+		require.True(t, review.Deadline.Before(time.Now()))
+		require.Nil(t, review.EscalatedAt)
+
+		// 3. Эскалация переназначает ревью на свободного участника
+		escalated, err := st.uc.EscalateOverdueReviews(t.Context())
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, escalated, 1)
+
+		reviews, code := getUserReviews(t, st, overdueId)
+		require.Equal(t, 200, code)
+		require.Empty(t, reviews.PullRequests)
+
+		overdue, code = getOverduePRs(t, st, teamName)
+		require.Equal(t, 200, code)
+		require.Empty(t, overdue.PullRequests)
+
+		history, code := getPRHistory(t, st, prId)
+		require.Equal(t, 200, code)
+		require.True(t, slices.ContainsFunc(history.Events, func(e *models.PREvent) bool {
+			return e.Kind == models.EventEscalated && e.UserId == overdueId && e.Reason == models.TriggerEscalation
+		}))
+		require.True(t, slices.ContainsFunc(history.Events, func(e *models.PREvent) bool {
+			return e.Kind == models.EventUnassigned && e.UserId == overdueId && e.Reason == models.TriggerEscalation
+		}))
+	})
+
+	t.Run("no candidates", func(t *testing.T) {
+		teamName, authorId := createSLATeam(2)
+		response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: authorId})
+		require.Equal(t, 201, code)
+		require.Len(t, response.PR.Reviewers, 2)
+		prId := response.PR.Id
+		overdueId := response.PR.Reviewers[0]
+
+		setAssignedAt(t, st, prId, overdueId, time.Now().AddDate(0, 0, -7))
+
+		_, err := st.uc.EscalateOverdueReviews(t.Context())
+		require.NoError(t, err)
+
+		// Замены нет: ревьювер остаётся, а назначение помечено эскалированным
+		reviews, code := getUserReviews(t, st, overdueId)
+		require.Equal(t, 200, code)
+		require.Len(t, reviews.PullRequests, 1)
+		require.ElementsMatch(t, response.PR.Reviewers, reviews.PullRequests[0].Reviewers)
+
+		overdue, code := getOverduePRs(t, st, teamName)
+		require.Equal(t, 200, code)
+		require.Len(t, overdue.PullRequests, 1)
+		require.Len(t, overdue.PullRequests[0].Reviews, 1)
+		require.NotNil(t, overdue.PullRequests[0].Reviews[0].EscalatedAt)
+
+		history, code := getPRHistory(t, st, prId)
+		require.Equal(t, 200, code)
+		escalatedEvents := 0
+		for _, e := range history.Events {
+			if e.Kind == models.EventEscalated {
+				escalatedEvents++
+			}
+			require.NotEqual(t, models.EventUnassigned, e.Kind)
+		}
+		require.Equal(t, 1, escalatedEvents)
+
+		// Повторно назначение не эскалируется
+		_, err = st.uc.EscalateOverdueReviews(t.Context())
+		require.NoError(t, err)
+		history, code = getPRHistory(t, st, prId)
+		require.Equal(t, 200, code)
+		require.Equal(t, 1, len(slices.DeleteFunc(history.Events, func(e *models.PREvent) bool {
+			return e.Kind != models.EventEscalated
+		})))
+	})
+}
+
+// setAssignedAt сдвигает время назначения ревьювера в прошлое напрямую в базе
+func setAssignedAt(t *testing.T, st *Suite, prId string, userId string, assignedAt time.Time) {
+	_, err := st.pool.Exec(t.Context(), `
+		UPDATE pull_requests_users SET assigned_at = $3
+		WHERE pr_id = $1 AND user_id = $2
+	`, prId, userId, assignedAt)
+	require.NoError(t, err)
+}
+
+func getOverduePRs(t *testing.T, st *Suite, teamName string) (*dto.OverduePRsResponse, int) {
+	httpReq := httptest.NewRequestWithContext(t.Context(), "GET", "/pullRequest/overdue?team_name="+url.QueryEscape(teamName), nil)
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.OverduePRsResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
@@ -1,6 +1,8 @@
 package e2e
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"pr-review/internal/config"
@@ -10,6 +12,7 @@ import (
 	"pr-review/internal/repository/postgres"
 	"pr-review/internal/usecases"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 )
 
@@ -18,6 +21,9 @@ const envconfigFilename = ".env.test"
 type Suite struct {
 	srv *server.HTTPServer
 	db  *postgres.Storage
+	uc  *usecases.Usecases
+	// pool прямое соединение с базой, чтобы готовить данные, которые нельзя получить через API (например, старые назначения)
+	pool *pgxpool.Pool
 }
 
 func NewSuite() *Suite {
@@ -32,15 +38,22 @@ func NewSuite() *Suite {
 	cfg := config.MustParseConfig()
 
 	db := postgres.New(cfg.DatabaseConfig)
-	uc := usecases.New(log, db)
+	pool, err := pgxpool.New(context.Background(), fmt.Sprintf("postgres://%s:%s@%s:%d/%s",
+		cfg.DatabaseConfig.Username, cfg.DatabaseConfig.Password, cfg.DatabaseConfig.Host, cfg.DatabaseConfig.Port, cfg.DatabaseConfig.Name))
+	if err != nil {
+		panic("error creating test connection pool: " + err.Error())
+	}
+	uc := usecases.New(log, cfg.ReviewConfig, db)
 	h := handlers.New(log, uc)
 	m := middlewares.New(log)
 
 	srv := server.New(log, cfg.ApplicationConfig, h, m)
 
 	return &Suite{
-		srv: srv,
-		db:  db,
+		srv:  srv,
+		db:   db,
+		uc:   uc,
+		pool: pool,
 	}
 }
 
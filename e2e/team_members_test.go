@@ -0,0 +1,141 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"slices"
+	"testing"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTeamMembers проверяет добавление и исключение участников существующей команды и замену её состава,
+// а также переназначение открытых ревью исключённых участников
+func TestTeamMembers(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	newMember := func() *models.Member {
+		return &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		}
+	}
+	memberIds := func(members []*models.Member) []string {
+		ids := make([]string, 0, len(members))
+		for _, m := range members {
+			ids = append(ids, m.Id)
+		}
+		return ids
+	}
+	teamName := gofakeit.Name() + uuid.NewString()
+	author, first, second := newMember(), newMember(), newMember()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    teamName,
+		Members: []*models.Member{author, first, second},
+	})
+	require.Equal(t, 201, code)
+
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+	require.Equal(t, 201, code)
+	require.ElementsMatch(t, []string{first.Id, second.Id}, response.PR.Reviewers)
+	prId := response.PR.Id
+
+	// 1. Участник добавляется в существующую команду
+	newcomer := newMember()
+	roster, code := changeTeamMembers(t, st, "POST", "/team/addMembers", &dto.TeamMembersRequest{
+		Name:    teamName,
+		Members: []*models.Member{newcomer},
+	})
+	require.Equal(t, 200, code)
+	require.ElementsMatch(t, []string{author.Id, first.Id, second.Id, newcomer.Id}, memberIds(roster.Members))
+
+	// 2. Исключённый участник снимается с ревью, его место занимает оставшийся в команде
+	roster, code = changeTeamMembers(t, st, "POST", "/team/removeMembers", &dto.RemoveTeamMembersRequest{
+		Name:    teamName,
+		UserIds: []string{first.Id},
+	})
+	require.Equal(t, 200, code)
+	require.ElementsMatch(t, []string{author.Id, second.Id, newcomer.Id}, memberIds(roster.Members))
+
+	pr, code := getPR(t, st, prId)
+	require.Equal(t, 200, code)
+	require.ElementsMatch(t, []string{second.Id, newcomer.Id}, pr.PR.Reviewers)
+
+	history, code := getPRHistory(t, st, prId)
+	require.Equal(t, 200, code)
+	require.True(t, slices.ContainsFunc(history.Events, func(e *models.PREvent) bool {
+		return e.Kind == models.EventUnassigned && e.UserId == first.Id && e.Reason == models.TriggerMemberRemoval
+	}))
+
+	// 3. Исключить того, кто в команде не состоит, нельзя
+	_, code = changeTeamMembers(t, st, "POST", "/team/removeMembers", &dto.RemoveTeamMembersRequest{
+		Name:    teamName,
+		UserIds: []string{first.Id},
+	})
+	require.Equal(t, 404, code)
+
+	// 4. Замена состава идемпотентна
+	for range 2 {
+		roster, code = changeTeamMembers(t, st, "PUT", "/team/members", &dto.TeamMembersRequest{
+			Name:    teamName,
+			Members: []*models.Member{author, second, newcomer},
+		})
+		require.Equal(t, 200, code)
+		require.ElementsMatch(t, []string{author.Id, second.Id, newcomer.Id}, memberIds(roster.Members))
+	}
+
+	// 5. Не вошедшие в новый состав исключаются, замену им подобрать не из кого
+	roster, code = changeTeamMembers(t, st, "PUT", "/team/members", &dto.TeamMembersRequest{
+		Name:    teamName,
+		Members: []*models.Member{author, second},
+	})
+	require.Equal(t, 200, code)
+	require.ElementsMatch(t, []string{author.Id, second.Id}, memberIds(roster.Members))
+
+	pr, code = getPR(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Equal(t, []string{second.Id}, pr.PR.Reviewers)
+	require.Equal(t, models.ReasonNoCandidates, pr.PR.NeedMoreReviewersReason)
+
+	// 6. Автор без команды не может создать PR
+	_, code = changeTeamMembers(t, st, "PUT", "/team/members", &dto.TeamMembersRequest{
+		Name:    teamName,
+		Members: []*models.Member{second},
+	})
+	require.Equal(t, 200, code)
+	_, code = createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+	require.Equal(t, 409, code)
+
+	// 7. Несуществующая команда
+	_, code = changeTeamMembers(t, st, "POST", "/team/addMembers", &dto.TeamMembersRequest{
+		Name:    gofakeit.Name() + uuid.NewString(),
+		Members: []*models.Member{newMember()},
+	})
+	require.Equal(t, 404, code)
+}
+
+func changeTeamMembers(t *testing.T, st *Suite, method, path string, reqBody any) (*dto.GetTeamResponse, int) {
+	body, err := json.Marshal(reqBody)
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), method, path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	var res dto.GetTeamResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
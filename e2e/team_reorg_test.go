@@ -0,0 +1,162 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"slices"
+	"testing"
+
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTeamReorg проверяет переименование, удаление и слияние команд
+func TestTeamReorg(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	newMember := func() *models.Member {
+		return &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		}
+	}
+	newTeam := func(members ...*models.Member) string {
+		name := gofakeit.Name() + uuid.NewString()
+		_, code := createTeam(t, st, &dto.AddTeamRequest{Name: name, Members: members})
+		require.Equal(t, 201, code)
+		return name
+	}
+
+	// 1. Переименование сохраняет участников, занятое название отклоняется
+	member := newMember()
+	teamName := newTeam(member)
+	newName := gofakeit.Name() + uuid.NewString()
+	body, code := postTeamReorg(t, st, "/team/rename", &dto.RenameTeamRequest{Name: teamName, NewName: newName})
+	require.Equal(t, 200, code)
+	var renamed dto.RenameTeamResponse
+	require.NoError(t, json.Unmarshal(body, &renamed))
+	require.Equal(t, newName, renamed.Name)
+	require.Equal(t, teamName, renamed.PreviousName)
+
+	team, code := getTeam(t, st, newName)
+	require.Equal(t, 200, code)
+	require.Len(t, team.Members, 1)
+	require.Equal(t, member.Id, team.Members[0].Id)
+	_, code = getTeam(t, st, teamName)
+	require.Equal(t, 404, code)
+
+	otherName := newTeam(newMember())
+	body, code = postTeamReorg(t, st, "/team/rename", &dto.RenameTeamRequest{Name: newName, NewName: otherName})
+	require.Equal(t, 400, code)
+	var errResp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &errResp))
+	require.Equal(t, dto.ErrCodeTeamExists, errResp.Error.Code)
+
+	// 2. Слияние переводит участников и правила в целевую команду, исходная удаляется
+	author, excluded := newMember(), newMember()
+	sourceName := newTeam(author, excluded)
+	targetReviewer, targetExcluded := newMember(), newMember()
+	targetName := newTeam(targetReviewer, targetExcluded)
+	_, code = addReviewerRule(t, st, &dto.AddReviewerRuleRequest{
+		TeamName:   sourceName,
+		Kind:       models.RuleExclude,
+		AuthorId:   author.Id,
+		ReviewerId: targetExcluded.Id,
+	})
+	require.Equal(t, 201, code)
+
+	body, code = postTeamReorg(t, st, "/team/merge", &dto.MergeTeamsRequest{Name: sourceName, IntoName: targetName})
+	require.Equal(t, 200, code)
+	var merged dto.GetTeamResponse
+	require.NoError(t, json.Unmarshal(body, &merged))
+	require.Len(t, merged.Members, 4)
+	_, code = getTeam(t, st, sourceName)
+	require.Equal(t, 404, code)
+
+	rules, code := getReviewerRules(t, st, targetName)
+	require.Equal(t, 200, code)
+	require.Len(t, rules.Rules, 1)
+
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+	require.Equal(t, 201, code)
+	require.ElementsMatch(t, []string{excluded.Id, targetReviewer.Id}, response.PR.Reviewers)
+
+	// 3. Удаление с переводом участников сохраняет их ревью
+	movedName := newTeam(newMember())
+	moved, code := getTeam(t, st, movedName)
+	require.Equal(t, 200, code)
+	body, code = postTeamReorg(t, st, "/team/delete", &dto.DeleteTeamRequest{Name: movedName, MoveMembersTo: targetName})
+	require.Equal(t, 200, code)
+	var deleted dto.DeleteTeamResponse
+	require.NoError(t, json.Unmarshal(body, &deleted))
+	require.Equal(t, []string{moved.Members[0].Id}, deleted.Members)
+	team, code = getTeam(t, st, targetName)
+	require.Equal(t, 200, code)
+	require.True(t, slices.ContainsFunc(team.Members, func(m *models.Member) bool { return m.Id == moved.Members[0].Id }))
+
+	// 4. Удаление без перевода деактивирует участников и переназначает их ревью
+	_, code = addReviewers(t, st, &dto.AddReviewersRequest{
+		PullRequestID: response.PR.Id,
+		ReviewerIDs:   []string{member.Id},
+	})
+	require.Equal(t, 200, code)
+	body, code = postTeamReorg(t, st, "/team/delete", &dto.DeleteTeamRequest{Name: newName})
+	require.Equal(t, 200, code)
+	deleted = dto.DeleteTeamResponse{}
+	require.NoError(t, json.Unmarshal(body, &deleted))
+	require.Equal(t, []string{member.Id}, deleted.Members)
+
+	reviews, code := getUserReviews(t, st, member.Id)
+	require.Equal(t, 200, code)
+	require.Empty(t, reviews.PullRequests)
+	history, code := getPRHistory(t, st, response.PR.Id)
+	require.Equal(t, 200, code)
+	require.True(t, slices.ContainsFunc(history.Events, func(e *models.PREvent) bool {
+		return e.Kind == models.EventUnassigned && e.UserId == member.Id && e.Reason == models.TriggerDeactivation
+	}))
+
+	// 5. Команду с открытым PR без перевода участников удалить нельзя, после закрытия PR - можно
+	busyAuthor := newMember()
+	busyName := newTeam(busyAuthor, newMember())
+	busyPR, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: busyAuthor.Id})
+	require.Equal(t, 201, code)
+	body, code = postTeamReorg(t, st, "/team/delete", &dto.DeleteTeamRequest{Name: busyName})
+	require.Equal(t, 409, code)
+	errResp = dto.ErrorResponse{}
+	require.NoError(t, json.Unmarshal(body, &errResp))
+	require.Equal(t, dto.ErrCodeTeamHasOpenPRs, errResp.Error.Code)
+	_, code = getTeam(t, st, busyName)
+	require.Equal(t, 200, code)
+
+	_, code = changePRStatus(t, st, "/pullRequest/close", busyPR.PR.Id)
+	require.Equal(t, 200, code)
+	_, code = postTeamReorg(t, st, "/team/delete", &dto.DeleteTeamRequest{Name: busyName})
+	require.Equal(t, 200, code)
+
+	// 6. Несуществующие команды
+	_, code = postTeamReorg(t, st, "/team/delete", &dto.DeleteTeamRequest{Name: newName})
+	require.Equal(t, 404, code)
+	_, code = postTeamReorg(t, st, "/team/merge", &dto.MergeTeamsRequest{Name: otherName, IntoName: uuid.NewString()})
+	require.Equal(t, 404, code)
+}
+
+func postTeamReorg(t *testing.T, st *Suite, path string, reqBody any) ([]byte, int) {
+	body, err := json.Marshal(reqBody)
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), "POST", path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	return recorder.Body.Bytes(), recorder.Result().StatusCode
+}
@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
 	"testing"
 
+	"github.com/brianvoe/gofakeit"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -202,7 +206,7 @@ func createTeam(t *testing.T, st *Suite, reqBody *dto.AddTeamRequest) ([]byte, i
 }
 
 func getTeam(t *testing.T, st *Suite, name string) (*dto.GetTeamResponse, int) {
-	req := httptest.NewRequestWithContext(t.Context(), "GET", "/team/get?team_name="+name, nil)
+	req := httptest.NewRequestWithContext(t.Context(), "GET", "/team/get?team_name="+url.QueryEscape(name), nil)
 	recorder := httptest.NewRecorder()
 
 	st.srv.TestReq(req, recorder)
@@ -213,3 +217,79 @@ func getTeam(t *testing.T, st *Suite, name string) (*dto.GetTeamResponse, int) {
 
 	return &res, recorder.Result().StatusCode
 }
+
+// TestTeamSettings проверяет изменение квоты ревьюверов команды и её применение при создании PR
+func TestTeamSettings(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	teamName := gofakeit.Name() + uuid.NewString()
+	members := make([]*models.Member, 0, 4)
+	for range 4 {
+		members = append(members, &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		})
+	}
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    teamName,
+		Members: members,
+	})
+	require.Equal(t, 201, code)
+
+	settings, code := getTeamSettings(t, st, teamName)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 2, settings.Settings.ReviewersPerPR)
+
+	reviewersPerPR := 3
+	settings, code = updateTeamSettings(t, st, &dto.TeamSettingsRequest{
+		Name:           teamName,
+		ReviewersPerPR: &reviewersPerPR,
+	})
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 3, settings.Settings.ReviewersPerPR)
+
+	response, code, _, _ := createPR(t, st, members[0].Id)
+	require.Equal(t, 201, code)
+	require.Len(t, response.PR.Reviewers, 3)
+
+	reviewersPerPR = 0
+	_, code = updateTeamSettings(t, st, &dto.TeamSettingsRequest{
+		Name:           teamName,
+		ReviewersPerPR: &reviewersPerPR,
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func getTeamSettings(t *testing.T, st *Suite, name string) (*dto.TeamSettingsResponse, int) {
+	req := httptest.NewRequestWithContext(t.Context(), "GET", "/team/settings?team_name="+url.QueryEscape(name), nil)
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	var res dto.TeamSettingsResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+func updateTeamSettings(t *testing.T, st *Suite, reqBody *dto.TeamSettingsRequest) (*dto.TeamSettingsResponse, int) {
+	body, err := json.Marshal(reqBody)
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), "POST", "/team/settings", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	var res dto.TeamSettingsResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"pr-review/internal/http/dto"
 	"pr-review/internal/models"
+	"slices"
 	"testing"
 
 	"github.com/brianvoe/gofakeit"
@@ -176,6 +177,188 @@ func TestDeactivateActivateUser(t *testing.T) {
 	require.Equal(t, res.Members[0].IsActive, true)
 }
 
+// TestDeactivateReassignsReviews проверяет, что деактивация через POST /users/setIsActive переназначает открытые ревью
+// и сообщает, какие PR'ы и кому переданы
+func TestDeactivateReassignsReviews(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	newMember := func() *models.Member {
+		return &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		}
+	}
+	author, first, second, spare := newMember(), newMember(), newMember(), newMember()
+	spare.IsActive = false
+	teamName := gofakeit.Name() + uuid.NewString()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    teamName,
+		Members: []*models.Member{author, first, second, spare},
+	})
+	require.Equal(t, 201, code)
+
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+	require.Equal(t, 201, code)
+	require.ElementsMatch(t, []string{first.Id, second.Id}, response.PR.Reviewers)
+	prId := response.PR.Id
+
+	// 1. Замена нашлась
+	isActive := true
+	_, code = setIsActive(t, st, &dto.SetIsActiveRequest{UserId: spare.Id, IsActive: &isActive})
+	require.Equal(t, 200, code)
+	isActive = false
+	res, code := setIsActive(t, st, &dto.SetIsActiveRequest{UserId: first.Id, IsActive: &isActive})
+	require.Equal(t, 200, code)
+	require.False(t, res.User.IsActive)
+	require.Len(t, res.Reassigned, 1)
+	require.Equal(t, prId, res.Reassigned[0].PrId)
+	require.Equal(t, []string{spare.Id}, res.Reassigned[0].ReplacedBy)
+
+	pr, code := getPR(t, st, prId)
+	require.Equal(t, 200, code)
+	require.ElementsMatch(t, []string{second.Id, spare.Id}, pr.PR.Reviewers)
+
+	// 2. Замены нет, у PR'а выставляется need_more_reviewers
+	res, code = setIsActive(t, st, &dto.SetIsActiveRequest{UserId: second.Id, IsActive: &isActive})
+	require.Equal(t, 200, code)
+	require.Len(t, res.Reassigned, 1)
+	require.Empty(t, res.Reassigned[0].ReplacedBy)
+
+	pr, code = getPR(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Equal(t, []string{spare.Id}, pr.PR.Reviewers)
+	require.True(t, pr.NeedMoreReviewers)
+	history, code := getPRHistory(t, st, prId)
+	require.Equal(t, 200, code)
+	require.True(t, slices.ContainsFunc(history.Events, func(e *models.PREvent) bool {
+		return e.Kind == models.EventUnassigned && e.UserId == second.Id && e.Reason == models.TriggerDeactivation
+	}))
+
+	// 3. Неизвестный пользователь
+	_, code = setIsActive(t, st, &dto.SetIsActiveRequest{UserId: uuid.NewString(), IsActive: &isActive})
+	require.Equal(t, 404, code)
+}
+
+// TestBulkSetIsActive проверяет массовую смену активности по списку пользователей и по команде:
+// атомарность, переназначение ревью деактивированных и результат по каждому пользователю
+func TestBulkSetIsActive(t *testing.T) {
+	st := NewSuite()
+	st.Start()
+	t.Cleanup(func() {
+		st.srv.Stop()
+	})
+
+	newMember := func() *models.Member {
+		return &models.Member{
+			Id:       uuid.NewString(),
+			Username: gofakeit.Name() + uuid.NewString(),
+			IsActive: true,
+		}
+	}
+	author, first, second, spare := newMember(), newMember(), newMember(), newMember()
+	spare.IsActive = false
+	teamName := gofakeit.Name() + uuid.NewString()
+	_, code := createTeam(t, st, &dto.AddTeamRequest{
+		Name:    teamName,
+		Members: []*models.Member{author, first, second, spare},
+	})
+	require.Equal(t, 201, code)
+
+	response, code := createPRWithRequest(t, st, &dto.CreatePRRequest{AuthorID: author.Id})
+	require.Equal(t, 201, code)
+	require.ElementsMatch(t, []string{first.Id, second.Id}, response.PR.Reviewers)
+	prId := response.PR.Id
+
+	byUser := func(res *dto.BulkSetIsActiveResponse) map[string]*models.ActivationChange {
+		changes := make(map[string]*models.ActivationChange, len(res.Users))
+		for _, c := range res.Users {
+			changes[c.UserId] = c
+		}
+		return changes
+	}
+
+	// 1. Активация: уже активный пользователь не меняется
+	isActive := true
+	res, code := bulkSetIsActive(t, st, &dto.BulkSetIsActiveRequest{UserIds: []string{spare.Id, author.Id}, IsActive: &isActive})
+	require.Equal(t, 200, code)
+	changes := byUser(res)
+	require.Len(t, changes, 2)
+	require.True(t, changes[spare.Id].Changed)
+	require.False(t, changes[author.Id].Changed)
+	require.Empty(t, changes[spare.Id].Reassigned)
+
+	// 2. Неизвестный пользователь откатывает изменения всех
+	isActive = false
+	_, code = bulkSetIsActive(t, st, &dto.BulkSetIsActiveRequest{UserIds: []string{first.Id, uuid.NewString()}, IsActive: &isActive})
+	require.Equal(t, 404, code)
+	pr, code := getPR(t, st, prId)
+	require.Equal(t, 200, code)
+	require.ElementsMatch(t, []string{first.Id, second.Id}, pr.PR.Reviewers)
+
+	// 3. Деактивация по списку переназначает ревью
+	res, code = bulkSetIsActive(t, st, &dto.BulkSetIsActiveRequest{UserIds: []string{first.Id}, IsActive: &isActive})
+	require.Equal(t, 200, code)
+	require.Len(t, res.Users, 1)
+	require.True(t, res.Users[0].Changed)
+	require.Len(t, res.Users[0].Reassigned, 1)
+	require.Equal(t, prId, res.Users[0].Reassigned[0].PrId)
+	require.Equal(t, []string{spare.Id}, res.Users[0].Reassigned[0].ReplacedBy)
+
+	// 4. Деактивация всей команды: замены нет, у PR'а выставляется need_more_reviewers
+	res, code = bulkSetIsActive(t, st, &dto.BulkSetIsActiveRequest{TeamName: teamName, IsActive: &isActive})
+	require.Equal(t, 200, code)
+	changes = byUser(res)
+	require.Len(t, changes, 4)
+	require.False(t, changes[first.Id].Changed)
+	for _, id := range []string{author.Id, second.Id, spare.Id} {
+		require.True(t, changes[id].Changed)
+		require.False(t, changes[id].IsActive)
+	}
+	for _, id := range []string{second.Id, spare.Id} {
+		require.Len(t, changes[id].Reassigned, 1)
+		require.Empty(t, changes[id].Reassigned[0].ReplacedBy)
+	}
+
+	pr, code = getPR(t, st, prId)
+	require.Equal(t, 200, code)
+	require.Empty(t, pr.PR.Reviewers)
+	require.True(t, pr.NeedMoreReviewers)
+	history, code := getPRHistory(t, st, prId)
+	require.Equal(t, 200, code)
+	require.True(t, slices.ContainsFunc(history.Events, func(e *models.PREvent) bool {
+		return e.Kind == models.EventUnassigned && e.UserId == spare.Id && e.Reason == models.TriggerDeactivation
+	}))
+
+	// 5. Неверные запросы
+	_, code = bulkSetIsActive(t, st, &dto.BulkSetIsActiveRequest{UserIds: []string{first.Id}, TeamName: teamName, IsActive: &isActive})
+	require.Equal(t, 400, code)
+	_, code = bulkSetIsActive(t, st, &dto.BulkSetIsActiveRequest{UserIds: []string{first.Id, first.Id}, IsActive: &isActive})
+	require.Equal(t, 400, code)
+	_, code = bulkSetIsActive(t, st, &dto.BulkSetIsActiveRequest{TeamName: uuid.NewString(), IsActive: &isActive})
+	require.Equal(t, 404, code)
+}
+
+func bulkSetIsActive(t *testing.T, st *Suite, reqBody *dto.BulkSetIsActiveRequest) (*dto.BulkSetIsActiveResponse, int) {
+	body, err := json.Marshal(reqBody)
+	require.NoError(t, err)
+	req := httptest.NewRequestWithContext(t.Context(), "POST", "/users/bulkSetIsActive", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(req, recorder)
+
+	var res dto.BulkSetIsActiveResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
 func setIsActive(t *testing.T, st *Suite, reqBody *dto.SetIsActiveRequest) (*dto.SetIsActiveResponse, int) {
 	body, _ := json.Marshal(reqBody)
 	httpReq := httptest.NewRequestWithContext(t.Context(), "POST", "/users/setIsActive", bytes.NewBuffer(body))
@@ -190,3 +373,33 @@ func setIsActive(t *testing.T, st *Suite, reqBody *dto.SetIsActiveRequest) (*dto
 
 	return &res, recorder.Result().StatusCode
 }
+
+func setTags(t *testing.T, st *Suite, reqBody *dto.SetTagsRequest) (*dto.SetTagsResponse, int) {
+	body, _ := json.Marshal(reqBody)
+	httpReq := httptest.NewRequestWithContext(t.Context(), "POST", "/users/setTags", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.SetTagsResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
+
+func setMaxOpenReviews(t *testing.T, st *Suite, reqBody *dto.SetMaxOpenReviewsRequest) (*dto.SetMaxOpenReviewsResponse, int) {
+	body, _ := json.Marshal(reqBody)
+	httpReq := httptest.NewRequestWithContext(t.Context(), "POST", "/users/setMaxOpenReviews", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	st.srv.TestReq(httpReq, recorder)
+
+	var res dto.SetMaxOpenReviewsResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return &res, recorder.Result().StatusCode
+}
@@ -11,6 +11,7 @@ type Config struct {
 	Env string `envconfig:"ENV"`
 	*ApplicationConfig
 	*DatabaseConfig
+	*ReviewConfig
 }
 
 type ApplicationConfig struct {
@@ -29,6 +30,22 @@ type DatabaseConfig struct {
 	Name     string `envconfig:"POSTGRES_DB" env-required:"true" json:"-"`
 }
 
+type ReviewConfig struct {
+	// DefaultStrategy стратегия выбора ревьюверов для команд, у которых она не задана
+	DefaultStrategy string `envconfig:"REVIEW_DEFAULT_STRATEGY" default:"RANDOM"`
+	// AbsenceCheckInterval как часто проверять начавшиеся периоды отсутствия и снимать ревью с отсутствующих
+	AbsenceCheckInterval time.Duration `envconfig:"REVIEW_ABSENCE_CHECK_INTERVAL" default:"1m"`
+	// BackfillInterval как часто дозаполнять ревьюверами открытые PR'ы с need_more_reviewers
+	BackfillInterval time.Duration `envconfig:"REVIEW_BACKFILL_INTERVAL" default:"1m"`
+	// EscalationCheckInterval как часто искать ревью, нарушившие SLA команды, и переназначать их
+	EscalationCheckInterval time.Duration `envconfig:"REVIEW_ESCALATION_CHECK_INTERVAL" default:"1m"`
+	// WorkdayStartHour и WorkdayEndHour рабочее окно будних дней, в котором идёт отсчёт SLA ревью
+	WorkdayStartHour int `envconfig:"REVIEW_WORKDAY_START_HOUR" default:"9"`
+	WorkdayEndHour   int `envconfig:"REVIEW_WORKDAY_END_HOUR" default:"18"`
+	// Timezone часовой пояс рабочего окна в формате IANA (Europe/Moscow)
+	Timezone string `envconfig:"REVIEW_TIMEZONE" default:"UTC"`
+}
+
 func MustParseConfig() *Config {
 	environment := os.Getenv("ENV")
 	if environment == "" {
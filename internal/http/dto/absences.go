@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"pr-review/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxAbsenceReasonLength = 255
+)
+
+var (
+	ErrAbsenceIdRequired = Error(
+		ErrCodeBadRequest,
+		"absence_id is required",
+	)
+	ErrAbsenceIdShouldBeUuid = Error(
+		ErrCodeBadRequest,
+		"absence_id should be uuid",
+	)
+	ErrAbsencePeriodRequired = Error(
+		ErrCodeBadRequest,
+		"starts_at and ends_at are required",
+	)
+	ErrAbsenceEndsBeforeStart = Error(
+		ErrCodeBadRequest,
+		"ends_at should be after starts_at",
+	)
+	ErrAbsenceAlreadyEnded = Error(
+		ErrCodeBadRequest,
+		"ends_at should be in the future",
+	)
+	ErrAbsenceReasonTooLong = Error(
+		ErrCodeBadRequest,
+		"reason is too long",
+	)
+	ErrAbsenceNotFound = Error(
+		ErrCodeNotFound,
+		"absence not found",
+	)
+)
+
+// AddAbsenceRequest период отсутствия [starts_at, ends_at) в формате RFC 3339
+type AddAbsenceRequest struct {
+	UserId   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+func (r *AddAbsenceRequest) Validate() *ErrorResponse {
+	if r.UserId == "" {
+		return ErrUserIdRequired
+	}
+	if _, err := uuid.Parse(r.UserId); err != nil {
+		return ErrUserIdShouldBeUuid
+	}
+	if r.StartsAt.IsZero() || r.EndsAt.IsZero() {
+		return ErrAbsencePeriodRequired
+	}
+	if !r.EndsAt.After(r.StartsAt) {
+		return ErrAbsenceEndsBeforeStart
+	}
+	if !r.EndsAt.After(time.Now()) {
+		return ErrAbsenceAlreadyEnded
+	}
+	if len(r.Reason) > maxAbsenceReasonLength {
+		return ErrAbsenceReasonTooLong
+	}
+	return nil
+}
+
+type AddAbsenceResponse struct {
+	Absence *models.Absence `json:"absence"`
+}
+
+type DeleteAbsenceRequest struct {
+	Id string `json:"absence_id"`
+}
+
+func (r *DeleteAbsenceRequest) Validate() *ErrorResponse {
+	if r.Id == "" {
+		return ErrAbsenceIdRequired
+	}
+	if _, err := uuid.Parse(r.Id); err != nil {
+		return ErrAbsenceIdShouldBeUuid
+	}
+	return nil
+}
+
+type GetAbsencesResponse struct {
+	UserId   string            `json:"user_id"`
+	Absences []*models.Absence `json:"absences"`
+}
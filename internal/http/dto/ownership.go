@@ -0,0 +1,116 @@
+package dto
+
+import (
+	"pr-review/internal/models"
+	"pr-review/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxCodeownersSize = 1 << 20
+)
+
+var (
+	ErrRuleIdRequired = Error(
+		ErrCodeBadRequest,
+		"rule_id is required",
+	)
+	ErrRuleIdShouldBeUuid = Error(
+		ErrCodeBadRequest,
+		"rule_id should be uuid",
+	)
+	ErrPatternRequired = Error(
+		ErrCodeBadRequest,
+		"pattern is required",
+	)
+	ErrInvalidPattern = Error(
+		ErrCodeBadRequest,
+		"invalid pattern",
+	)
+	ErrOwnersRequired = Error(
+		ErrCodeBadRequest,
+		"teams or users are required",
+	)
+	ErrOwnerUserIdShouldBeUuid = Error(
+		ErrCodeBadRequest,
+		"users should contain uuids",
+	)
+	ErrCodeownersRequired = Error(
+		ErrCodeBadRequest,
+		"content is required",
+	)
+	ErrCodeownersTooLarge = Error(
+		ErrCodeBadRequest,
+		"content is too large",
+	)
+)
+
+type AddOwnershipRuleRequest struct {
+	Pattern string   `json:"pattern"`
+	Teams   []string `json:"teams"`
+	Users   []string `json:"users"`
+}
+
+func (r *AddOwnershipRuleRequest) Validate() *ErrorResponse {
+	if r.Pattern == "" {
+		return ErrPatternRequired
+	}
+	if len(r.Pattern) > maxFilePathLength {
+		return ErrInvalidPattern
+	}
+	if _, err := utils.CompileOwnersPattern(r.Pattern); err != nil {
+		return ErrInvalidPattern
+	}
+	if len(r.Teams) == 0 && len(r.Users) == 0 {
+		return ErrOwnersRequired
+	}
+	for _, team := range r.Teams {
+		if team == "" {
+			return ErrTeamNameRequired
+		}
+	}
+	for _, userId := range r.Users {
+		if _, err := uuid.Parse(userId); err != nil {
+			return ErrOwnerUserIdShouldBeUuid
+		}
+	}
+	return nil
+}
+
+type AddOwnershipRuleResponse struct {
+	Rule *models.OwnershipRule `json:"rule"`
+}
+
+type DeleteOwnershipRuleRequest struct {
+	Id string `json:"rule_id"`
+}
+
+func (r *DeleteOwnershipRuleRequest) Validate() *ErrorResponse {
+	if r.Id == "" {
+		return ErrRuleIdRequired
+	}
+	if _, err := uuid.Parse(r.Id); err != nil {
+		return ErrRuleIdShouldBeUuid
+	}
+	return nil
+}
+
+// ImportCodeownersRequest содержимое файла CODEOWNERS. Импорт заменяет все существующие правила
+type ImportCodeownersRequest struct {
+	Content string `json:"content"`
+}
+
+func (r *ImportCodeownersRequest) Validate() *ErrorResponse {
+	if r.Content == "" {
+		return ErrCodeownersRequired
+	}
+	if len(r.Content) > maxCodeownersSize {
+		return ErrCodeownersTooLarge
+	}
+	return nil
+}
+
+type OwnershipRulesResponse struct {
+	Rules []*models.OwnershipRule `json:"rules"`
+}
@@ -1,9 +1,13 @@
 package dto
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"net/url"
 	"pr-review/internal/models"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -37,6 +41,58 @@ var (
 		ErrCodeBadRequest,
 		"old_reviewer_id should be uuid",
 	)
+	ErrTooManyFiles = Error(
+		ErrCodeBadRequest,
+		"too many files",
+	)
+	ErrInvalidFilePath = Error(
+		ErrCodeBadRequest,
+		"files should contain non-empty paths",
+	)
+	ErrInvalidLabels = Error(
+		ErrCodeBadRequest,
+		"labels should contain at most 20 non-empty labels up to 32 characters",
+	)
+	ErrReviewerIdRequired = Error(
+		ErrCodeBadRequest,
+		"reviewer_id is required",
+	)
+	ErrReviewerIdShouldBeUuid = Error(
+		ErrCodeBadRequest,
+		"reviewer_id should be uuid",
+	)
+	ErrReviewerIdsRequired = Error(
+		ErrCodeBadRequest,
+		"reviewer_ids is required",
+	)
+	ErrReviewerIdsShouldBeUuid = Error(
+		ErrCodeBadRequest,
+		"reviewer_ids should contain only uuids",
+	)
+	ErrTooManyReviewers = Error(
+		ErrCodeBadRequest,
+		"too many reviewer_ids",
+	)
+	ErrUnknownReviewDecision = Error(
+		ErrCodeBadRequest,
+		"decision should be one of APPROVED, CHANGES_REQUESTED, COMMENTED",
+	)
+	ErrReviewCommentTooLong = Error(
+		ErrCodeBadRequest,
+		"comment is too long",
+	)
+	ErrPRTitleTooLong = Error(
+		ErrCodeBadRequest,
+		"pull_request_name is too long",
+	)
+	ErrNothingToUpdate = Error(
+		ErrCodeBadRequest,
+		"pull_request_name or author_id is required",
+	)
+	ErrUnknownReviewersPolicy = Error(
+		ErrCodeBadRequest,
+		"reviewers_policy should be one of KEEP, REPLACE",
+	)
 	ErrPageShouldBePositiveInt = Error(
 		ErrCodeBadRequest,
 		"page should be positive number",
@@ -45,6 +101,50 @@ var (
 		ErrCodeBadRequest,
 		"limit should be positive number",
 	)
+	ErrLimitTooLarge = Error(
+		ErrCodeBadRequest,
+		"limit should be at most 100",
+	)
+	ErrUnknownStatus = Error(
+		ErrCodeBadRequest,
+		"status should be comma-separated list of DRAFT, OPEN, MERGED, CLOSED",
+	)
+	ErrInvalidNeedMoreReviewers = Error(
+		ErrCodeBadRequest,
+		"need_more_reviewers should be true or false",
+	)
+	ErrInvalidDateRange = Error(
+		ErrCodeBadRequest,
+		"created_from, created_to, merged_from and merged_to should be RFC 3339 dates",
+	)
+	ErrSearchQueryTooLong = Error(
+		ErrCodeBadRequest,
+		"q should be at most 255 characters",
+	)
+	ErrUnknownSort = Error(
+		ErrCodeBadRequest,
+		"sort should be created_at, merged_at or title",
+	)
+	ErrUnknownOrder = Error(
+		ErrCodeBadRequest,
+		"order should be asc or desc",
+	)
+	ErrInvalidCursor = Error(
+		ErrCodeBadRequest,
+		"cursor is invalid or was issued for another sort",
+	)
+)
+
+const (
+	defaultPRListLimit = 20
+	maxPRListLimit     = 100
+)
+
+const (
+	maxPRFiles            = 1000
+	maxFilePathLength     = 1024
+	maxReviewCommentSize  = 10000
+	maxRequestedReviewers = 20
 )
 
 var (
@@ -52,12 +152,28 @@ var (
 	ErrCodeNoCandidates           ErrorCode = "NO_CANDIDATES"
 	ErrCodeCannotReassignMergedPR ErrorCode = "PR_MERGED"
 	ErrCodeUserNotReviewerOfPR    ErrorCode = "NOT_ASSIGNED"
+	ErrCodeNotEnoughApprovals     ErrorCode = "NOT_ENOUGH_APPROVALS"
+	ErrCodeIllegalTransition      ErrorCode = "ILLEGAL_TRANSITION"
+	ErrCodePRClosed               ErrorCode = "PR_CLOSED"
+	ErrCodePRDraft                ErrorCode = "PR_DRAFT"
+	// ErrCodeReviewerUnavailable запрошенный ревьювер - автор, неактивен или запрещён правилом команды
+	ErrCodeReviewerUnavailable ErrorCode = "REVIEWER_UNAVAILABLE"
+	// ErrCodeAuthorWithoutTeam у автора PR'а нет основной команды или он не состоит в указанной
+	ErrCodeAuthorWithoutTeam ErrorCode = "NO_TEAM"
 )
 
 type CreatePRRequest struct {
 	Id       string `json:"pull_request_id"`
 	Title    string `json:"pull_request_name"`
 	AuthorID string `json:"author_id"`
+	// Files изменённые файлы PR'а, по ним подбираются владельцы путей
+	Files []string `json:"files,omitempty"`
+	// Labels метки PR'а, ревьюверы подбираются так, чтобы каждую метку покрывал тег хотя бы одного из них
+	Labels []string `json:"labels,omitempty"`
+	// Draft создать черновик: ревьюверы назначаются, когда он станет готов к ревью
+	Draft bool `json:"draft,omitempty"`
+	// TeamName команда автора, из которой подбираются ревьюверы. По умолчанию - основная команда автора
+	TeamName string `json:"team_name,omitempty"`
 }
 
 func (r *CreatePRRequest) Validate() *ErrorResponse {
@@ -76,6 +192,17 @@ func (r *CreatePRRequest) Validate() *ErrorResponse {
 	if _, err := uuid.Parse(r.AuthorID); err != nil {
 		return ErrAuthorIdShouldBeUuid
 	}
+	if len(r.Files) > maxPRFiles {
+		return ErrTooManyFiles
+	}
+	for _, path := range r.Files {
+		if path == "" || len(path) > maxFilePathLength {
+			return ErrInvalidFilePath
+		}
+	}
+	if !validTags(r.Labels) {
+		return ErrInvalidLabels
+	}
 	return nil
 }
 
@@ -101,6 +228,70 @@ type MergePRResponse struct {
 	PR *models.PullRequest `json:"pr"`
 }
 
+// UpdatePRRequest запрос на изменение PR'а, непереданные поля не меняются
+type UpdatePRRequest struct {
+	PullRequestID string  `json:"pull_request_id"`
+	Title         *string `json:"pull_request_name"`
+	AuthorID      *string `json:"author_id"`
+	// ReviewersPolicy что делать с ревьюверами, если новый автор из другой команды, по умолчанию KEEP
+	ReviewersPolicy models.AuthorChangePolicy `json:"reviewers_policy,omitempty"`
+}
+
+func (r *UpdatePRRequest) Validate() *ErrorResponse {
+	if r.PullRequestID == "" {
+		return ErrPRIdRequired
+	}
+	if _, err := uuid.Parse(r.PullRequestID); err != nil {
+		return ErrPRIdShouldBeUuid
+	}
+	if r.Title == nil && r.AuthorID == nil {
+		return ErrNothingToUpdate
+	}
+	if r.Title != nil {
+		if *r.Title == "" {
+			return ErrPRTitleRequired
+		}
+		if len(*r.Title) > 255 {
+			return ErrPRTitleTooLong
+		}
+	}
+	if r.AuthorID != nil {
+		if *r.AuthorID == "" {
+			return ErrAuthorIdRequired
+		}
+		if _, err := uuid.Parse(*r.AuthorID); err != nil {
+			return ErrAuthorIdShouldBeUuid
+		}
+	}
+	if r.ReviewersPolicy != "" && !r.ReviewersPolicy.Valid() {
+		return ErrUnknownReviewersPolicy
+	}
+	return nil
+}
+
+type UpdatePRResponse struct {
+	PR *models.PullRequest `json:"pr"`
+}
+
+// ChangePRStatusRequest запрос на перевод PR'а в другой статус (готов к ревью, закрыт, переоткрыт)
+type ChangePRStatusRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+func (r *ChangePRStatusRequest) Validate() *ErrorResponse {
+	if r.PullRequestID == "" {
+		return ErrPRIdRequired
+	}
+	if _, err := uuid.Parse(r.PullRequestID); err != nil {
+		return ErrPRIdShouldBeUuid
+	}
+	return nil
+}
+
+type ChangePRStatusResponse struct {
+	PR *models.PullRequest `json:"pr"`
+}
+
 type ReassignPRRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldReviewerID string `json:"old_reviewer_id"`
@@ -127,6 +318,126 @@ type ReassignPRResponse struct {
 	ReplacedBy string              `json:"replaced_by"`
 }
 
+// AddReviewersRequest запрос на назначение конкретных ревьюверов, в том числе из других команд
+type AddReviewersRequest struct {
+	PullRequestID string   `json:"pull_request_id"`
+	ReviewerIDs   []string `json:"reviewer_ids"`
+}
+
+func (r *AddReviewersRequest) Validate() *ErrorResponse {
+	if r.PullRequestID == "" {
+		return ErrPRIdRequired
+	}
+	if _, err := uuid.Parse(r.PullRequestID); err != nil {
+		return ErrPRIdShouldBeUuid
+	}
+	if len(r.ReviewerIDs) == 0 {
+		return ErrReviewerIdsRequired
+	}
+	if len(r.ReviewerIDs) > maxRequestedReviewers {
+		return ErrTooManyReviewers
+	}
+	for _, id := range r.ReviewerIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return ErrReviewerIdsShouldBeUuid
+		}
+	}
+	return nil
+}
+
+type AddReviewersResponse struct {
+	PR *models.PullRequest `json:"pr"`
+	// Added ревьюверы, назначенные этим запросом (уже назначенные не повторяются)
+	Added []string `json:"added"`
+}
+
+// RemoveReviewerRequest запрос на снятие ревьювера с PR'а
+type RemoveReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	// Backfill сразу подобрать недостающих до квоты команды ревьюверов
+	Backfill bool `json:"backfill,omitempty"`
+}
+
+func (r *RemoveReviewerRequest) Validate() *ErrorResponse {
+	if r.PullRequestID == "" {
+		return ErrPRIdRequired
+	}
+	if _, err := uuid.Parse(r.PullRequestID); err != nil {
+		return ErrPRIdShouldBeUuid
+	}
+	if r.ReviewerID == "" {
+		return ErrReviewerIdRequired
+	}
+	if _, err := uuid.Parse(r.ReviewerID); err != nil {
+		return ErrReviewerIdShouldBeUuid
+	}
+	return nil
+}
+
+type RemoveReviewerResponse struct {
+	PR         *models.PullRequest `json:"pr"`
+	ReplacedBy string              `json:"replaced_by,omitempty"`
+}
+
+type ReviewPRRequest struct {
+	PullRequestID string                `json:"pull_request_id"`
+	ReviewerID    string                `json:"reviewer_id"`
+	Decision      models.ReviewDecision `json:"decision"`
+	Comment       string                `json:"comment,omitempty"`
+}
+
+func (r *ReviewPRRequest) Validate() *ErrorResponse {
+	if r.PullRequestID == "" {
+		return ErrPRIdRequired
+	}
+	if _, err := uuid.Parse(r.PullRequestID); err != nil {
+		return ErrPRIdShouldBeUuid
+	}
+	if r.ReviewerID == "" {
+		return ErrReviewerIdRequired
+	}
+	if _, err := uuid.Parse(r.ReviewerID); err != nil {
+		return ErrReviewerIdShouldBeUuid
+	}
+	if !r.Decision.Valid() {
+		return ErrUnknownReviewDecision
+	}
+	if len(r.Comment) > maxReviewCommentSize {
+		return ErrReviewCommentTooLong
+	}
+	return nil
+}
+
+type ReviewPRResponse struct {
+	PR     *models.PullRequest `json:"pr"`
+	Review *models.Review      `json:"review"`
+}
+
+type AssignmentExplainResponse struct {
+	PullRequestID string                       `json:"pull_request_id"`
+	Decisions     []*models.AssignmentDecision `json:"decisions"`
+}
+
+type GetPRResponse struct {
+	PR                *models.PullRequest `json:"pr"`
+	NeedMoreReviewers bool                `json:"need_more_reviewers"`
+	// Author автор PR'а с его командой
+	Author *models.User `json:"author"`
+	// Reviewers назначенные ревьюверы с username, командой и активностью
+	Reviewers []*models.User `json:"reviewers"`
+}
+
+type PRHistoryResponse struct {
+	PullRequestID string            `json:"pull_request_id"`
+	Events        []*models.PREvent `json:"events"`
+}
+
+type OverduePRsResponse struct {
+	TeamName     string              `json:"team_name"`
+	PullRequests []*models.OverduePR `json:"pull_requests"`
+}
+
 type StatisticsRequest struct {
 	Page  int
 	Limit int
@@ -161,3 +472,142 @@ func MapQueryToStatisticsRequest(query url.Values) (*StatisticsRequest, *ErrorRe
 		Limit: limit,
 	}, nil
 }
+
+// ListPRsRequest фильтры, сортировка и страница GET /pullRequest/list
+type ListPRsRequest struct {
+	models.PRListFilter
+}
+
+type ListPRsResponse struct {
+	PullRequests []*models.PullRequest `json:"pull_requests"`
+	// NextCursor передаётся в cursor для получения следующей страницы, пусто - страница последняя
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func MapQueryToListPRsRequest(query url.Values) (*ListPRsRequest, *ErrorResponse) {
+	req := &ListPRsRequest{
+		PRListFilter: models.PRListFilter{
+			AuthorId:   query.Get("author_id"),
+			TeamName:   query.Get("team_name"),
+			ReviewerId: query.Get("reviewer_id"),
+			Query:      query.Get("q"),
+			Sort:       models.PRSortCreatedAt,
+			Desc:       true,
+			Limit:      defaultPRListLimit,
+		},
+	}
+
+	if statuses := query.Get("status"); statuses != "" {
+		for _, status := range strings.Split(statuses, ",") {
+			status := models.Status(strings.ToUpper(strings.TrimSpace(status)))
+			if !status.Valid() {
+				return nil, ErrUnknownStatus
+			}
+			req.Statuses = append(req.Statuses, status)
+		}
+	}
+	if req.AuthorId != "" {
+		if _, err := uuid.Parse(req.AuthorId); err != nil {
+			return nil, ErrAuthorIdShouldBeUuid
+		}
+	}
+	if req.ReviewerId != "" {
+		if _, err := uuid.Parse(req.ReviewerId); err != nil {
+			return nil, ErrReviewerIdShouldBeUuid
+		}
+	}
+	if needMore := query.Get("need_more_reviewers"); needMore != "" {
+		value, err := strconv.ParseBool(needMore)
+		if err != nil {
+			return nil, ErrInvalidNeedMoreReviewers
+		}
+		req.NeedMoreReviewers = &value
+	}
+
+	dates := []struct {
+		param string
+		dst   **time.Time
+	}{
+		{"created_from", &req.CreatedFrom},
+		{"created_to", &req.CreatedTo},
+		{"merged_from", &req.MergedFrom},
+		{"merged_to", &req.MergedTo},
+	}
+	for _, date := range dates {
+		value := query.Get(date.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		*date.dst = &parsed
+	}
+
+	if len([]rune(req.Query)) > 255 {
+		return nil, ErrSearchQueryTooLong
+	}
+	if sort := query.Get("sort"); sort != "" {
+		req.Sort = models.PRSort(sort)
+		if !req.Sort.Valid() {
+			return nil, ErrUnknownSort
+		}
+	}
+	switch query.Get("order") {
+	case "", "desc":
+	case "asc":
+		req.Desc = false
+	default:
+		return nil, ErrUnknownOrder
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return nil, ErrLimitShouldBePositiveInt
+		}
+		if limit > maxPRListLimit {
+			return nil, ErrLimitTooLarge
+		}
+		req.Limit = limit
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := decodePRCursor(cursor)
+		if err != nil || after.Sort != req.Sort || after.Desc != req.Desc {
+			return nil, ErrInvalidCursor
+		}
+		req.After = after
+	}
+
+	return req, nil
+}
+
+// EncodePRCursor кодирует позицию в списке PR'ов в непрозрачную строку для next_cursor, nil - пустая строка
+func EncodePRCursor(cursor *models.PRCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePRCursor(cursor string) (*models.PRCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	var after models.PRCursor
+	if err := json.Unmarshal(data, &after); err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(after.Id); err != nil {
+		return nil, err
+	}
+	if after.Sort != models.PRSortTitle {
+		if _, err := time.Parse(time.RFC3339Nano, after.Value); err != nil {
+			return nil, err
+		}
+	}
+	return &after, nil
+}
@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"pr-review/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCodeRuleExists ErrorCode = "RULE_EXISTS"
+)
+
+var (
+	ErrUnknownRuleKind = Error(
+		ErrCodeBadRequest,
+		"kind should be one of EXCLUDE, INCLUDE",
+	)
+	ErrRuleUserIdShouldBeUuid = Error(
+		ErrCodeBadRequest,
+		"author_id and reviewer_id should be uuid",
+	)
+	ErrRuleAuthorIsReviewer = Error(
+		ErrCodeBadRequest,
+		"author_id and reviewer_id should differ",
+	)
+	ErrReviewerRuleNotFound = Error(
+		ErrCodeNotFound,
+		"reviewer rule not found",
+	)
+)
+
+type AddReviewerRuleRequest struct {
+	TeamName   string                  `json:"team_name"`
+	Kind       models.ReviewerRuleKind `json:"kind"`
+	AuthorId   string                  `json:"author_id"`
+	ReviewerId string                  `json:"reviewer_id"`
+}
+
+func (r *AddReviewerRuleRequest) Validate() *ErrorResponse {
+	if r.TeamName == "" {
+		return ErrTeamNameRequired
+	}
+	if !r.Kind.Valid() {
+		return ErrUnknownRuleKind
+	}
+	if _, err := uuid.Parse(r.AuthorId); err != nil {
+		return ErrRuleUserIdShouldBeUuid
+	}
+	if _, err := uuid.Parse(r.ReviewerId); err != nil {
+		return ErrRuleUserIdShouldBeUuid
+	}
+	if r.AuthorId == r.ReviewerId {
+		return ErrRuleAuthorIsReviewer
+	}
+	return nil
+}
+
+type AddReviewerRuleResponse struct {
+	Rule *models.ReviewerRule `json:"rule"`
+}
+
+type DeleteReviewerRuleRequest struct {
+	Id string `json:"rule_id"`
+}
+
+func (r *DeleteReviewerRuleRequest) Validate() *ErrorResponse {
+	if r.Id == "" {
+		return ErrRuleIdRequired
+	}
+	if _, err := uuid.Parse(r.Id); err != nil {
+		return ErrRuleIdShouldBeUuid
+	}
+	return nil
+}
+
+type ReviewerRulesResponse struct {
+	TeamName string                 `json:"team_name"`
+	Rules    []*models.ReviewerRule `json:"rules"`
+}
@@ -7,8 +7,9 @@ import (
 )
 
 var (
-	ErrCodeTeamExists ErrorCode = "TEAM_EXISTS"
-	ErrCodeUserExists ErrorCode = "USER_EXISTS"
+	ErrCodeTeamExists     ErrorCode = "TEAM_EXISTS"
+	ErrCodeUserExists     ErrorCode = "USER_EXISTS"
+	ErrCodeTeamHasOpenPRs ErrorCode = "TEAM_HAS_OPEN_PRS"
 )
 
 var (
@@ -16,6 +17,54 @@ var (
 		ErrCodeBadRequest,
 		"team_name is required",
 	)
+	ErrReviewersPerPROutOfRange = Error(
+		ErrCodeBadRequest,
+		"reviewers_per_pr should be between 1 and 10",
+	)
+	ErrReviewSLAHoursOutOfRange = Error(
+		ErrCodeBadRequest,
+		"review_sla_hours should be between 0 and 720",
+	)
+	ErrRequiredApprovalsOutOfRange = Error(
+		ErrCodeBadRequest,
+		"required_approvals should be between 0 and 10",
+	)
+	ErrTooManyFallbackTeams = Error(
+		ErrCodeBadRequest,
+		"too many fallback_teams",
+	)
+	ErrDuplicateFallbackTeam = Error(
+		ErrCodeBadRequest,
+		"fallback_teams should not contain duplicates",
+	)
+	ErrMembersRequired = Error(
+		ErrCodeBadRequest,
+		"members is required",
+	)
+	ErrUserIdsRequired = Error(
+		ErrCodeBadRequest,
+		"user_ids is required",
+	)
+	ErrNewTeamNameRequired = Error(
+		ErrCodeBadRequest,
+		"new_team_name is required",
+	)
+	ErrNewTeamNameTooLong = Error(
+		ErrCodeBadRequest,
+		"new_team_name is too long",
+	)
+	ErrIntoTeamNameRequired = Error(
+		ErrCodeBadRequest,
+		"into_team_name is required",
+	)
+	ErrMergeIntoSelf = Error(
+		ErrCodeBadRequest,
+		"team cannot be merged into itself",
+	)
+	ErrUnknownReviewerStrategy = Error(
+		ErrCodeBadRequest,
+		"reviewer_strategy should be one of RANDOM, ROUND_ROBIN, LEAST_LOADED, WEIGHTED_RANDOM",
+	)
 )
 
 type GetTeamResponse struct {
@@ -24,8 +73,9 @@ type GetTeamResponse struct {
 }
 
 type AddTeamRequest struct {
-	Name    string           `json:"team_name"`
-	Members []*models.Member `json:"members"`
+	Name             string                  `json:"team_name"`
+	Members          []*models.Member        `json:"members"`
+	ReviewerStrategy models.ReviewerStrategy `json:"reviewer_strategy,omitempty"`
 }
 
 func (r *AddTeamRequest) Validate() *ErrorResponse {
@@ -45,7 +95,15 @@ func (r *AddTeamRequest) Validate() *ErrorResponse {
 			},
 		}
 	}
-	if len(r.Members) > 300 {
+	if r.ReviewerStrategy != "" && !r.ReviewerStrategy.Valid() {
+		return ErrUnknownReviewerStrategy
+	}
+	return validateMembers(r.Members)
+}
+
+// validateMembers проверяет список участников команды
+func validateMembers(members []*models.Member) *ErrorResponse {
+	if len(members) > 300 {
 		return &ErrorResponse{
 			Error: &ErrorField{
 				Code:    ErrCodeBadRequest,
@@ -53,7 +111,7 @@ func (r *AddTeamRequest) Validate() *ErrorResponse {
 			},
 		}
 	}
-	for _, m := range r.Members {
+	for _, m := range members {
 		if m.Id == "" {
 			return &ErrorResponse{
 				Error: &ErrorField{
@@ -86,15 +144,172 @@ func (r *AddTeamRequest) Validate() *ErrorResponse {
 				},
 			}
 		}
+		if !validTags(m.Tags) {
+			return ErrInvalidTags
+		}
+		if m.MaxOpenReviews != nil && !validMaxOpenReviews(*m.MaxOpenReviews) {
+			return ErrMaxOpenReviewsOutOfRange
+		}
 	}
 	return nil
 }
 
-type Team struct {
+// TeamMembersRequest участники, которые добавляются в команду или составляют её новый состав (пустой - команда без участников)
+type TeamMembersRequest struct {
 	Name    string           `json:"team_name"`
 	Members []*models.Member `json:"members"`
 }
 
+func (r *TeamMembersRequest) Validate() *ErrorResponse {
+	if r.Name == "" {
+		return ErrTeamNameRequired
+	}
+	return validateMembers(r.Members)
+}
+
+type RemoveTeamMembersRequest struct {
+	Name    string   `json:"team_name"`
+	UserIds []string `json:"user_ids"`
+}
+
+func (r *RemoveTeamMembersRequest) Validate() *ErrorResponse {
+	if r.Name == "" {
+		return ErrTeamNameRequired
+	}
+	if len(r.UserIds) == 0 {
+		return ErrUserIdsRequired
+	}
+	return validateUserIds(r.UserIds)
+}
+
+type RenameTeamRequest struct {
+	Name    string `json:"team_name"`
+	NewName string `json:"new_team_name"`
+}
+
+func (r *RenameTeamRequest) Validate() *ErrorResponse {
+	if r.Name == "" {
+		return ErrTeamNameRequired
+	}
+	if r.NewName == "" {
+		return ErrNewTeamNameRequired
+	}
+	if len(r.NewName) > 255 {
+		return ErrNewTeamNameTooLong
+	}
+	return nil
+}
+
+type RenameTeamResponse struct {
+	Name         string `json:"team_name"`
+	PreviousName string `json:"previous_team_name"`
+}
+
+type DeleteTeamRequest struct {
+	Name string `json:"team_name"`
+	// MoveMembersTo команда, в которую переводятся участники; если не задана, участники деактивируются
+	MoveMembersTo string `json:"move_members_to,omitempty"`
+}
+
+func (r *DeleteTeamRequest) Validate() *ErrorResponse {
+	if r.Name == "" {
+		return ErrTeamNameRequired
+	}
+	if r.MoveMembersTo == r.Name {
+		return ErrMergeIntoSelf
+	}
+	return nil
+}
+
+type DeleteTeamResponse struct {
+	Name          string `json:"team_name"`
+	MoveMembersTo string `json:"move_members_to,omitempty"`
+	// Members id переведённых или, если move_members_to не задана, деактивированных участников (не состоявших в других командах)
+	Members []string `json:"members"`
+}
+
+type MergeTeamsRequest struct {
+	// Name команда, которая сливается и удаляется
+	Name string `json:"team_name"`
+	// IntoName команда, в которую она сливается
+	IntoName string `json:"into_team_name"`
+}
+
+func (r *MergeTeamsRequest) Validate() *ErrorResponse {
+	if r.Name == "" {
+		return ErrTeamNameRequired
+	}
+	if r.IntoName == "" {
+		return ErrIntoTeamNameRequired
+	}
+	if r.IntoName == r.Name {
+		return ErrMergeIntoSelf
+	}
+	return nil
+}
+
+type Team struct {
+	Name             string                  `json:"team_name"`
+	Members          []*models.Member        `json:"members"`
+	ReviewerStrategy models.ReviewerStrategy `json:"reviewer_strategy,omitempty"`
+}
+
 type AddTeamResponse struct {
 	Team *Team `json:"team"`
 }
+
+type TeamSettingsRequest struct {
+	Name             string                   `json:"team_name"`
+	ReviewerStrategy *models.ReviewerStrategy `json:"reviewer_strategy"`
+	ReviewersPerPR   *int                     `json:"reviewers_per_pr"`
+	FallbackTeams    *[]string                `json:"fallback_teams"`
+	// MaxOpenReviews ограничение открытых ревью по умолчанию для участников команды, 0 - без ограничения
+	MaxOpenReviews *int `json:"max_open_reviews"`
+	// RequiredApprovals сколько одобрений нужно для merge PR'а, 0 - без ограничения
+	RequiredApprovals *int `json:"required_approvals"`
+	// ReviewSLAHours SLA ревью в рабочих часах, 0 - без SLA
+	ReviewSLAHours *int `json:"review_sla_hours"`
+}
+
+func (r *TeamSettingsRequest) Validate() *ErrorResponse {
+	if r.Name == "" {
+		return ErrTeamNameRequired
+	}
+	// пустая стратегия сбрасывает её на стратегию по умолчанию
+	if r.ReviewerStrategy != nil && *r.ReviewerStrategy != "" && !r.ReviewerStrategy.Valid() {
+		return ErrUnknownReviewerStrategy
+	}
+	if r.ReviewersPerPR != nil && (*r.ReviewersPerPR < 1 || *r.ReviewersPerPR > 10) {
+		return ErrReviewersPerPROutOfRange
+	}
+	if r.MaxOpenReviews != nil && !validMaxOpenReviews(*r.MaxOpenReviews) {
+		return ErrMaxOpenReviewsOutOfRange
+	}
+	if r.RequiredApprovals != nil && (*r.RequiredApprovals < 0 || *r.RequiredApprovals > 10) {
+		return ErrRequiredApprovalsOutOfRange
+	}
+	if r.ReviewSLAHours != nil && (*r.ReviewSLAHours < 0 || *r.ReviewSLAHours > 720) {
+		return ErrReviewSLAHoursOutOfRange
+	}
+	if r.FallbackTeams != nil {
+		if len(*r.FallbackTeams) > 10 {
+			return ErrTooManyFallbackTeams
+		}
+		seen := make(map[string]bool, len(*r.FallbackTeams))
+		for _, name := range *r.FallbackTeams {
+			if name == "" {
+				return ErrTeamNameRequired
+			}
+			if seen[name] {
+				return ErrDuplicateFallbackTeam
+			}
+			seen[name] = true
+		}
+	}
+	return nil
+}
+
+type TeamSettingsResponse struct {
+	Name     string               `json:"team_name"`
+	Settings *models.TeamSettings `json:"settings"`
+}
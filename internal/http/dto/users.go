@@ -2,10 +2,16 @@ package dto
 
 import (
 	"pr-review/internal/models"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
+const (
+	ErrCodeUserOffboarded         ErrorCode = "USER_OFFBOARDED"
+	ErrCodeTransferTargetInactive ErrorCode = "TRANSFER_TARGET_INACTIVE"
+)
+
 var (
 	ErrUserIdRequired = Error(
 		ErrCodeBadRequest,
@@ -23,6 +29,61 @@ var (
 		ErrCodeNotFound,
 		"user not found",
 	)
+	ErrTagsRequired = Error(
+		ErrCodeBadRequest,
+		"tags is required",
+	)
+	ErrInvalidTags = Error(
+		ErrCodeBadRequest,
+		"tags should contain at most 20 non-empty tags up to 32 characters",
+	)
+	ErrMaxOpenReviewsRequired = Error(
+		ErrCodeBadRequest,
+		"max_open_reviews is required",
+	)
+	ErrMaxOpenReviewsOutOfRange = Error(
+		ErrCodeBadRequest,
+		"max_open_reviews should be between 0 and 1000",
+	)
+	ErrTooManyUserIds = Error(
+		ErrCodeBadRequest,
+		"too many user_ids",
+	)
+	ErrDuplicateUserIds = Error(
+		ErrCodeBadRequest,
+		"user_ids should not contain duplicates",
+	)
+	ErrUserIdsOrTeamNameRequired = Error(
+		ErrCodeBadRequest,
+		"exactly one of user_ids and team_name is required",
+	)
+	ErrUnknownOffboardPolicy = Error(
+		ErrCodeBadRequest,
+		"authored_prs_policy should be one of KEEP, CLOSE, TRANSFER",
+	)
+	ErrTransferToRequired = Error(
+		ErrCodeBadRequest,
+		"transfer_to is required for TRANSFER policy",
+	)
+	ErrTransferToShouldBeUuid = Error(
+		ErrCodeBadRequest,
+		"transfer_to should be uuid",
+	)
+	ErrTransferToWithoutTransfer = Error(
+		ErrCodeBadRequest,
+		"transfer_to is allowed only with TRANSFER policy",
+	)
+	ErrTransferToSelf = Error(
+		ErrCodeBadRequest,
+		"transfer_to should differ from user_id",
+	)
+)
+
+const (
+	maxTags           = 20
+	maxTagLength      = 32
+	maxOpenReviewsCap = 1000
+	maxUserIds        = 300
 )
 
 type SetIsActiveRequest struct {
@@ -45,9 +106,189 @@ func (r *SetIsActiveRequest) Validate() *ErrorResponse {
 
 type SetIsActiveResponse struct {
 	User *models.User `json:"user"`
+	// Reassigned снятые с деактивированного пользователя ревью и назначенные вместо него ревьюверы
+	Reassigned []*models.ReviewReassignment `json:"reassigned_reviews"`
+}
+
+// BulkSetIsActiveRequest смена активности сразу нескольких пользователей: списка user_ids или всех участников команды team_name
+type BulkSetIsActiveRequest struct {
+	UserIds  []string `json:"user_ids,omitempty"`
+	TeamName string   `json:"team_name,omitempty"`
+	IsActive *bool    `json:"is_active"`
+}
+
+func (r *BulkSetIsActiveRequest) Validate() *ErrorResponse {
+	if (len(r.UserIds) == 0) == (r.TeamName == "") {
+		return ErrUserIdsOrTeamNameRequired
+	}
+	if r.IsActive == nil {
+		return ErrIsActiveRequired
+	}
+	return validateUserIds(r.UserIds)
+}
+
+type BulkSetIsActiveResponse struct {
+	// Users результат для каждого пользователя в порядке id
+	Users []*models.ActivationChange `json:"users"`
 }
 
 type GetReviewResponse struct {
 	UserId       string                `json:"user_id"`
 	PullRequests []*models.PullRequest `json:"pull_requests"`
 }
+
+type SetTagsRequest struct {
+	UserId string   `json:"user_id"`
+	Tags   []string `json:"tags"`
+}
+
+func (r *SetTagsRequest) Validate() *ErrorResponse {
+	if r.UserId == "" {
+		return ErrUserIdRequired
+	}
+	if _, err := uuid.Parse(r.UserId); err != nil {
+		return ErrUserIdShouldBeUuid
+	}
+	if r.Tags == nil {
+		return ErrTagsRequired
+	}
+	if !validTags(r.Tags) {
+		return ErrInvalidTags
+	}
+	return nil
+}
+
+type SetTagsResponse struct {
+	User *models.User `json:"user"`
+}
+
+// SetMaxOpenReviewsRequest ограничение открытых ревью пользователя, 0 - брать ограничение команды
+type SetMaxOpenReviewsRequest struct {
+	UserId         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+func (r *SetMaxOpenReviewsRequest) Validate() *ErrorResponse {
+	if r.UserId == "" {
+		return ErrUserIdRequired
+	}
+	if _, err := uuid.Parse(r.UserId); err != nil {
+		return ErrUserIdShouldBeUuid
+	}
+	if r.MaxOpenReviews == nil {
+		return ErrMaxOpenReviewsRequired
+	}
+	if !validMaxOpenReviews(*r.MaxOpenReviews) {
+		return ErrMaxOpenReviewsOutOfRange
+	}
+	return nil
+}
+
+type SetMaxOpenReviewsResponse struct {
+	User *models.User `json:"user"`
+}
+
+// SetPrimaryTeamRequest основная команда пользователя, он должен в ней состоять
+type SetPrimaryTeamRequest struct {
+	UserId   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+func (r *SetPrimaryTeamRequest) Validate() *ErrorResponse {
+	if r.UserId == "" {
+		return ErrUserIdRequired
+	}
+	if _, err := uuid.Parse(r.UserId); err != nil {
+		return ErrUserIdShouldBeUuid
+	}
+	if r.TeamName == "" {
+		return ErrTeamNameRequired
+	}
+	return nil
+}
+
+type SetPrimaryTeamResponse struct {
+	User *models.User `json:"user"`
+}
+
+// OffboardUserRequest увольнение пользователя, Policy - что делать с его открытыми PR'ами, по умолчанию KEEP
+type OffboardUserRequest struct {
+	UserId string                `json:"user_id"`
+	Policy models.OffboardPolicy `json:"authored_prs_policy,omitempty"`
+	// TransferTo кому передать открытые PR'ы при политике TRANSFER
+	TransferTo string `json:"transfer_to,omitempty"`
+}
+
+func (r *OffboardUserRequest) Validate() *ErrorResponse {
+	if r.UserId == "" {
+		return ErrUserIdRequired
+	}
+	if _, err := uuid.Parse(r.UserId); err != nil {
+		return ErrUserIdShouldBeUuid
+	}
+	if r.Policy != "" && !r.Policy.Valid() {
+		return ErrUnknownOffboardPolicy
+	}
+	if r.Policy != models.OffboardTransfer {
+		if r.TransferTo != "" {
+			return ErrTransferToWithoutTransfer
+		}
+		return nil
+	}
+	if r.TransferTo == "" {
+		return ErrTransferToRequired
+	}
+	if _, err := uuid.Parse(r.TransferTo); err != nil {
+		return ErrTransferToShouldBeUuid
+	}
+	if r.TransferTo == r.UserId {
+		return ErrTransferToSelf
+	}
+	return nil
+}
+
+type OffboardUserResponse struct {
+	User   *models.User          `json:"user"`
+	Policy models.OffboardPolicy `json:"authored_prs_policy"`
+	// ReassignedPRs PR'ы, с ревью которых снят пользователь
+	ReassignedPRs []string `json:"reassigned_prs"`
+	// AuthoredPRs открытые PR'ы пользователя, к которым применена политика
+	AuthoredPRs []string `json:"authored_prs"`
+}
+
+// validateUserIds проверяет список id пользователей: не больше maxUserIds уникальных uuid
+func validateUserIds(userIds []string) *ErrorResponse {
+	if len(userIds) > maxUserIds {
+		return ErrTooManyUserIds
+	}
+	seen := make(map[string]bool, len(userIds))
+	for _, id := range userIds {
+		if _, err := uuid.Parse(id); err != nil {
+			return ErrUserIdShouldBeUuid
+		}
+		if seen[id] {
+			return ErrDuplicateUserIds
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// validMaxOpenReviews проверяет ограничение открытых ревью, 0 снимает ограничение
+func validMaxOpenReviews(limit int) bool {
+	return limit >= 0 && limit <= maxOpenReviewsCap
+}
+
+// validTags проверяет теги пользователя или метки PR'а
+func validTags(tags []string) bool {
+	if len(tags) > maxTags {
+		return false
+	}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || len(tag) > maxTagLength {
+			return false
+		}
+	}
+	return true
+}
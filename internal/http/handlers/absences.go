@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/usecases"
+
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+// AddAbsence godoc
+// @Summary Добавить пользователю период отсутствия. Внутри периода пользователь считается неактивным, а при его начале открытые ревью пользователя переназначаются
+// @Param request body dto.AddAbsenceRequest true "Пользователь, начало и конец периода (RFC 3339), причина"
+// @Produce json
+// @Success 201 {object} dto.AddAbsenceResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /users/addAbsence [post]
+// @Tags Users
+func (h *Handlers) AddAbsence() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.AddAbsenceRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		absence, err := h.uc.AddAbsence(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.ErrUserNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, dto.AddAbsenceResponse{
+			Absence: absence,
+		})
+	}
+}
+
+// GetAbsences godoc
+// @Summary Получить текущие и будущие периоды отсутствия пользователя
+// @Param user_id query string true "Id пользователя"
+// @Produce json
+// @Success 200 {object} dto.GetAbsencesResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /users/getAbsences [get]
+// @Tags Users
+func (h *Handlers) GetAbsences() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		userId := r.URL.Query().Get("user_id")
+		if userId == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrUserIdRequired)
+			return
+		}
+		if _, err := uuid.Parse(userId); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrUserIdShouldBeUuid)
+			return
+		}
+
+		absences, err := h.uc.GetAbsences(r.Context(), userId)
+		if err != nil {
+			if errors.Is(err, usecases.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.ErrUserNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.GetAbsencesResponse{
+			UserId:   userId,
+			Absences: absences,
+		})
+	}
+}
+
+// DeleteAbsence godoc
+// @Summary Удалить период отсутствия. Снятые с пользователя ревью обратно не возвращаются
+// @Param request body dto.DeleteAbsenceRequest true "Id периода отсутствия"
+// @Produce json
+// @Success 204 "Период отсутствия удалён"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Период отсутствия не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /users/deleteAbsence [post]
+// @Tags Users
+func (h *Handlers) DeleteAbsence() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.DeleteAbsenceRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		err := h.uc.DeleteAbsence(r.Context(), req.Id)
+		if err != nil {
+			if errors.Is(err, usecases.ErrAbsenceNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.ErrAbsenceNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -10,14 +10,50 @@ import (
 type Usecases interface {
 	GetTeam(ctx context.Context, name string) ([]*models.Member, error)
 	CreateTeam(ctx context.Context, reqDTO *dto.AddTeamRequest) error
+	AddTeamMembers(ctx context.Context, reqDTO *dto.TeamMembersRequest) ([]*models.Member, error)
+	RemoveTeamMembers(ctx context.Context, reqDTO *dto.RemoveTeamMembersRequest) ([]*models.Member, error)
+	SetTeamMembers(ctx context.Context, reqDTO *dto.TeamMembersRequest) ([]*models.Member, error)
+	RenameTeam(ctx context.Context, reqDTO *dto.RenameTeamRequest) (*models.Team, error)
+	DeleteTeam(ctx context.Context, reqDTO *dto.DeleteTeamRequest) ([]string, error)
+	MergeTeams(ctx context.Context, reqDTO *dto.MergeTeamsRequest) ([]*models.Member, error)
+	GetTeamSettings(ctx context.Context, name string) (*models.Team, error)
+	UpdateTeamSettings(ctx context.Context, reqDTO *dto.TeamSettingsRequest) (*models.Team, error)
+	GetReviewerRules(ctx context.Context, teamName string) ([]*models.ReviewerRule, error)
+	AddReviewerRule(ctx context.Context, reqDTO *dto.AddReviewerRuleRequest) (*models.ReviewerRule, error)
+	DeleteReviewerRule(ctx context.Context, id string) error
 
-	UserSetIsActive(ctx context.Context, reqDTO *dto.SetIsActiveRequest) (*models.User, error)
+	UserSetIsActive(ctx context.Context, reqDTO *dto.SetIsActiveRequest) (*models.User, []*models.ReviewReassignment, error)
+	BulkSetIsActive(ctx context.Context, reqDTO *dto.BulkSetIsActiveRequest) ([]*models.ActivationChange, error)
+	UserSetTags(ctx context.Context, reqDTO *dto.SetTagsRequest) (*models.User, error)
+	UserSetMaxOpenReviews(ctx context.Context, reqDTO *dto.SetMaxOpenReviewsRequest) (*models.User, error)
+	UserSetPrimaryTeam(ctx context.Context, reqDTO *dto.SetPrimaryTeamRequest) (*models.User, error)
+	OffboardUser(ctx context.Context, reqDTO *dto.OffboardUserRequest) (*models.Offboarding, error)
 	GetPRs(ctx context.Context, userId string) ([]*models.PullRequest, error)
+	AddAbsence(ctx context.Context, reqDTO *dto.AddAbsenceRequest) (*models.Absence, error)
+	GetAbsences(ctx context.Context, userId string) ([]*models.Absence, error)
+	DeleteAbsence(ctx context.Context, id string) error
 
 	CreatePR(ctx context.Context, reqDTO *dto.CreatePRRequest) (*models.PullRequest, error)
 	MergePR(ctx context.Context, prId string) (*models.PullRequest, error)
 	ReassignPR(ctx context.Context, reqDTO *dto.ReassignPRRequest) (*models.PullRequest, string, error)
+	ReviewPR(ctx context.Context, reqDTO *dto.ReviewPRRequest) (*models.PullRequest, *models.Review, error)
+	UpdatePR(ctx context.Context, reqDTO *dto.UpdatePRRequest) (*models.PullRequest, error)
+	ReadyPR(ctx context.Context, prId string) (*models.PullRequest, error)
+	ClosePR(ctx context.Context, prId string) (*models.PullRequest, error)
+	ReopenPR(ctx context.Context, prId string) (*models.PullRequest, error)
 	GetStatistics(ctx context.Context, reqDTO *dto.StatisticsRequest) (map[string]int, uint64, error)
+	GetAssignmentDecisions(ctx context.Context, prId string) ([]*models.AssignmentDecision, error)
+	GetPRHistory(ctx context.Context, prId string) ([]*models.PREvent, error)
+	GetPR(ctx context.Context, prId string) (*models.PRDetails, error)
+	ListPRs(ctx context.Context, reqDTO *dto.ListPRsRequest) ([]*models.PullRequest, *models.PRCursor, error)
+	AddReviewers(ctx context.Context, reqDTO *dto.AddReviewersRequest) (*models.PullRequest, []string, error)
+	RemoveReviewer(ctx context.Context, reqDTO *dto.RemoveReviewerRequest) (*models.PullRequest, string, error)
+	GetOverduePRs(ctx context.Context, teamName string) ([]*models.OverduePR, error)
+
+	GetOwnershipRules(ctx context.Context) ([]*models.OwnershipRule, error)
+	AddOwnershipRule(ctx context.Context, reqDTO *dto.AddOwnershipRuleRequest) (*models.OwnershipRule, error)
+	DeleteOwnershipRule(ctx context.Context, id string) error
+	ImportCodeowners(ctx context.Context, reqDTO *dto.ImportCodeownersRequest) ([]*models.OwnershipRule, error)
 }
 
 type Handlers struct {
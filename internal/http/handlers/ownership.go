@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/usecases"
+
+	"github.com/go-chi/render"
+)
+
+// GetOwnershipRules godoc
+// @Summary Получить правила владения путями в порядке применения (при совпадении нескольких правил применяется последнее)
+// @Produce json
+// @Success 200 {object} dto.OwnershipRulesResponse
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /ownership/list [get]
+// @Tags Ownership
+func (h *Handlers) GetOwnershipRules() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+
+		rules, err := h.uc.GetOwnershipRules(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.OwnershipRulesResponse{
+			Rules: rules,
+		})
+	}
+}
+
+// AddOwnershipRule godoc
+// @Summary Добавить правило владения путями в конец списка правил
+// @Param request body dto.AddOwnershipRuleRequest true "Шаблон пути в формате CODEOWNERS, команды и пользователи-владельцы"
+// @Produce json
+// @Success 201 {object} dto.AddOwnershipRuleResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Команда или пользователь не найдены"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /ownership/add [post]
+// @Tags Ownership
+func (h *Handlers) AddOwnershipRule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.AddOwnershipRuleRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		rule, err := h.uc.AddOwnershipRule(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrOwnerNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, dto.AddOwnershipRuleResponse{
+			Rule: rule,
+		})
+	}
+}
+
+// DeleteOwnershipRule godoc
+// @Summary Удалить правило владения путями
+// @Param request body dto.DeleteOwnershipRuleRequest true "Id правила"
+// @Produce json
+// @Success 204 "Правило удалено"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Правило не найдено"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /ownership/delete [post]
+// @Tags Ownership
+func (h *Handlers) DeleteOwnershipRule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.DeleteOwnershipRuleRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		err := h.uc.DeleteOwnershipRule(r.Context(), req.Id)
+		if err != nil {
+			if errors.Is(err, usecases.ErrRuleNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ImportCodeowners godoc
+// @Summary Заменить все правила владения путями правилами из файла CODEOWNERS. Владелец @name ищется среди команд, затем среди username пользователей
+// @Param request body dto.ImportCodeownersRequest true "Содержимое файла CODEOWNERS"
+// @Produce json
+// @Success 200 {object} dto.OwnershipRulesResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос или неверный формат файла"
+// @Failure 404 {object} dto.ErrorResponse "Владелец не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /ownership/import [post]
+// @Tags Ownership
+func (h *Handlers) ImportCodeowners() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.ImportCodeownersRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		rules, err := h.uc.ImportCodeowners(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrInvalidCodeowners) {
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, dto.Error(dto.ErrCodeBadRequest, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrOwnerNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.OwnershipRulesResponse{
+			Rules: rules,
+		})
+	}
+}
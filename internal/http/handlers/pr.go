@@ -1,21 +1,24 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
 	"pr-review/internal/usecases"
 
 	"github.com/go-chi/render"
+	"github.com/google/uuid"
 )
 
 // CreatePR godoc
-// @Summary Создать PR и автоматически назначить до 2 ревьюверов из команды автора
+// @Summary Создать PR и автоматически назначить ревьюверов: сначала владельцев изменённых файлов (files), затем из команды автора (до reviewers_per_pr команды, по умолчанию 2)
 // @Param request body dto.CreatePRRequest true "PR"
 // @Produce json
 // @Success 201 {object} dto.CreatePRResponse
 // @Failure 404 {object} dto.ErrorResponse "Автор не найден"
-// @Failure 409 {object} dto.ErrorResponse "PR уже существует"
+// @Failure 409 {object} dto.ErrorResponse "PR уже существует, у автора нет основной команды или он не состоит в указанной"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
 // @Router /pullRequest/create [post]
 // @Tags PullRequests
@@ -53,6 +56,11 @@ func (h *Handlers) CreatePR() http.HandlerFunc {
 				render.JSON(w, r, dto.Error(dto.ErrCodePRExists, err.Error()))
 				return
 			}
+			if errors.Is(err, usecases.ErrAuthorWithoutTeam) || errors.Is(err, usecases.ErrAuthorNotInTeam) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeAuthorWithoutTeam, err.Error()))
+				return
+			}
 			w.WriteHeader(http.StatusInternalServerError)
 			render.JSON(w, r, dto.ErrInternal)
 			return
@@ -66,11 +74,12 @@ func (h *Handlers) CreatePR() http.HandlerFunc {
 }
 
 // MergePR godoc
-// @Summary Пометить PR как MERGED (идемпотентная операция)
+// @Summary Пометить PR как MERGED (идемпотентная операция). Если в команде автора задано required_approvals, нужны одобрения назначенных ревьюверов
 // @Param request body dto.MergePRRequest true "PR id"
 // @Produce json
 // @Success 200 {object} dto.MergePRResponse "PR в состоянии MERGED"
 // @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "Недостаточно одобрений или PR в статусе DRAFT/CLOSED"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
 // @Router /pullRequest/merge [post]
 // @Tags PullRequests
@@ -103,6 +112,16 @@ func (h *Handlers) MergePR() http.HandlerFunc {
 				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
 				return
 			}
+			if errors.Is(err, usecases.ErrNotEnoughApprovals) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotEnoughApprovals, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrIllegalTransition) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeIllegalTransition, err.Error()))
+				return
+			}
 			w.WriteHeader(http.StatusInternalServerError)
 			render.JSON(w, r, dto.ErrInternal)
 			return
@@ -115,6 +134,169 @@ func (h *Handlers) MergePR() http.HandlerFunc {
 	}
 }
 
+// ReadyPR godoc
+// @Summary Перевести черновик PR'а (DRAFT) в OPEN и назначить ревьюверов
+// @Param request body dto.ChangePRStatusRequest true "PR id"
+// @Produce json
+// @Success 200 {object} dto.ChangePRStatusResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "PR не в статусе DRAFT"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/ready [post]
+// @Tags PullRequests
+func (h *Handlers) ReadyPR() http.HandlerFunc {
+	return h.changePRStatus(h.uc.ReadyPR)
+}
+
+// ClosePR godoc
+// @Summary Закрыть PR без merge (из DRAFT или OPEN), ревьюверы с PR'а снимаются
+// @Param request body dto.ChangePRStatusRequest true "PR id"
+// @Produce json
+// @Success 200 {object} dto.ChangePRStatusResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "PR уже MERGED или CLOSED"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/close [post]
+// @Tags PullRequests
+func (h *Handlers) ClosePR() http.HandlerFunc {
+	return h.changePRStatus(h.uc.ClosePR)
+}
+
+// ReopenPR godoc
+// @Summary Переоткрыть закрытый PR (CLOSED -> OPEN), ревьюверы назначаются заново
+// @Param request body dto.ChangePRStatusRequest true "PR id"
+// @Produce json
+// @Success 200 {object} dto.ChangePRStatusResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "PR не в статусе CLOSED"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/reopen [post]
+// @Tags PullRequests
+func (h *Handlers) ReopenPR() http.HandlerFunc {
+	return h.changePRStatus(h.uc.ReopenPR)
+}
+
+// changePRStatus общий обработчик запросов на перевод PR'а в другой статус через change
+func (h *Handlers) changePRStatus(change func(ctx context.Context, prId string) (*models.PullRequest, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.ChangePRStatusRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		pr, err := change(r.Context(), req.PullRequestID)
+		if err != nil {
+			if errors.Is(err, usecases.ErrPRNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrIllegalTransition) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeIllegalTransition, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.ChangePRStatusResponse{
+			PR: pr,
+		})
+	}
+}
+
+// ReviewPR godoc
+// @Summary Сохранить решение назначенного ревьювера по открытому PR'у (APPROVED, CHANGES_REQUESTED, COMMENTED). Учитывается последнее решение ревьювера
+// @Param request body dto.ReviewPRRequest true "PR id, id ревьювера, решение и комментарий"
+// @Produce json
+// @Success 200 {object} dto.ReviewPRResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "PR не открыт (MERGED, CLOSED, DRAFT) или пользователь не назначен ревьювером"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/review [post]
+// @Tags PullRequests
+func (h *Handlers) ReviewPR() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.ReviewPRRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		pr, review, err := h.uc.ReviewPR(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrPRNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrReviewMergedPR) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeCannotReassignMergedPR, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrReviewClosedPR) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodePRClosed, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrReviewDraftPR) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodePRDraft, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrUserNotReviewerOfPR) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeUserNotReviewerOfPR, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.ReviewPRResponse{
+			PR:     pr,
+			Review: review,
+		})
+	}
+}
+
 // ReassignPR godoc
 // @Summary Переназначить конкретного ревьювера на другого из его команды
 // @Param request body dto.ReassignPRRequest true "PR id & old reviewer id"
@@ -179,6 +361,208 @@ func (h *Handlers) ReassignPR() http.HandlerFunc {
 	}
 }
 
+// AddReviewers godoc
+// @Summary Назначить на PR конкретных ревьюверов, в том числе из других команд, сверх автоматического подбора (источник REQUESTED). Уже назначенные пропускаются
+// @Param request body dto.AddReviewersRequest true "PR id & reviewer ids"
+// @Produce json
+// @Success 200 {object} dto.AddReviewersResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "PR или пользователь не найден"
+// @Failure 409 {object} dto.ErrorResponse "Нельзя менять после MERGED или назначать на закрытый PR или черновик"
+// @Failure 409 {object} dto.ErrorResponse "Ревьювер - автор PR'а, неактивен или запрещён правилом команды; автор PR'а не состоит в команде"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/addReviewers [post]
+// @Tags PullRequests
+func (h *Handlers) AddReviewers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.AddReviewersRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		pr, added, err := h.uc.AddReviewers(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrPRNotFound) || errors.Is(err, usecases.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrChangeReviewersMergedPR) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeCannotReassignMergedPR, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrRequestReviewersClosedPR) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodePRClosed, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrRequestReviewersDraftPR) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodePRDraft, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrReviewerIsAuthor) || errors.Is(err, usecases.ErrReviewerInactive) || errors.Is(err, usecases.ErrReviewerExcludedByRule) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeReviewerUnavailable, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrAuthorWithoutTeam) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeAuthorWithoutTeam, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.AddReviewersResponse{
+			PR:    pr,
+			Added: added,
+		})
+	}
+}
+
+// RemoveReviewer godoc
+// @Summary Снять ревьювера с PR'а. С backfill недостающие до квоты команды ревьюверы сразу подбираются заново, без него место остаётся пустым (need_more_reviewers_reason REVIEWER_REMOVED, фоновое дозаполнение такой PR не трогает)
+// @Param request body dto.RemoveReviewerRequest true "PR id, reviewer id, backfill"
+// @Produce json
+// @Success 200 {object} dto.RemoveReviewerResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 409 {object} dto.ErrorResponse "Нельзя менять после MERGED"
+// @Failure 409 {object} dto.ErrorResponse "Пользователь не был назначен ревьювером"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/removeReviewer [post]
+// @Tags PullRequests
+func (h *Handlers) RemoveReviewer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.RemoveReviewerRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		pr, replacedBy, err := h.uc.RemoveReviewer(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrPRNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrChangeReviewersMergedPR) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeCannotReassignMergedPR, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrUserNotReviewerOfPR) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeUserNotReviewerOfPR, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.RemoveReviewerResponse{
+			PR:         pr,
+			ReplacedBy: replacedBy,
+		})
+	}
+}
+
+// UpdatePR godoc
+// @Summary Изменить название и/или автора PR'а. При смене автора открытого PR'а новый автор снимается с ревью, а ревьюверы добираются до квоты его команды; если команда другая, reviewers_policy решает, оставить ли текущих ревьюверов (KEEP, по умолчанию) или подобрать заново (REPLACE)
+// @Param request body dto.UpdatePRRequest true "PR id, название, автор"
+// @Produce json
+// @Success 200 {object} dto.UpdatePRResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "PR или автор не найден"
+// @Failure 409 {object} dto.ErrorResponse "Нельзя менять автора после MERGED или новый автор не состоит в команде"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/update [post]
+// @Tags PullRequests
+func (h *Handlers) UpdatePR() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.UpdatePRRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		pr, err := h.uc.UpdatePR(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrPRNotFound) || errors.Is(err, usecases.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrChangeAuthorMergedPR) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeCannotReassignMergedPR, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrAuthorWithoutTeam) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeAuthorWithoutTeam, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.UpdatePRResponse{
+			PR: pr,
+		})
+	}
+}
+
 // Statistics godoc
 // @Summary Получить статистику по количеству PR'ов у авторов
 // @Param page query number false "Страница"
@@ -214,3 +598,228 @@ func (h *Handlers) Statistics() http.HandlerFunc {
 		})
 	}
 }
+
+// GetPR godoc
+// @Summary Получить PR: статус, ревьюверы с username, командой и активностью, автор с командой, need_more_reviewers, время создания и merge
+// @Param pull_request_id query string true "Идентификатор PR'а"
+// @Produce json
+// @Success 200 {object} dto.GetPRResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/get [get]
+// @Tags PullRequests
+func (h *Handlers) GetPR() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		prId := r.URL.Query().Get("pull_request_id")
+		if prId == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrPRIdRequired)
+			return
+		}
+		if _, err := uuid.Parse(prId); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrPRIdShouldBeUuid)
+			return
+		}
+
+		details, err := h.uc.GetPR(r.Context(), prId)
+		if err != nil {
+			if errors.Is(err, usecases.ErrPRNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.GetPRResponse{
+			PR:                details.PR,
+			NeedMoreReviewers: details.NeedMoreReviewers,
+			Author:            details.Author,
+			Reviewers:         details.Reviewers,
+		})
+	}
+}
+
+// ListPRs godoc
+// @Summary Список PR'ов с фильтрами и курсорной пагинацией. Для следующей страницы передаётся next_cursor из ответа с теми же sort и order
+// @Param status query string false "Статусы через запятую: DRAFT, OPEN, MERGED, CLOSED"
+// @Param author_id query string false "Автор"
+// @Param team_name query string false "Команда автора"
+// @Param reviewer_id query string false "Назначенный ревьювер"
+// @Param need_more_reviewers query bool false "Не хватает ревьюверов до квоты команды"
+// @Param created_from query string false "Создан не раньше (RFC 3339)"
+// @Param created_to query string false "Создан раньше (RFC 3339)"
+// @Param merged_from query string false "Смержен не раньше (RFC 3339)"
+// @Param merged_to query string false "Смержен раньше (RFC 3339)"
+// @Param q query string false "Подстрока названия без учёта регистра"
+// @Param sort query string false "Поле сортировки: created_at (по умолчанию), merged_at (только смерженные PR'ы), title"
+// @Param order query string false "Порядок: desc (по умолчанию) или asc"
+// @Param limit query int false "Размер страницы, от 1 до 100, по умолчанию 20"
+// @Param cursor query string false "Позиция следующей страницы из next_cursor"
+// @Produce json
+// @Success 200 {object} dto.ListPRsResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/list [get]
+// @Tags PullRequests
+func (h *Handlers) ListPRs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+
+		reqDTO, errResp := dto.MapQueryToListPRsRequest(r.URL.Query())
+		if errResp != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, errResp)
+			return
+		}
+
+		prs, next, err := h.uc.ListPRs(r.Context(), reqDTO)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.ListPRsResponse{
+			PullRequests: prs,
+			NextCursor:   dto.EncodePRCursor(next),
+		})
+	}
+}
+
+// AssignmentExplain godoc
+// @Summary Объяснить подбор ревьюверов PR'а: для каждого подбора - seed, стратегия, кандидаты в порядке стратегии и исключённые кандидаты с причиной
+// @Param pull_request_id query string true "Идентификатор PR'а"
+// @Produce json
+// @Success 200 {object} dto.AssignmentExplainResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/assignmentExplain [get]
+// @Tags PullRequests
+func (h *Handlers) AssignmentExplain() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		prId := r.URL.Query().Get("pull_request_id")
+		if prId == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrPRIdRequired)
+			return
+		}
+		if _, err := uuid.Parse(prId); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrPRIdShouldBeUuid)
+			return
+		}
+
+		decisions, err := h.uc.GetAssignmentDecisions(r.Context(), prId)
+		if err != nil {
+			if errors.Is(err, usecases.ErrPRNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.AssignmentExplainResponse{
+			PullRequestID: prId,
+			Decisions:     decisions,
+		})
+	}
+}
+
+// PRHistory godoc
+// @Summary История PR'а: создание, назначения и снятия ревьюверов, смены статуса, автора и need_more_reviewers с операцией, выполнившим её (заголовок X-Actor) и временем
+// @Param pull_request_id query string true "Идентификатор PR'а"
+// @Produce json
+// @Success 200 {object} dto.PRHistoryResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "PR не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/history [get]
+// @Tags PullRequests
+func (h *Handlers) PRHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		prId := r.URL.Query().Get("pull_request_id")
+		if prId == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrPRIdRequired)
+			return
+		}
+		if _, err := uuid.Parse(prId); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrPRIdShouldBeUuid)
+			return
+		}
+
+		events, err := h.uc.GetPRHistory(r.Context(), prId)
+		if err != nil {
+			if errors.Is(err, usecases.ErrPRNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.PRHistoryResponse{
+			PullRequestID: prId,
+			Events:        events,
+		})
+	}
+}
+
+// OverduePRs godoc
+// @Summary Открытые PR'ы авторов команды, назначенные ревьюверы которых не приняли решение за SLA команды (review_sla_hours рабочих часов, выходные не учитываются)
+// @Param team_name query string true "Название команды"
+// @Produce json
+// @Success 200 {object} dto.OverduePRsResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /pullRequest/overdue [get]
+// @Tags PullRequests
+func (h *Handlers) OverduePRs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		teamName := r.URL.Query().Get("team_name")
+		if teamName == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrTeamNameRequired)
+			return
+		}
+
+		prs, err := h.uc.GetOverduePRs(r.Context(), teamName)
+		if err != nil {
+			if errors.Is(err, usecases.ErrTeamNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.OverduePRsResponse{
+			TeamName:     teamName,
+			PullRequests: prs,
+		})
+	}
+}
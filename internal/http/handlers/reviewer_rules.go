@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/usecases"
+
+	"github.com/go-chi/render"
+)
+
+// GetReviewerRules godoc
+// @Summary Получить правила команды для пар автор - ревьювер
+// @Param team_name query string true "Название команды"
+// @Produce json
+// @Success 200 {object} dto.ReviewerRulesResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /team/rules [get]
+// @Tags Teams
+func (h *Handlers) GetReviewerRules() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		teamName := r.URL.Query().Get("team_name")
+		if teamName == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrTeamNameRequired)
+			return
+		}
+
+		rules, err := h.uc.GetReviewerRules(r.Context(), teamName)
+		if err != nil {
+			if errors.Is(err, usecases.ErrTeamNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.ReviewerRulesResponse{
+			TeamName: teamName,
+			Rules:    rules,
+		})
+	}
+}
+
+// AddReviewerRule godoc
+// @Summary Добавить команде правило: EXCLUDE - никогда не назначать ревьювера на PR'ы автора, INCLUDE - всегда назначать. Применяется при создании PR'а и переназначении ревьювера
+// @Param request body dto.AddReviewerRuleRequest true "Команда автора, вид правила, автор и ревьювер"
+// @Produce json
+// @Success 201 {object} dto.AddReviewerRuleResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос, автор не в команде или правило для пары уже есть"
+// @Failure 404 {object} dto.ErrorResponse "Команда или пользователь не найдены"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /team/addRule [post]
+// @Tags Teams
+func (h *Handlers) AddReviewerRule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.AddReviewerRuleRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		rule, err := h.uc.AddReviewerRule(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrTeamNotFound) || errors.Is(err, usecases.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrAuthorNotInTeam) {
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, dto.Error(dto.ErrCodeBadRequest, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrReviewerRuleExists) {
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, dto.Error(dto.ErrCodeRuleExists, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, dto.AddReviewerRuleResponse{
+			Rule: rule,
+		})
+	}
+}
+
+// DeleteReviewerRule godoc
+// @Summary Удалить правило команды для пары автор - ревьювер
+// @Param request body dto.DeleteReviewerRuleRequest true "Id правила"
+// @Produce json
+// @Success 204 "Правило удалено"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Правило не найдено"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /team/deleteRule [post]
+// @Tags Teams
+func (h *Handlers) DeleteReviewerRule() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.DeleteReviewerRuleRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		err := h.uc.DeleteReviewerRule(r.Context(), req.Id)
+		if err != nil {
+			if errors.Is(err, usecases.ErrReviewerRuleNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.ErrReviewerRuleNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
 	"pr-review/internal/repository/postgres"
 	"pr-review/internal/usecases"
 
@@ -16,6 +17,7 @@ import (
 // @Produce json
 // @Success 201 {object} dto.AddTeamResponse
 // @Failure 400 {object} dto.ErrorResponse "Неверный запрос или команда с таким team_name уже существует"
+// @Failure 409 {object} dto.ErrorResponse "Один из участников уволен"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
 // @Router /team/add [post]
 // @Tags Teams
@@ -52,6 +54,11 @@ func (h *Handlers) AddTeam() http.HandlerFunc {
 				render.JSON(w, r, dto.Error(dto.ErrCodeUserExists, err.Error()))
 				return
 			}
+			if errors.Is(err, usecases.ErrUserOffboarded) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeUserOffboarded, err.Error()))
+				return
+			}
 			w.WriteHeader(http.StatusInternalServerError)
 			render.JSON(w, r, dto.ErrInternal)
 			return
@@ -60,8 +67,9 @@ func (h *Handlers) AddTeam() http.HandlerFunc {
 		w.WriteHeader(http.StatusCreated)
 		render.JSON(w, r, dto.AddTeamResponse{
 			Team: &dto.Team{
-				Name:    req.Name,
-				Members: req.Members,
+				Name:             req.Name,
+				Members:          req.Members,
+				ReviewerStrategy: req.ReviewerStrategy,
 			},
 		})
 	}
@@ -105,3 +113,413 @@ func (h *Handlers) GetTeam() http.HandlerFunc {
 		})
 	}
 }
+
+// AddTeamMembers godoc
+// @Summary Добавить участников в существующую команду (создаёт/обновляет пользователей, участники другой команды переходят в эту). С неактивных участников снимаются открытые ревью
+// @Param request body dto.TeamMembersRequest true "Команда и участники"
+// @Produce json
+// @Success 200 {object} dto.GetTeamResponse "Новый состав команды"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос или пользователь с таким username уже существует"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 409 {object} dto.ErrorResponse "Один из участников уволен"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /team/addMembers [post]
+// @Tags Teams
+func (h *Handlers) AddTeamMembers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.TeamMembersRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+		if len(req.Members) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrMembersRequired)
+			return
+		}
+
+		h.changeTeamMembers(w, r, req.Name, func() ([]*models.Member, error) {
+			return h.uc.AddTeamMembers(r.Context(), &req)
+		})
+	}
+}
+
+// RemoveTeamMembers godoc
+// @Summary Исключить участников из команды, их открытые ревью переназначаются. Исключённые пользователи остаются без команды
+// @Param request body dto.RemoveTeamMembersRequest true "Команда и id участников"
+// @Produce json
+// @Success 200 {object} dto.GetTeamResponse "Новый состав команды"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена или пользователь не состоит в ней"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /team/removeMembers [post]
+// @Tags Teams
+func (h *Handlers) RemoveTeamMembers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.RemoveTeamMembersRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		h.changeTeamMembers(w, r, req.Name, func() ([]*models.Member, error) {
+			return h.uc.RemoveTeamMembers(r.Context(), &req)
+		})
+	}
+}
+
+// SetTeamMembers godoc
+// @Summary Заменить состав команды (идемпотентно): участники не из списка исключаются с переназначением их открытых ревью, остальные добавляются/обновляются
+// @Param request body dto.TeamMembersRequest true "Команда и её новый состав"
+// @Produce json
+// @Success 200 {object} dto.GetTeamResponse "Новый состав команды"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос или пользователь с таким username уже существует"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 409 {object} dto.ErrorResponse "Один из участников уволен"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /team/members [put]
+// @Tags Teams
+func (h *Handlers) SetTeamMembers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.TeamMembersRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		h.changeTeamMembers(w, r, req.Name, func() ([]*models.Member, error) {
+			return h.uc.SetTeamMembers(r.Context(), &req)
+		})
+	}
+}
+
+// changeTeamMembers выполняет change и отдаёт новый состав команды или ошибку
+func (h *Handlers) changeTeamMembers(w http.ResponseWriter, r *http.Request, teamName string, change func() ([]*models.Member, error)) {
+	members, err := change()
+	if err != nil {
+		if errors.Is(err, usecases.ErrTeamNotFound) || errors.Is(err, usecases.ErrNotTeamMember) {
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+			return
+		}
+		if errors.Is(err, postgres.ErrUserExists) {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.Error(dto.ErrCodeUserExists, err.Error()))
+			return
+		}
+		if errors.Is(err, usecases.ErrUserOffboarded) {
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, dto.Error(dto.ErrCodeUserOffboarded, err.Error()))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, dto.ErrInternal)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	render.JSON(w, r, dto.GetTeamResponse{
+		Name:    teamName,
+		Members: members,
+	})
+}
+
+// RenameTeam godoc
+// @Summary Переименовать команду
+// @Param request body dto.RenameTeamRequest true "Текущее и новое название команды"
+// @Produce json
+// @Success 200 {object} dto.RenameTeamResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос или команда с таким названием уже существует"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /team/rename [post]
+// @Tags Teams
+func (h *Handlers) RenameTeam() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.RenameTeamRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		team, err := h.uc.RenameTeam(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrTeamNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrTeamAlredyExists) {
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, dto.Error(dto.ErrCodeTeamExists, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.RenameTeamResponse{
+			Name:         team.Name,
+			PreviousName: req.Name,
+		})
+	}
+}
+
+// DeleteTeam godoc
+// @Summary Удалить команду вместе с её правилами ревьюверов и владением путями. С move_members_to участники переводятся в указанную команду и сохраняют свои ревью, без него - деактивируются, остаются без команды, а их открытые ревью переназначаются. Без move_members_to команду с открытыми PR'ами или черновиками удалить нельзя
+// @Param request body dto.DeleteTeamRequest true "Команда и команда для её участников"
+// @Produce json
+// @Success 200 {object} dto.DeleteTeamResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Команда или команда для участников не найдена"
+// @Failure 409 {object} dto.ErrorResponse "У команды есть открытые PR'ы, а move_members_to не указан"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /team/delete [post]
+// @Tags Teams
+func (h *Handlers) DeleteTeam() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.DeleteTeamRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		members, err := h.uc.DeleteTeam(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrTeamNotFound) || errors.Is(err, usecases.ErrTargetTeamNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrTeamHasOpenPRs) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeTeamHasOpenPRs, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.DeleteTeamResponse{
+			Name:          req.Name,
+			MoveMembersTo: req.MoveMembersTo,
+			Members:       members,
+		})
+	}
+}
+
+// MergeTeams godoc
+// @Summary Слить команду в другую: участники, правила ревьюверов, владение путями и резервные команды переходят в into_team_name, исходная команда удаляется. Настройки целевой команды не меняются
+// @Param request body dto.MergeTeamsRequest true "Исходная и целевая команды"
+// @Produce json
+// @Success 200 {object} dto.GetTeamResponse "Новый состав целевой команды"
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Исходная или целевая команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /team/merge [post]
+// @Tags Teams
+func (h *Handlers) MergeTeams() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.MergeTeamsRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		members, err := h.uc.MergeTeams(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrTeamNotFound) || errors.Is(err, usecases.ErrTargetTeamNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.GetTeamResponse{
+			Name:    req.IntoName,
+			Members: members,
+		})
+	}
+}
+
+// GetTeamSettings godoc
+// @Summary Получить настройки назначения ревьюверов команды
+// @Param team_name query string true "Название команды"
+// @Produce json
+// @Success 200 {object} dto.TeamSettingsResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /team/settings [get]
+// @Tags Teams
+func (h *Handlers) GetTeamSettings() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		teamName := r.URL.Query().Get("team_name")
+		if teamName == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrTeamNameRequired)
+			return
+		}
+
+		team, err := h.uc.GetTeamSettings(r.Context(), teamName)
+		if err != nil {
+			if errors.Is(err, usecases.ErrTeamNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.TeamSettingsResponse{
+			Name:     team.Name,
+			Settings: &team.TeamSettings,
+		})
+	}
+}
+
+// UpdateTeamSettings godoc
+// @Summary Изменить настройки назначения ревьюверов команды (передаются только изменяемые поля)
+// @Param request body dto.TeamSettingsRequest true "Настройки команды"
+// @Produce json
+// @Success 200 {object} dto.TeamSettingsResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Команда или резервная команда не найдена"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /team/settings [post]
+// @Tags Teams
+func (h *Handlers) UpdateTeamSettings() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.TeamSettingsRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		team, err := h.uc.UpdateTeamSettings(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrTeamNotFound) || errors.Is(err, usecases.ErrFallbackTeamNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrFallbackToSelf) {
+				w.WriteHeader(http.StatusBadRequest)
+				render.JSON(w, r, dto.Error(dto.ErrCodeBadRequest, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.TeamSettingsResponse{
+			Name:     team.Name,
+			Settings: &team.TeamSettings,
+		})
+	}
+}
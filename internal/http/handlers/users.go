@@ -11,12 +11,13 @@ import (
 )
 
 // UserSetIsActive godoc
-// @Summary Установить флаг активности пользователя
+// @Summary Установить флаг активности пользователя. Открытые ревью деактивированного пользователя переназначаются, в ответе - на кого
 // @Param request body dto.SetIsActiveRequest true "Установка пользователя активным/неактивным"
 // @Produce json
 // @Success 200 {object} dto.SetIsActiveResponse
 // @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
 // @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 409 {object} dto.ErrorResponse "Уволенного пользователя нельзя активировать"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
 // @Router /users/setIsActive [post]
 // @Tags Users
@@ -41,13 +42,18 @@ func (h *Handlers) UserSetIsActive() http.HandlerFunc {
 			return
 		}
 
-		user, err := h.uc.UserSetIsActive(r.Context(), &req)
+		user, reassigned, err := h.uc.UserSetIsActive(r.Context(), &req)
 		if err != nil {
 			if errors.Is(err, usecases.ErrUserNotFound) {
 				w.WriteHeader(http.StatusNotFound)
 				render.JSON(w, r, dto.ErrUserNotFound)
 				return
 			}
+			if errors.Is(err, usecases.ErrUserOffboarded) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeUserOffboarded, err.Error()))
+				return
+			}
 			w.WriteHeader(http.StatusInternalServerError)
 			render.JSON(w, r, dto.ErrInternal)
 			return
@@ -55,11 +61,295 @@ func (h *Handlers) UserSetIsActive() http.HandlerFunc {
 
 		w.WriteHeader(http.StatusOK)
 		render.JSON(w, r, dto.SetIsActiveResponse{
+			User:       user,
+			Reassigned: reassigned,
+		})
+	}
+}
+
+// BulkSetIsActive godoc
+// @Summary Установить флаг активности списку пользователей или всем участникам команды в одной транзакции. Открытые ревью деактивированных переназначаются, в ответе - результат по каждому пользователю
+// @Param request body dto.BulkSetIsActiveRequest true "Пользователи (user_ids или team_name) и флаг активности"
+// @Produce json
+// @Success 200 {object} dto.BulkSetIsActiveResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь или команда не найдены"
+// @Failure 409 {object} dto.ErrorResponse "Уволенного пользователя нельзя активировать"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /users/bulkSetIsActive [post]
+// @Tags Users
+func (h *Handlers) BulkSetIsActive() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.BulkSetIsActiveRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		changes, err := h.uc.BulkSetIsActive(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrUserNotFound) || errors.Is(err, usecases.ErrTeamNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrUserOffboarded) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeUserOffboarded, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.BulkSetIsActiveResponse{Users: changes})
+	}
+}
+
+// UserSetTags godoc
+// @Summary Заменить теги экспертизы пользователя (go, sql, frontend...), по ним ревьюверы подбираются под метки PR'а
+// @Param request body dto.SetTagsRequest true "Теги пользователя"
+// @Produce json
+// @Success 200 {object} dto.SetTagsResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /users/setTags [post]
+// @Tags Users
+func (h *Handlers) UserSetTags() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.SetTagsRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		user, err := h.uc.UserSetTags(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.ErrUserNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.SetTagsResponse{
+			User: user,
+		})
+	}
+}
+
+// UserSetMaxOpenReviews godoc
+// @Summary Задать пользователю ограничение открытых ревью (0 - брать ограничение команды). Кандидаты, достигшие ограничения, не назначаются
+// @Param request body dto.SetMaxOpenReviewsRequest true "Ограничение открытых ревью"
+// @Produce json
+// @Success 200 {object} dto.SetMaxOpenReviewsResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /users/setMaxOpenReviews [post]
+// @Tags Users
+func (h *Handlers) UserSetMaxOpenReviews() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.SetMaxOpenReviewsRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		user, err := h.uc.UserSetMaxOpenReviews(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.ErrUserNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.SetMaxOpenReviewsResponse{
+			User: user,
+		})
+	}
+}
+
+// UserSetPrimaryTeam godoc
+// @Summary Сделать одну из команд пользователя основной. В ней по умолчанию открываются его PR'ы
+// @Param request body dto.SetPrimaryTeamRequest true "Основная команда"
+// @Produce json
+// @Success 200 {object} dto.SetPrimaryTeamResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь не найден или не состоит в команде"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /users/setPrimaryTeam [post]
+// @Tags Users
+func (h *Handlers) UserSetPrimaryTeam() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.SetPrimaryTeamRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		user, err := h.uc.UserSetPrimaryTeam(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.ErrUserNotFound)
+				return
+			}
+			if errors.Is(err, usecases.ErrNotTeamMember) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.SetPrimaryTeamResponse{
 			User: user,
 		})
 	}
 }
 
+// OffboardUser godoc
+// @Summary Уволить пользователя: деактивировать, исключить из команд и переназначить его открытые ревью в одной транзакции. К его открытым PR'ам применяется политика authored_prs_policy: KEEP (по умолчанию) - оставить, CLOSE - закрыть, TRANSFER - передать пользователю transfer_to. История пользователя сохраняется
+// @Param request body dto.OffboardUserRequest true "Пользователь и политика для его PR'ов"
+// @Produce json
+// @Success 200 {object} dto.OffboardUserResponse
+// @Failure 400 {object} dto.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} dto.ErrorResponse "Пользователь или получатель PR'ов не найден"
+// @Failure 409 {object} dto.ErrorResponse "Пользователь или получатель PR'ов уже уволен, получатель неактивен или у него нет основной команды"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка"
+// @Router /users/offboard [post]
+// @Tags Users
+func (h *Handlers) OffboardUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Header.Set(w.Header(), "Content-Type", "application/json")
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrContentTypeNotJson)
+			return
+		}
+
+		var req dto.OffboardUserRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, dto.ErrInvalidBody)
+			return
+		}
+		if err := req.Validate(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, err)
+			return
+		}
+
+		res, err := h.uc.OffboardUser(r.Context(), &req)
+		if err != nil {
+			if errors.Is(err, usecases.ErrUserNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.ErrUserNotFound)
+				return
+			}
+			if errors.Is(err, usecases.ErrTransferTargetNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				render.JSON(w, r, dto.Error(dto.ErrCodeNotFound, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrUserOffboarded) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeUserOffboarded, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrTransferTargetInactive) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeTransferTargetInactive, err.Error()))
+				return
+			}
+			if errors.Is(err, usecases.ErrAuthorWithoutTeam) {
+				w.WriteHeader(http.StatusConflict)
+				render.JSON(w, r, dto.Error(dto.ErrCodeAuthorWithoutTeam, err.Error()))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, dto.ErrInternal)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, dto.OffboardUserResponse{
+			User:          res.User,
+			Policy:        res.Policy,
+			ReassignedPRs: res.ReassignedPRs,
+			AuthoredPRs:   res.AuthoredPRs,
+		})
+	}
+}
+
 // GetUserReviews godoc
 // @Summary Получить PR'ы, где пользователь назначен ревьювером
 // @Param user_id query string true "Идентификатор пользователя"
@@ -3,7 +3,9 @@ package middlewares
 import (
 	"log/slog"
 	"net/http"
+	"pr-review/internal/utils"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -42,6 +44,26 @@ func (m *Middlewares) Recoverer(next http.Handler) http.Handler {
 	return http.HandlerFunc(fn)
 }
 
+// maxActorLength максимальная длина X-Actor, более длинные значения обрезаются
+const maxActorLength = 255
+
+// Actor сохраняет в контексте запроса заголовок X-Actor: кто выполняет операцию, он записывается в историю PR'ов
+func (m *Middlewares) Actor(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		actor := strings.TrimSpace(r.Header.Get("X-Actor"))
+		if runes := []rune(actor); len(runes) > maxActorLength {
+			actor = string(runes[:maxActorLength])
+		}
+		if actor != "" {
+			r = r.WithContext(utils.WithActor(r.Context(), actor))
+		}
+
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
 func (m *Middlewares) RequestLogger() func(next http.Handler) http.Handler {
 	const op = "middlewares.RequestLogger"
 	log := m.log.With(slog.String("op", op))
@@ -20,15 +20,51 @@ func initRouter(h Handlers, m Middlewares) http.Handler {
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Logger)
 	r.Use(m.Recoverer)
+	r.Use(m.Actor)
 
 	r.Get("/team/get", h.GetTeam())
 	r.Post("/team/add", h.AddTeam())
+	r.Post("/team/addMembers", h.AddTeamMembers())
+	r.Post("/team/removeMembers", h.RemoveTeamMembers())
+	r.Put("/team/members", h.SetTeamMembers())
+	r.Post("/team/rename", h.RenameTeam())
+	r.Post("/team/delete", h.DeleteTeam())
+	r.Post("/team/merge", h.MergeTeams())
+	r.Get("/team/settings", h.GetTeamSettings())
+	r.Post("/team/settings", h.UpdateTeamSettings())
+	r.Get("/team/rules", h.GetReviewerRules())
+	r.Post("/team/addRule", h.AddReviewerRule())
+	r.Post("/team/deleteRule", h.DeleteReviewerRule())
 	r.Post("/users/setIsActive", h.UserSetIsActive())
+	r.Post("/users/bulkSetIsActive", h.BulkSetIsActive())
+	r.Post("/users/setTags", h.UserSetTags())
+	r.Post("/users/setMaxOpenReviews", h.UserSetMaxOpenReviews())
+	r.Post("/users/setPrimaryTeam", h.UserSetPrimaryTeam())
+	r.Post("/users/offboard", h.OffboardUser())
 	r.Get("/users/getReview", h.GetUserReviews())
+	r.Post("/users/addAbsence", h.AddAbsence())
+	r.Get("/users/getAbsences", h.GetAbsences())
+	r.Post("/users/deleteAbsence", h.DeleteAbsence())
 	r.Post("/pullRequest/create", h.CreatePR())
 	r.Post("/pullRequest/merge", h.MergePR())
 	r.Post("/pullRequest/reassign", h.ReassignPR())
+	r.Post("/pullRequest/addReviewers", h.AddReviewers())
+	r.Post("/pullRequest/removeReviewer", h.RemoveReviewer())
+	r.Post("/pullRequest/review", h.ReviewPR())
+	r.Post("/pullRequest/update", h.UpdatePR())
+	r.Post("/pullRequest/ready", h.ReadyPR())
+	r.Post("/pullRequest/close", h.ClosePR())
+	r.Post("/pullRequest/reopen", h.ReopenPR())
 	r.Get("/pullRequest/statistics", h.Statistics())
+	r.Get("/pullRequest/assignmentExplain", h.AssignmentExplain())
+	r.Get("/pullRequest/history", h.PRHistory())
+	r.Get("/pullRequest/overdue", h.OverduePRs())
+	r.Get("/pullRequest/list", h.ListPRs())
+	r.Get("/pullRequest/get", h.GetPR())
+	r.Get("/ownership/list", h.GetOwnershipRules())
+	r.Post("/ownership/add", h.AddOwnershipRule())
+	r.Post("/ownership/delete", h.DeleteOwnershipRule())
+	r.Post("/ownership/import", h.ImportCodeowners())
 
 	r.Get("/swagger/doc.json", func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, "./docs/swagger.json")
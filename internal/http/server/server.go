@@ -13,16 +13,52 @@ type Handlers interface {
 	CreatePR() http.HandlerFunc
 	MergePR() http.HandlerFunc
 	ReassignPR() http.HandlerFunc
+	ReviewPR() http.HandlerFunc
+	UpdatePR() http.HandlerFunc
+	ReadyPR() http.HandlerFunc
+	ClosePR() http.HandlerFunc
+	ReopenPR() http.HandlerFunc
 	GetUserReviews() http.HandlerFunc
 	GetTeam() http.HandlerFunc
 	AddTeam() http.HandlerFunc
+	AddTeamMembers() http.HandlerFunc
+	RemoveTeamMembers() http.HandlerFunc
+	SetTeamMembers() http.HandlerFunc
+	RenameTeam() http.HandlerFunc
+	DeleteTeam() http.HandlerFunc
+	MergeTeams() http.HandlerFunc
+	GetTeamSettings() http.HandlerFunc
+	UpdateTeamSettings() http.HandlerFunc
+	GetReviewerRules() http.HandlerFunc
+	AddReviewerRule() http.HandlerFunc
+	DeleteReviewerRule() http.HandlerFunc
 	UserSetIsActive() http.HandlerFunc
+	BulkSetIsActive() http.HandlerFunc
+	UserSetTags() http.HandlerFunc
+	UserSetMaxOpenReviews() http.HandlerFunc
+	UserSetPrimaryTeam() http.HandlerFunc
+	OffboardUser() http.HandlerFunc
+	AddAbsence() http.HandlerFunc
+	GetAbsences() http.HandlerFunc
+	DeleteAbsence() http.HandlerFunc
 	Statistics() http.HandlerFunc
+	AssignmentExplain() http.HandlerFunc
+	PRHistory() http.HandlerFunc
+	OverduePRs() http.HandlerFunc
+	ListPRs() http.HandlerFunc
+	GetPR() http.HandlerFunc
+	AddReviewers() http.HandlerFunc
+	RemoveReviewer() http.HandlerFunc
+	GetOwnershipRules() http.HandlerFunc
+	AddOwnershipRule() http.HandlerFunc
+	DeleteOwnershipRule() http.HandlerFunc
+	ImportCodeowners() http.HandlerFunc
 }
 
 type Middlewares interface {
 	Recoverer(next http.Handler) http.Handler
 	RequestLogger() func(next http.Handler) http.Handler
+	Actor(next http.Handler) http.Handler
 }
 
 type HTTPServer struct {
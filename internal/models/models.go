@@ -1,30 +1,121 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type Status string
 
 var (
+	StatusDraft  Status = "DRAFT"
 	StatusOpen   Status = "OPEN"
 	StatusMerged Status = "MERGED"
+	StatusClosed Status = "CLOSED"
 )
 
+func (s Status) Valid() bool {
+	switch s {
+	case StatusDraft, StatusOpen, StatusMerged, StatusClosed:
+		return true
+	}
+	return false
+}
+
+// statusTransitions допустимые переходы между статусами PR'а, MERGED - конечный статус
+var statusTransitions = map[Status][]Status{
+	StatusDraft:  {StatusOpen, StatusClosed},
+	StatusOpen:   {StatusMerged, StatusClosed},
+	StatusClosed: {StatusOpen},
+}
+
+// CanTransitionTo проверяет, можно ли перевести PR из статуса s в статус to
+func (s Status) CanTransitionTo(to Status) bool {
+	return slices.Contains(statusTransitions[s], to)
+}
+
+// ReviewerStrategy стратегия выбора ревьюверов среди кандидатов команды
+type ReviewerStrategy string
+
+var (
+	StrategyRandom         ReviewerStrategy = "RANDOM"
+	StrategyRoundRobin     ReviewerStrategy = "ROUND_ROBIN"
+	StrategyLeastLoaded    ReviewerStrategy = "LEAST_LOADED"
+	StrategyWeightedRandom ReviewerStrategy = "WEIGHTED_RANDOM"
+)
+
+func (s ReviewerStrategy) Valid() bool {
+	switch s {
+	case StrategyRandom, StrategyRoundRobin, StrategyLeastLoaded, StrategyWeightedRandom:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	Id       string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	// TeamName основная команда пользователя, пусто - не задана
 	TeamName string `json:"team_name"`
+	// Teams все команды пользователя в порядке названий
+	Teams []string `json:"teams"`
+	Tags  []string `json:"tags,omitempty"`
+	// MaxOpenReviews собственное ограничение открытых ревью пользователя, без учёта значения по умолчанию команды
+	MaxOpenReviews *int `json:"max_open_reviews,omitempty"`
+	// OffboardedAt время offboarding'а, nil - пользователь не уволен
+	OffboardedAt *time.Time `json:"offboarded_at,omitempty"`
+}
+
+// Absence период отсутствия пользователя [StartsAt, EndsAt), внутри которого он считается неактивным
+type Absence struct {
+	Id       string    `json:"absence_id"`
+	UserId   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+// AssignmentSource откуда взят назначенный ревьювер
+type AssignmentSource string
+
+var (
+	SourceTeam     AssignmentSource = "TEAM"
+	SourceFallback AssignmentSource = "FALLBACK"
+	SourceOwner    AssignmentSource = "OWNER"
+	SourceRule     AssignmentSource = "RULE"
+	// SourceRequested ревьювер явно запрошен через POST /pullRequest/addReviewers
+	SourceRequested AssignmentSource = "REQUESTED"
+)
+
+// TeamSettings настройки назначения ревьюверов в команде
+type TeamSettings struct {
+	ReviewerStrategy ReviewerStrategy `json:"reviewer_strategy,omitempty"`
+	ReviewersPerPR   int              `json:"reviewers_per_pr"`
+	// MaxOpenReviews ограничение открытых ревью для участников команды, у которых не задано своё
+	MaxOpenReviews *int `json:"max_open_reviews,omitempty"`
+	// RequiredApprovals сколько назначенных ревьюверов должны одобрить PR, чтобы его можно было смержить, 0 - без ограничения
+	RequiredApprovals int `json:"required_approvals"`
+	// ReviewSLAHours за сколько рабочих часов назначенный ревьювер должен принять решение, иначе ревью эскалируется
+	ReviewSLAHours *int `json:"review_sla_hours,omitempty"`
+	// FallbackTeams команды, из которых по порядку добираются ревьюверы, если в команде не хватает кандидатов
+	FallbackTeams []string `json:"fallback_teams"`
 }
 
 type Team struct {
 	Id   string `json:"id"`
 	Name string `json:"name"`
+	TeamSettings
 }
 
 type Member struct {
 	Id       string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	// Tags области экспертизы пользователя (go, sql, frontend...), по ним подбираются ревьюверы под метки PR'а
+	Tags []string `json:"tags,omitempty"`
+	// MaxOpenReviews сколько открытых ревью может быть у пользователя одновременно, nil - берётся значение команды
+	MaxOpenReviews *int `json:"max_open_reviews,omitempty"`
 }
 
 type PullRequestShort struct {
@@ -33,10 +124,355 @@ type PullRequestShort struct {
 	AuthorId          string `json:"author_id"`
 	Status            Status `json:"status"`
 	NeedMoreReviewers bool   `json:"-"`
+	// TeamId команда PR'а, из которой подбираются ревьюверы
+	TeamId string `json:"-"`
+}
+
+// Assignment назначение ревьювера на PR
+type Assignment struct {
+	UserId string           `json:"user_id"`
+	Source AssignmentSource `json:"source"`
+	// TeamName команда, из которой взят ревьювер, если это не команда PR'а
+	TeamName string `json:"team_name,omitempty"`
+	// Decision последнее решение ревьювера, пусто - ещё не ревьюил
+	Decision   ReviewDecision `json:"decision,omitempty"`
+	DecidedAt  *time.Time     `json:"decided_at,omitempty"`
+	AssignedAt time.Time      `json:"assigned_at"`
+}
+
+// ReviewDecision решение ревьювера по PR'у
+type ReviewDecision string
+
+var (
+	DecisionApproved         ReviewDecision = "APPROVED"
+	DecisionChangesRequested ReviewDecision = "CHANGES_REQUESTED"
+	DecisionCommented        ReviewDecision = "COMMENTED"
+)
+
+func (d ReviewDecision) Valid() bool {
+	switch d {
+	case DecisionApproved, DecisionChangesRequested, DecisionCommented:
+		return true
+	}
+	return false
+}
+
+// Review решение ревьювера по PR'у
+type Review struct {
+	Id         string         `json:"review_id"`
+	PrId       string         `json:"pull_request_id"`
+	ReviewerId string         `json:"reviewer_id"`
+	Decision   ReviewDecision `json:"decision"`
+	Comment    string         `json:"comment,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
 }
 
 type PullRequest struct {
 	PullRequestShort
-	Reviewers []string   `json:"assigned_reviewers"`
-	MergedAt  *time.Time `json:"merged_at"`
+	// TeamName команда PR'а, из которой подбираются ревьюверы, пусто - у автора не было команды или она удалена
+	TeamName    string        `json:"team_name,omitempty"`
+	Reviewers   []string      `json:"assigned_reviewers"`
+	Assignments []*Assignment `json:"assignments"`
+	Labels      []string      `json:"labels"`
+	CreatedAt   time.Time     `json:"created_at"`
+	MergedAt    *time.Time    `json:"merged_at"`
+	// NeedMoreReviewersReason почему PR'у не хватает ревьюверов до квоты команды, пусто - хватает
+	NeedMoreReviewersReason NeedMoreReviewersReason `json:"need_more_reviewers_reason,omitempty"`
+	// AppliedRules правила команды, которые запретили или потребовали назначить ревьювера при последнем подборе
+	AppliedRules []*ReviewerRule `json:"applied_rules,omitempty"`
+}
+
+// PRDetails PR вместе с автором и назначенными ревьюверами
+type PRDetails struct {
+	PR                *PullRequest
+	NeedMoreReviewers bool
+	// Author автор PR'а с его командой, nil - пользователь удалён
+	Author *User
+	// Reviewers назначенные ревьюверы в порядке PR.Reviewers
+	Reviewers []*User
+}
+
+// PRSort поле, по которому сортируется список PR'ов
+type PRSort string
+
+var (
+	PRSortCreatedAt PRSort = "created_at"
+	// PRSortMergedAt в список попадают только смерженные PR'ы
+	PRSortMergedAt PRSort = "merged_at"
+	PRSortTitle    PRSort = "title"
+)
+
+func (s PRSort) Valid() bool {
+	switch s {
+	case PRSortCreatedAt, PRSortMergedAt, PRSortTitle:
+		return true
+	}
+	return false
+}
+
+// PRListFilter фильтры, сортировка и страница списка PR'ов. Пустые поля не фильтруют
+type PRListFilter struct {
+	Statuses   []Status
+	AuthorId   string
+	TeamName   string
+	ReviewerId string
+	// NeedMoreReviewers nil - не фильтровать
+	NeedMoreReviewers *bool
+	CreatedFrom       *time.Time
+	CreatedTo         *time.Time
+	MergedFrom        *time.Time
+	MergedTo          *time.Time
+	// Query подстрока названия без учёта регистра
+	Query string
+	Sort  PRSort
+	Desc  bool
+	Limit int
+	// After позиция, после которой начинается страница, nil - с начала списка
+	After *PRCursor
+}
+
+// PRCursor позиция в списке PR'ов: значение поля сортировки и id последнего PR'а страницы.
+// Id нужен, чтобы порядок PR'ов с одинаковым значением был стабильным
+type PRCursor struct {
+	Sort  PRSort `json:"sort"`
+	Desc  bool   `json:"desc"`
+	Value string `json:"value"`
+	Id    string `json:"id"`
+}
+
+// ReviewerRuleKind вид правила подбора ревьюверов
+type ReviewerRuleKind string
+
+var (
+	// RuleExclude ревьювер никогда не назначается на PR'ы автора
+	RuleExclude ReviewerRuleKind = "EXCLUDE"
+	// RuleInclude ревьювер всегда назначается на PR'ы автора
+	RuleInclude ReviewerRuleKind = "INCLUDE"
+)
+
+func (k ReviewerRuleKind) Valid() bool {
+	switch k {
+	case RuleExclude, RuleInclude:
+		return true
+	}
+	return false
+}
+
+// ReviewerRule правило команды для пары автор - ревьювер
+type ReviewerRule struct {
+	Id         string           `json:"rule_id"`
+	TeamName   string           `json:"team_name"`
+	Kind       ReviewerRuleKind `json:"kind"`
+	AuthorId   string           `json:"author_id"`
+	ReviewerId string           `json:"reviewer_id"`
+}
+
+// OverduePR открытый PR, назначенные ревьюверы которого нарушили SLA команды автора
+type OverduePR struct {
+	Id       string           `json:"pull_request_id"`
+	Title    string           `json:"pull_request_name"`
+	AuthorId string           `json:"author_id"`
+	Reviews  []*OverdueReview `json:"overdue_reviews"`
+}
+
+// OverdueReview назначение ревьювера без решения, нарушившее SLA команды автора PR'а
+type OverdueReview struct {
+	PrId       string    `json:"-"`
+	Title      string    `json:"-"`
+	AuthorId   string    `json:"-"`
+	ReviewerId string    `json:"reviewer_id"`
+	AssignedAt time.Time `json:"assigned_at"`
+	// Deadline до какого момента ревьювер должен был принять решение
+	Deadline time.Time `json:"deadline"`
+	// EscalatedAt когда назначение эскалировано, если замену найти не удалось и ревьювер остался
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
+	// SLAHours SLA команды автора в рабочих часах
+	SLAHours int `json:"-"`
+}
+
+// AuthorChangePolicy что делать с ревьюверами PR'а, когда новый автор из другой команды
+type AuthorChangePolicy string
+
+var (
+	// PolicyKeep ревьюверы остаются, недостающие до квоты новой команды добавляются из неё
+	PolicyKeep AuthorChangePolicy = "KEEP"
+	// PolicyReplace ревьюверы снимаются и подбираются заново из команды нового автора
+	PolicyReplace AuthorChangePolicy = "REPLACE"
+)
+
+func (p AuthorChangePolicy) Valid() bool {
+	switch p {
+	case PolicyKeep, PolicyReplace:
+		return true
+	}
+	return false
+}
+
+// OffboardPolicy что делать с открытыми PR'ами уволенного автора
+type OffboardPolicy string
+
+var (
+	// OffboardKeep PR'ы остаются открытыми за уволенным автором
+	OffboardKeep OffboardPolicy = "KEEP"
+	// OffboardClose PR'ы закрываются
+	OffboardClose OffboardPolicy = "CLOSE"
+	// OffboardTransfer PR'ы передаются другому автору
+	OffboardTransfer OffboardPolicy = "TRANSFER"
+)
+
+func (p OffboardPolicy) Valid() bool {
+	switch p {
+	case OffboardKeep, OffboardClose, OffboardTransfer:
+		return true
+	}
+	return false
+}
+
+// ReviewReassignment ревью, снятое с пользователя, и назначенные вместо него ревьюверы
+type ReviewReassignment struct {
+	PrId string `json:"pull_request_id"`
+	// ReplacedBy назначенные вместо пользователя, пусто - замена не нашлась
+	ReplacedBy []string `json:"replaced_by"`
+}
+
+// ActivationChange результат смены активности пользователя в массовой операции
+type ActivationChange struct {
+	UserId   string `json:"user_id"`
+	IsActive bool   `json:"is_active"`
+	// Changed false - пользователь уже был в нужном состоянии
+	Changed bool `json:"changed"`
+	// Reassigned снятые с деактивированного пользователя ревью и назначенные вместо него ревьюверы
+	Reassigned []*ReviewReassignment `json:"reassigned_reviews"`
+}
+
+// Offboarding результат offboarding'а пользователя
+type Offboarding struct {
+	User   *User
+	Policy OffboardPolicy
+	// ReassignedPRs PR'ы, с ревью которых снят пользователь
+	ReassignedPRs []string
+	// AuthoredPRs открытые PR'ы пользователя, к которым применена политика
+	AuthoredPRs []string
+}
+
+// NeedMoreReviewersReason причина, по которой на PR назначено меньше ревьюверов, чем требует команда
+type NeedMoreReviewersReason string
+
+var (
+	// ReasonNoCandidates подходящих кандидатов не хватило
+	ReasonNoCandidates NeedMoreReviewersReason = "NO_CANDIDATES"
+	// ReasonCapacityExceeded кандидаты были, но часть из них пропущена из-за ограничения открытых ревью
+	ReasonCapacityExceeded NeedMoreReviewersReason = "CAPACITY_EXCEEDED"
+	// ReasonReviewerRemoved ревьювера сняли без замены, фоновое дозаполнение такой PR не трогает
+	ReasonReviewerRemoved NeedMoreReviewersReason = "REVIEWER_REMOVED"
+)
+
+// OwnershipRule правило владения путями в стиле CODEOWNERS: файлы, подходящие под Pattern, принадлежат командам Teams и пользователям Users.
+// Если файлу подходят несколько правил, применяется последнее
+type OwnershipRule struct {
+	Id      string   `json:"rule_id"`
+	Pattern string   `json:"pattern"`
+	Teams   []string `json:"teams"`
+	Users   []string `json:"users"`
+	TeamIds []string `json:"-"`
+}
+
+// DecisionTrigger операция, при которой подбирались ревьюверы или менялся PR
+type DecisionTrigger string
+
+var (
+	TriggerCreate       DecisionTrigger = "CREATE"
+	TriggerReassign     DecisionTrigger = "REASSIGN"
+	TriggerDeactivation DecisionTrigger = "DEACTIVATION"
+	TriggerAbsence      DecisionTrigger = "ABSENCE"
+	TriggerReady        DecisionTrigger = "READY"
+	TriggerReopen       DecisionTrigger = "REOPEN"
+	TriggerAuthorChange DecisionTrigger = "AUTHOR_CHANGE"
+	TriggerMerge        DecisionTrigger = "MERGE"
+	TriggerClose        DecisionTrigger = "CLOSE"
+	TriggerTeamSettings DecisionTrigger = "TEAM_SETTINGS"
+	TriggerBackfill     DecisionTrigger = "BACKFILL"
+	TriggerEscalation   DecisionTrigger = "ESCALATION"
+	TriggerRequest      DecisionTrigger = "REQUEST"
+	TriggerRemove       DecisionTrigger = "REMOVE"
+	// TriggerMemberRemoval ревьювер исключён из команды
+	TriggerMemberRemoval DecisionTrigger = "MEMBER_REMOVAL"
+	// TriggerMemberMove участники переведены в другую команду при её удалении или слиянии
+	TriggerMemberMove DecisionTrigger = "MEMBER_MOVE"
+	// TriggerOffboarding ревьювер или автор уволен
+	TriggerOffboarding DecisionTrigger = "OFFBOARDING"
+)
+
+// PREventKind вид события в истории PR'а
+type PREventKind string
+
+var (
+	EventCreated       PREventKind = "CREATED"
+	EventAssigned      PREventKind = "ASSIGNED"
+	EventUnassigned    PREventKind = "UNASSIGNED"
+	EventStatusChanged PREventKind = "STATUS_CHANGED"
+	EventAuthorChanged PREventKind = "AUTHOR_CHANGED"
+	// EventEscalated ревьювер не принял решение за SLA команды
+	EventEscalated PREventKind = "ESCALATED"
+	// EventNeedMoreReviewersChanged изменилась причина need_more_reviewers, пустая причина - ревьюверов хватает
+	EventNeedMoreReviewersChanged PREventKind = "NEED_MORE_REVIEWERS_CHANGED"
+)
+
+// PREvent событие в истории PR'а
+type PREvent struct {
+	Id   int64       `json:"event_id"`
+	PrId string      `json:"pull_request_id"`
+	Kind PREventKind `json:"kind"`
+	// UserId ревьювер, назначенный или снятый с PR'а
+	UserId string `json:"user_id,omitempty"`
+	// From и To прежнее и новое значение: статус, автор или причина need_more_reviewers
+	From   string          `json:"from,omitempty"`
+	To     string          `json:"to,omitempty"`
+	Reason DecisionTrigger `json:"reason"`
+	// Actor кто выполнил операцию (заголовок X-Actor запроса), пусто - неизвестно или фоновая задача
+	Actor     string    `json:"actor,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExclusionReason причина, по которой кандидат не рассматривался
+type ExclusionReason string
+
+var (
+	ExclusionInactive        ExclusionReason = "INACTIVE"
+	ExclusionAuthor          ExclusionReason = "AUTHOR"
+	ExclusionAlreadyAssigned ExclusionReason = "ALREADY_ASSIGNED"
+	ExclusionReplaced        ExclusionReason = "REPLACED"
+	ExclusionAtCapacity      ExclusionReason = "AT_CAPACITY"
+	ExclusionRule            ExclusionReason = "RULE"
+)
+
+// AssignmentDecision запись о подборе ревьюверов на PR.
+// Случайный выбор делается генератором, инициализированным Seed, поэтому по записи решение можно воспроизвести
+type AssignmentDecision struct {
+	Id        string          `json:"decision_id"`
+	PrId      string          `json:"pull_request_id"`
+	Trigger   DecisionTrigger `json:"trigger"`
+	Seed      int64           `json:"seed"`
+	Steps     []*DecisionStep `json:"steps"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// DecisionStep подбор ревьюверов из одной группы кандидатов (обязательные по правилам команды, владельцы, команда автора, резервная команда)
+type DecisionStep struct {
+	Source   AssignmentSource `json:"source"`
+	TeamName string           `json:"team_name,omitempty"`
+	Strategy ReviewerStrategy `json:"strategy,omitempty"`
+	// Labels метки PR'а, ещё не покрытые перед этим шагом
+	Labels []string `json:"uncovered_labels,omitempty"`
+	// Candidates кандидаты в порядке, в котором их расставила стратегия
+	Candidates []string             `json:"candidates"`
+	Excluded   []*ExcludedCandidate `json:"excluded"`
+	Assigned   []string             `json:"assigned"`
+}
+
+type ExcludedCandidate struct {
+	UserId string          `json:"user_id"`
+	Reason ExclusionReason `json:"reason"`
+	// RuleId правило команды, запретившее назначение, для причины RULE
+	RuleId string `json:"rule_id,omitempty"`
 }
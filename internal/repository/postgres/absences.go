@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pr-review/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrAbsenceNotFound = errors.New("absence not found")
+)
+
+// AddAbsence добавляет период отсутствия. Если период уже начался, он сразу помечается обработанным,
+// а started = true: открытые ревью пользователя должны быть сняты в той же транзакции
+func (s *Storage) AddAbsence(ctx context.Context, tx pgx.Tx, absence *models.Absence) (bool, error) {
+	const op = "postgres.AddAbsence"
+
+	var started bool
+	err := tx.QueryRow(ctx, `
+		INSERT INTO absences (id, user_id, starts_at, ends_at, reason, reassigned_at)
+		VALUES ($1, $2, $3, $4, $5, CASE WHEN $3 <= now() THEN now() END)
+		RETURNING reassigned_at IS NOT NULL
+	`, absence.Id, absence.UserId, absence.StartsAt, absence.EndsAt, absence.Reason).Scan(&started)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return false, ErrUserNotFound
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return started, nil
+}
+
+// GetAbsences возвращает ещё не закончившиеся периоды отсутствия пользователя в порядке начала
+func (s *Storage) GetAbsences(ctx context.Context, userId string) ([]*models.Absence, error) {
+	const op = "postgres.GetAbsences"
+
+	rows, err := s.db.Query(ctx, `
+		SELECT id, user_id, starts_at, ends_at, reason
+		FROM absences
+		WHERE user_id = $1 AND ends_at > now()
+		ORDER BY starts_at, id
+	`, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	return scanAbsences(rows, op)
+}
+
+// DeleteAbsence удаляет период отсутствия и возвращает id его пользователя
+func (s *Storage) DeleteAbsence(ctx context.Context, id string) (string, error) {
+	const op = "postgres.DeleteAbsence"
+
+	var userId string
+	err := s.db.QueryRow(ctx, `DELETE FROM absences WHERE id = $1 RETURNING user_id`, id).Scan(&userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrAbsenceNotFound
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userId, nil
+}
+
+// ClaimStartedAbsences помечает обработанными до limit начавшихся, но ещё не обработанных периодов отсутствия и возвращает их.
+// Строки, заблокированные другими транзакциями, пропускаются, поэтому несколько реплик не обработают один период дважды
+func (s *Storage) ClaimStartedAbsences(ctx context.Context, tx pgx.Tx, limit int) ([]*models.Absence, error) {
+	const op = "postgres.ClaimStartedAbsences"
+
+	rows, err := tx.Query(ctx, `
+		UPDATE absences SET reassigned_at = now()
+		WHERE id IN (
+			SELECT id FROM absences
+			WHERE reassigned_at IS NULL AND starts_at <= now() AND ends_at > now()
+			ORDER BY starts_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, starts_at, ends_at, reason
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	return scanAbsences(rows, op)
+}
+
+func scanAbsences(rows pgx.Rows, op string) ([]*models.Absence, error) {
+	absences := make([]*models.Absence, 0)
+	for rows.Next() {
+		var absence models.Absence
+		if err := rows.Scan(&absence.Id, &absence.UserId, &absence.StartsAt, &absence.EndsAt, &absence.Reason); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		absences = append(absences, &absence)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return absences, nil
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"pr-review/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) AddAssignmentDecision(ctx context.Context, tx pgx.Tx, decision *models.AssignmentDecision) error {
+	const op = "postgres.AddAssignmentDecision"
+
+	steps, err := json.Marshal(decision.Steps)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO assignment_decisions (id, pr_id, trigger, seed, steps)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`, decision.Id, decision.PrId, decision.Trigger, decision.Seed, steps).Scan(&decision.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetAssignmentDecisions возвращает решения о подборе ревьюверов PR'а в порядке их принятия
+func (s *Storage) GetAssignmentDecisions(ctx context.Context, prId string) ([]*models.AssignmentDecision, error) {
+	const op = "postgres.GetAssignmentDecisions"
+
+	rows, err := s.db.Query(ctx, `
+		SELECT id, pr_id, trigger, seed, steps, created_at
+		FROM assignment_decisions
+		WHERE pr_id = $1
+		ORDER BY position
+	`, prId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	decisions := make([]*models.AssignmentDecision, 0)
+	for rows.Next() {
+		var decision models.AssignmentDecision
+		var steps []byte
+		if err := rows.Scan(&decision.Id, &decision.PrId, &decision.Trigger, &decision.Seed, &steps, &decision.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := json.Unmarshal(steps, &decision.Steps); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		decisions = append(decisions, &decision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return decisions, nil
+}
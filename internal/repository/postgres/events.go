@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-review/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AddPREvents сохраняет события истории PR'ов в переданном порядке, время событий проставляется базой
+func (s *Storage) AddPREvents(ctx context.Context, tx pgx.Tx, events []*models.PREvent) error {
+	const op = "postgres.AddPREvents"
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	prIds := make([]string, 0, len(events))
+	kinds := make([]string, 0, len(events))
+	userIds := make([]string, 0, len(events))
+	froms := make([]string, 0, len(events))
+	tos := make([]string, 0, len(events))
+	reasons := make([]string, 0, len(events))
+	actors := make([]string, 0, len(events))
+	for _, event := range events {
+		prIds = append(prIds, event.PrId)
+		kinds = append(kinds, string(event.Kind))
+		userIds = append(userIds, event.UserId)
+		froms = append(froms, event.From)
+		tos = append(tos, event.To)
+		reasons = append(reasons, string(event.Reason))
+		actors = append(actors, event.Actor)
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO pr_events (pr_id, kind, user_id, from_value, to_value, reason, actor)
+		SELECT e.pr_id::uuid, e.kind, NULLIF(e.user_id, '')::uuid, NULLIF(e.from_value, ''), NULLIF(e.to_value, ''), e.reason, NULLIF(e.actor, '')
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[])
+			WITH ORDINALITY AS e(pr_id, kind, user_id, from_value, to_value, reason, actor, ord)
+		ORDER BY e.ord
+	`, prIds, kinds, userIds, froms, tos, reasons, actors)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetPREvents возвращает историю PR'а в порядке событий
+func (s *Storage) GetPREvents(ctx context.Context, prId string) ([]*models.PREvent, error) {
+	const op = "postgres.GetPREvents"
+
+	rows, err := s.db.Query(ctx, `
+		SELECT id, pr_id, kind, COALESCE(user_id::text, ''), COALESCE(from_value, ''), COALESCE(to_value, ''),
+			reason, COALESCE(actor, ''), created_at
+		FROM pr_events
+		WHERE pr_id = $1
+		ORDER BY id
+	`, prId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	events := make([]*models.PREvent, 0)
+	for rows.Next() {
+		var event models.PREvent
+		err := rows.Scan(&event.Id, &event.PrId, &event.Kind, &event.UserId, &event.From, &event.To,
+			&event.Reason, &event.Actor, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
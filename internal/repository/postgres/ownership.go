@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pr-review/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrRuleNotFound = errors.New("ownership rule not found")
+)
+
+// GetOwnershipRules возвращает правила владения путями в порядке их применения
+func (s *Storage) GetOwnershipRules(ctx context.Context, tx pgx.Tx) ([]*models.OwnershipRule, error) {
+	const op = "postgres.GetOwnershipRules"
+
+	rows, err := s.conn(tx).Query(ctx, `
+		SELECT r.id, r.pattern,
+			COALESCE(array_agg(t.name ORDER BY t.name) FILTER (WHERE t.id IS NOT NULL), '{}'),
+			COALESCE(array_agg(t.id::text ORDER BY t.name) FILTER (WHERE t.id IS NOT NULL), '{}'),
+			COALESCE(array_agg(o.user_id::text ORDER BY o.user_id) FILTER (WHERE o.user_id IS NOT NULL), '{}')
+		FROM ownership_rules r
+		LEFT JOIN ownership_rule_owners o ON o.rule_id = r.id
+		LEFT JOIN teams t ON o.team_id = t.id
+		GROUP BY r.id, r.pattern, r.position
+		ORDER BY r.position
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	rules := make([]*models.OwnershipRule, 0)
+	for rows.Next() {
+		var rule models.OwnershipRule
+		if err := rows.Scan(&rule.Id, &rule.Pattern, &rule.Teams, &rule.TeamIds, &rule.Users); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		rules = append(rules, &rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rules, nil
+}
+
+// AddOwnershipRule добавляет правило в конец списка, владельцы берутся из TeamIds и Users
+func (s *Storage) AddOwnershipRule(ctx context.Context, tx pgx.Tx, rule *models.OwnershipRule) error {
+	const op = "postgres.AddOwnershipRule"
+
+	_, err := tx.Exec(ctx, `INSERT INTO ownership_rules (id, pattern) VALUES ($1, $2)`, rule.Id, rule.Pattern)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, teamId := range rule.TeamIds {
+		_, err = tx.Exec(ctx, `INSERT INTO ownership_rule_owners (rule_id, team_id) VALUES ($1, $2)`, rule.Id, teamId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	for _, userId := range rule.Users {
+		_, err = tx.Exec(ctx, `INSERT INTO ownership_rule_owners (rule_id, user_id) VALUES ($1, $2)`, rule.Id, userId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteOwnershipRule(ctx context.Context, id string) error {
+	const op = "postgres.DeleteOwnershipRule"
+
+	cmd, err := s.db.Exec(ctx, `DELETE FROM ownership_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrRuleNotFound)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteOwnershipRules(ctx context.Context, tx pgx.Tx) error {
+	const op = "postgres.DeleteOwnershipRules"
+
+	_, err := tx.Exec(ctx, `DELETE FROM ownership_rules`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) AddPRFiles(ctx context.Context, tx pgx.Tx, prId string, files []string) error {
+	const op = "postgres.AddPRFiles"
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO pull_request_files (pr_id, path)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT DO NOTHING
+	`, prId, files)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetPRFiles(ctx context.Context, tx pgx.Tx, prId string) ([]string, error) {
+	const op = "postgres.GetPRFiles"
+
+	rows, err := tx.Query(ctx, `SELECT path FROM pull_request_files WHERE pr_id = $1 ORDER BY path`, prId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	files := make([]string, 0)
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		files = append(files, path)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return files, nil
+}
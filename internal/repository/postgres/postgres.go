@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -40,3 +41,18 @@ func New(config *config.DatabaseConfig) *Storage {
 func (s *Storage) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	return s.db.Begin(ctx)
 }
+
+// querier общий интерфейс пула соединений и транзакции
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn возвращает транзакцию, а если она не передана - пул соединений
+func (s *Storage) conn(tx pgx.Tx) querier {
+	if tx == nil {
+		return s.db
+	}
+	return tx
+}
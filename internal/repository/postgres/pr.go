@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"pr-review/internal/models"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -14,9 +15,10 @@ import (
 var (
 	ErrPRNotFound      = errors.New("pull request not found")
 	ErrPRAlreadyExists = errors.New("pull request already exists")
+	ErrPRStatusChanged = errors.New("pull request status changed concurrently")
 )
 
-// UnassignPRsFromUser удаляет ассайни на юзера со всех открытых PR
+// UnassignPRsFromUser удаляет ассайни на юзера со всех открытых PR и черновиков
 func (s *Storage) UnassignPRsFromUser(ctx context.Context, tx pgx.Tx, userId string) ([]string, error) {
 	const op = "postgres.UnassignPRsFromUser"
 
@@ -27,7 +29,7 @@ func (s *Storage) UnassignPRsFromUser(ctx context.Context, tx pgx.Tx, userId str
 			SELECT s.name FROM pull_requests pr
 			JOIN statuses s ON pr.status_id = s.id
 			WHERE pr.id = pru.pr_id
-		) = 'OPEN'
+		) IN ('OPEN', 'DRAFT')
 		RETURNING pr_id
 	`, userId)
 	if err != nil {
@@ -50,32 +52,57 @@ func (s *Storage) UnassignPRsFromUser(ctx context.Context, tx pgx.Tx, userId str
 	return prIDs, nil
 }
 
-// GetMembers возвращает идентификаторы пользователей из команды автора PR'а, кроме самого автора
-func (s *Storage) GetMembers(ctx context.Context, tx pgx.Tx, prId string) ([]*models.Member, error) {
-	const op = "postgres.GetMembers"
+// UnassignTeamPRsFromUser удаляет ассайни на юзера с открытых PR и черновиков команды teamId и с тех, куда его подобрали из неё
+func (s *Storage) UnassignTeamPRsFromUser(ctx context.Context, tx pgx.Tx, userId string, teamId string) ([]string, error) {
+	const op = "postgres.UnassignTeamPRsFromUser"
 
-	var authorId string
-	err := tx.QueryRow(ctx, `SELECT author_id FROM pull_requests WHERE id = $1`, prId).Scan(&authorId)
+	rows, err := tx.Query(ctx, `
+		DELETE FROM pull_requests_users pru
+		USING pull_requests pr, statuses s
+		WHERE pru.user_id = $1 AND pru.pr_id = pr.id AND pr.status_id = s.id AND s.name IN ('OPEN', 'DRAFT')
+		AND (pr.team_id = $2 OR pru.source_team_id = $2)
+		RETURNING pru.pr_id
+	`, userId, teamId)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer rows.Close()
 
-	var teamId string
-	err = tx.QueryRow(ctx, `SELECT team_id FROM users WHERE id = $1`, authorId).Scan(&teamId)
-	if err != nil {
+	var prIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		prIDs = append(prIDs, id)
+	}
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var members []*models.Member
-	rows, err := tx.Query(ctx, `SELECT id, username, is_active FROM users WHERE team_id = $1 AND id != $2`, teamId, authorId)
+	return prIDs, nil
+}
+
+// GetTeamMembersById возвращает всех участников команды teamId в порядке id
+func (s *Storage) GetTeamMembersById(ctx context.Context, tx pgx.Tx, teamId string) ([]*models.Member, error) {
+	const op = "postgres.GetTeamMembersById"
+
+	rows, err := tx.Query(ctx, `
+		SELECT u.id, u.username, `+activeExpr+`, u.tags, u.max_open_reviews
+		FROM team_members tm
+		JOIN users u ON tm.user_id = u.id
+		WHERE tm.team_id = $1
+		ORDER BY u.id
+	`, teamId)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	defer rows.Close()
 
+	members := make([]*models.Member, 0)
 	for rows.Next() {
 		var member models.Member
-		if err := rows.Scan(&member.Id, &member.Username, &member.IsActive); err != nil {
+		if err := rows.Scan(&member.Id, &member.Username, &member.IsActive, &member.Tags, &member.MaxOpenReviews); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 		members = append(members, &member)
@@ -87,16 +114,17 @@ func (s *Storage) GetMembers(ctx context.Context, tx pgx.Tx, prId string) ([]*mo
 	return members, nil
 }
 
-// AssignPRToUser ассайнит первого пользователя из userIds на PR
-func (s *Storage) AssignPRToUser(ctx context.Context, tx pgx.Tx, prId string, members []*models.Member) (string, error) {
+// AssignPRToUser ассайнит первого пользователя из members на PR.
+// source и sourceTeamId сохраняются в назначении, чтобы было видно, откуда взят ревьювер
+func (s *Storage) AssignPRToUser(ctx context.Context, tx pgx.Tx, prId string, members []*models.Member, source models.AssignmentSource, sourceTeamId string) (string, error) {
 	const op = "postgres.AssignPRToUser"
 
 	for _, member := range members {
 		cmd, err := tx.Exec(ctx, `
-			INSERT INTO pull_requests_users (pr_id, user_id)
-			VALUES ($1, $2)
+			INSERT INTO pull_requests_users (pr_id, user_id, source, source_team_id)
+			VALUES ($1, $2, $3, NULLIF($4, '')::uuid)
 			ON CONFLICT DO NOTHING
-		`, prId, member.Id)
+		`, prId, member.Id, source, sourceTeamId)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				continue // пропускаем, если участник уже зассайнен
@@ -111,11 +139,47 @@ func (s *Storage) AssignPRToUser(ctx context.Context, tx pgx.Tx, prId string, me
 	return "", nil
 }
 
-// SetNeedMoreReviewers устанавливает PR'у флаг need_more_reviewers = true
-func (s *Storage) SetNeedMoreReviewers(ctx context.Context, tx pgx.Tx, prId string) error {
+// GetOpenReviewsCount возвращает количество открытых PR'ов, на которые назначен каждый из пользователей
+func (s *Storage) GetOpenReviewsCount(ctx context.Context, tx pgx.Tx, userIds []string) (map[string]int, error) {
+	const op = "postgres.GetOpenReviewsCount"
+
+	rows, err := tx.Query(ctx, `
+		SELECT pru.user_id, COUNT(*)
+		FROM pull_requests_users pru
+		JOIN pull_requests pr ON pru.pr_id = pr.id
+		JOIN statuses s ON pr.status_id = s.id
+		WHERE s.name = 'OPEN' AND pru.user_id = ANY($1)
+		GROUP BY pru.user_id
+	`, userIds)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int, len(userIds))
+	for rows.Next() {
+		var userId string
+		var count int
+		if err := rows.Scan(&userId, &count); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		counts[userId] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return counts, nil
+}
+
+// SetNeedMoreReviewers устанавливает PR'у флаг need_more_reviewers и его причину. Пустая причина - ревьюверов хватает
+func (s *Storage) SetNeedMoreReviewers(ctx context.Context, tx pgx.Tx, prId string, reason models.NeedMoreReviewersReason) error {
 	const op = "postgres.SetNeedMoreReviewers"
 
-	cmd, err := tx.Exec(ctx, `UPDATE pull_requests SET need_more_reviewers = true WHERE id = $1`, prId)
+	cmd, err := tx.Exec(ctx, `
+		UPDATE pull_requests SET need_more_reviewers = $2 <> '', need_more_reviewers_reason = NULLIF($2, '')
+		WHERE id = $1
+	`, prId, reason)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -131,10 +195,12 @@ func (s *Storage) GetPRsByUserId(ctx context.Context, id string) ([]*models.Pull
 	const op = "postgres.GetPRsByUserId"
 
 	rows, err := s.db.Query(ctx, `
-		SELECT pr.id, pr.title, pr.author_id, s.name, pr.need_more_reviewers, pr.merged_at
+		SELECT pr.id, pr.title, pr.author_id, COALESCE(t.id::text, ''), COALESCE(t.name, ''), s.name, pr.need_more_reviewers,
+			COALESCE(pr.need_more_reviewers_reason, ''), pr.labels, pr.created_at, pr.merged_at
 		FROM pull_requests_users pru
 		JOIN pull_requests pr ON pru.pr_id = pr.id
 		JOIN statuses s ON pr.status_id = s.id
+		LEFT JOIN teams t ON pr.team_id = t.id
 		WHERE user_id = $1`, id)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -145,7 +211,7 @@ func (s *Storage) GetPRsByUserId(ctx context.Context, id string) ([]*models.Pull
 	for rows.Next() {
 		var pr models.PullRequest
 		if err := rows.Scan(
-			&pr.Id, &pr.Title, &pr.AuthorId, &pr.Status, &pr.NeedMoreReviewers, &pr.MergedAt,
+			&pr.Id, &pr.Title, &pr.AuthorId, &pr.TeamId, &pr.TeamName, &pr.Status, &pr.NeedMoreReviewers, &pr.NeedMoreReviewersReason, &pr.Labels, &pr.CreatedAt, &pr.MergedAt,
 		); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -156,34 +222,58 @@ func (s *Storage) GetPRsByUserId(ctx context.Context, id string) ([]*models.Pull
 	}
 
 	for _, pr := range prs {
-		rows, err := s.db.Query(ctx, `
-			SELECT user_id FROM pull_requests_users WHERE pr_id = $1
-		`, pr.Id)
+		pr.Reviewers, pr.Assignments, err = s.getAssignments(ctx, s.db, pr.Id)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		defer rows.Close()
-		reviewers := make([]string, 0)
-		for rows.Next() {
-			var reviewerId string
-			if err := rows.Scan(&reviewerId); err != nil {
-				return nil, fmt.Errorf("%s: %w", op, err)
-			}
-			reviewers = append(reviewers, reviewerId)
-		}
-		pr.Reviewers = reviewers
 	}
 
 	return prs, nil
 }
 
+// getAssignments возвращает идентификаторы ревьюверов PR'а и их назначения с последним решением каждого ревьювера
+func (s *Storage) getAssignments(ctx context.Context, q querier, prId string) ([]string, []*models.Assignment, error) {
+	rows, err := q.Query(ctx, `
+		SELECT pru.user_id, pru.source, COALESCE(t.name, ''), COALESCE(rv.decision, ''), rv.created_at, pru.assigned_at
+		FROM pull_requests_users pru
+		LEFT JOIN teams t ON pru.source_team_id = t.id
+		LEFT JOIN LATERAL (
+			SELECT r.decision, r.created_at FROM reviews r
+			WHERE r.pr_id = pru.pr_id AND r.user_id = pru.user_id
+			ORDER BY r.position DESC
+			LIMIT 1
+		) rv ON true
+		WHERE pru.pr_id = $1
+	`, prId)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	reviewers := make([]string, 0)
+	assignments := make([]*models.Assignment, 0)
+	for rows.Next() {
+		var assignment models.Assignment
+		if err := rows.Scan(&assignment.UserId, &assignment.Source, &assignment.TeamName, &assignment.Decision, &assignment.DecidedAt, &assignment.AssignedAt); err != nil {
+			return nil, nil, err
+		}
+		reviewers = append(reviewers, assignment.UserId)
+		assignments = append(assignments, &assignment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return reviewers, assignments, nil
+}
+
 func (s *Storage) CreatePR(ctx context.Context, tx pgx.Tx, pr *models.PullRequestShort) error {
 	const op = "postgres.CreatePR"
 
 	_, err := tx.Exec(ctx, `
-		INSERT INTO pull_requests (id, title, author_id, status_id, need_more_reviewers)
-		VALUES ($1, $2, $3, (SELECT id FROM statuses WHERE name = $4), $5)
-	`, pr.Id, pr.Title, pr.AuthorId, pr.Status, pr.NeedMoreReviewers)
+		INSERT INTO pull_requests (id, title, author_id, team_id, status_id, need_more_reviewers)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid, (SELECT id FROM statuses WHERE name = $5), $6)
+	`, pr.Id, pr.Title, pr.AuthorId, pr.TeamId, pr.Status, pr.NeedMoreReviewers)
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
 			return fmt.Errorf("%s: %w", op, ErrPRAlreadyExists)
@@ -194,6 +284,57 @@ func (s *Storage) CreatePR(ctx context.Context, tx pgx.Tx, pr *models.PullReques
 	return nil
 }
 
+// SetPRLabels заменяет метки PR'а
+func (s *Storage) SetPRLabels(ctx context.Context, tx pgx.Tx, prId string, labels []string) error {
+	const op = "postgres.SetPRLabels"
+
+	cmd, err := tx.Exec(ctx, `UPDATE pull_requests SET labels = $2 WHERE id = $1`, prId, labels)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrPRNotFound)
+	}
+
+	return nil
+}
+
+// GetUncoveredLabels возвращает метки PR'а, которых нет в тегах ни у одного из назначенных ревьюверов
+func (s *Storage) GetUncoveredLabels(ctx context.Context, tx pgx.Tx, prId string) ([]string, error) {
+	const op = "postgres.GetUncoveredLabels"
+
+	rows, err := tx.Query(ctx, `
+		SELECT l.label
+		FROM pull_requests pr, unnest(pr.labels) AS l(label)
+		WHERE pr.id = $1 AND NOT EXISTS (
+			SELECT 1 FROM pull_requests_users pru
+			JOIN users u ON pru.user_id = u.id
+			WHERE pru.pr_id = pr.id AND l.label = ANY(u.tags)
+		)
+	`, prId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	labels := make([]string, 0)
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		labels = append(labels, label)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return labels, nil
+}
+
+// UpdatePR изменяет непустые поля PR'а: название, автора, команду и статус.
+// need_more_reviewers меняется отдельно через SetNeedMoreReviewers, чтобы не потерять причину
 func (s *Storage) UpdatePR(ctx context.Context, tx pgx.Tx, pr *models.PullRequestShort) error {
 	const op = "postgres.UpdatePR"
 
@@ -204,10 +345,15 @@ func (s *Storage) UpdatePR(ctx context.Context, tx pgx.Tx, pr *models.PullReques
 	if pr.AuthorId != "" {
 		values["author_id"] = pr.AuthorId
 	}
+	if pr.TeamId != "" {
+		values["team_id"] = pr.TeamId
+	}
 	if pr.Status != "" {
 		values["status_id"] = sq.Expr("(SELECT id FROM statuses WHERE name = ?)", pr.Status)
 	}
-	values["need_more_reviewers"] = pr.NeedMoreReviewers
+	if len(values) == 0 {
+		return nil
+	}
 
 	builder := sq.Update("pull_requests").SetMap(values).Where(sq.Eq{"id": pr.Id}).PlaceholderFormat(sq.Dollar)
 	sql, args, err := builder.ToSql()
@@ -247,12 +393,14 @@ func (s *Storage) GetPRById(ctx context.Context, tx pgx.Tx, id string) (*models.
 
 	var pr models.PullRequest
 
-	err := tx.QueryRow(ctx, `
-		SELECT pr.id, pr.title, pr.author_id, s.name, pr.need_more_reviewers
+	err := s.conn(tx).QueryRow(ctx, `
+		SELECT pr.id, pr.title, pr.author_id, COALESCE(t.id::text, ''), COALESCE(t.name, ''), s.name, pr.need_more_reviewers,
+			COALESCE(pr.need_more_reviewers_reason, ''), pr.labels, pr.created_at
 		FROM pull_requests pr
 		JOIN statuses s ON pr.status_id = s.id
+		LEFT JOIN teams t ON pr.team_id = t.id
 		WHERE pr.id = $1
-	`, id).Scan(&pr.Id, &pr.Title, &pr.AuthorId, &pr.Status, &pr.NeedMoreReviewers)
+	`, id).Scan(&pr.Id, &pr.Title, &pr.AuthorId, &pr.TeamId, &pr.TeamName, &pr.Status, &pr.NeedMoreReviewers, &pr.NeedMoreReviewersReason, &pr.Labels, &pr.CreatedAt)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrPRNotFound
 	}
@@ -260,36 +408,94 @@ func (s *Storage) GetPRById(ctx context.Context, tx pgx.Tx, id string) (*models.
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	rows, err := tx.Query(ctx, `
-		SELECT user_id FROM pull_requests_users WHERE pr_id = $1
-	`, id)
+	pr.Reviewers, pr.Assignments, err = s.getAssignments(ctx, s.conn(tx), id)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	err = s.conn(tx).QueryRow(ctx, `
+		SELECT merged_at FROM pull_requests WHERE id = $1
+	`, id).Scan(&pr.MergedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &pr, nil
+}
+
+// GetOpenPRIdsByAuthor блокирует и возвращает открытые PR'ы автора authorId в порядке id
+func (s *Storage) GetOpenPRIdsByAuthor(ctx context.Context, tx pgx.Tx, authorId string) ([]string, error) {
+	const op = "postgres.GetOpenPRIdsByAuthor"
+
+	rows, err := tx.Query(ctx, `
+		SELECT pr.id
+		FROM pull_requests pr
+		JOIN statuses s ON pr.status_id = s.id
+		WHERE pr.author_id = $1 AND s.name = 'OPEN'
+		ORDER BY pr.id
+		FOR UPDATE OF pr
+	`, authorId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 	defer rows.Close()
 
-	var reviewers []string
+	prIds := make([]string, 0)
 	for rows.Next() {
-		var reviewerId string
-		if err := rows.Scan(&reviewerId); err != nil {
+		var id string
+		if err := rows.Scan(&id); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
-		reviewers = append(reviewers, reviewerId)
+		prIds = append(prIds, id)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-	pr.Reviewers = reviewers
 
-	err = tx.QueryRow(ctx, `
-		SELECT merged_at FROM pull_requests WHERE id = $1
-	`, id).Scan(&pr.MergedAt)
+	return prIds, nil
+}
+
+// SetPRStatus переводит PR из статуса from в статус to. Если статус PR'а уже не from, возвращает ErrPRStatusChanged
+func (s *Storage) SetPRStatus(ctx context.Context, tx pgx.Tx, prId string, from models.Status, to models.Status) error {
+	const op = "postgres.SetPRStatus"
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE pull_requests SET status_id = (SELECT id FROM statuses WHERE name = $3)
+		WHERE id = $1 AND status_id = (SELECT id FROM statuses WHERE name = $2)
+	`, prId, from, to)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrPRStatusChanged)
+	}
+
+	return nil
+}
+
+// UnassignAllFromPR снимает с PR'а всех ревьюверов и возвращает их id
+func (s *Storage) UnassignAllFromPR(ctx context.Context, tx pgx.Tx, prId string) ([]string, error) {
+	const op = "postgres.UnassignAllFromPR"
+
+	rows, err := tx.Query(ctx, `DELETE FROM pull_requests_users WHERE pr_id = $1 RETURNING user_id`, prId)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer rows.Close()
 
-	return &pr, nil
+	userIds := make([]string, 0)
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		userIds = append(userIds, userId)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userIds, nil
 }
 
 func (s *Storage) UnassignPRFromUser(ctx context.Context, tx pgx.Tx, prId string, userId string) error {
@@ -376,3 +582,151 @@ func (s *Storage) GetStatistics(ctx context.Context, page int, limit int) (map[s
 
 	return statistics, count, nil
 }
+
+// ClaimUnderstaffedPRs блокирует и возвращает до limit открытых PR'ов с need_more_reviewers и id больше afterId в порядке id.
+// Если передан userIds, берутся только PR'ы, на которые их можно подобрать: PR'ы их команд и команд,
+// для которых их команды резервные. PR'ы, заблокированные другими транзакциями, пропускаются,
+// поэтому несколько реплик не дозаполняют один PR одновременно.
+// PR'ы, ревьювера которых сняли без замены, и PR'ы без команды не дозаполняются
+func (s *Storage) ClaimUnderstaffedPRs(ctx context.Context, tx pgx.Tx, afterId string, limit int, userIds []string) ([]string, error) {
+	const op = "postgres.ClaimUnderstaffedPRs"
+
+	rows, err := tx.Query(ctx, `
+		SELECT pr.id FROM pull_requests pr
+		WHERE pr.need_more_reviewers AND pr.id > $1
+		AND pr.need_more_reviewers_reason IS DISTINCT FROM 'REVIEWER_REMOVED'
+		AND pr.team_id IS NOT NULL
+		AND pr.status_id = (SELECT id FROM statuses WHERE name = 'OPEN')
+		AND ($3::uuid[] IS NULL OR pr.team_id IN (
+			SELECT tm.team_id FROM team_members tm WHERE tm.user_id = ANY($3)
+			UNION
+			SELECT tf.team_id FROM team_fallbacks tf
+			JOIN team_members tm ON tm.team_id = tf.fallback_team_id
+			WHERE tm.user_id = ANY($3)
+		))
+		ORDER BY pr.id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, afterId, limit, userIds)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	prIds := make([]string, 0)
+	for rows.Next() {
+		var prId string
+		if err := rows.Scan(&prId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		prIds = append(prIds, prId)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prIds, nil
+}
+
+// ListPRs возвращает до filter.Limit PR'ов, подходящих под фильтры, в порядке filter.Sort после позиции filter.After.
+// PR'ы с одинаковым значением поля сортировки упорядочены по id, поэтому страницы не пересекаются
+func (s *Storage) ListPRs(ctx context.Context, filter *models.PRListFilter) ([]*models.PullRequest, error) {
+	const op = "postgres.ListPRs"
+
+	sortColumn, sortType := "pr.created_at", "timestamptz"
+	switch filter.Sort {
+	case models.PRSortMergedAt:
+		sortColumn = "pr.merged_at"
+	case models.PRSortTitle:
+		sortColumn, sortType = "pr.title", "text"
+	}
+	direction, cmp := "ASC", ">"
+	if filter.Desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	builder := sq.Select(
+		"pr.id", "pr.title", "pr.author_id", "COALESCE(t.id::text, '')", "COALESCE(t.name, '')", "s.name", "pr.need_more_reviewers",
+		"COALESCE(pr.need_more_reviewers_reason, '')", "pr.labels", "pr.created_at", "pr.merged_at",
+	).
+		From("pull_requests pr").
+		Join("statuses s ON pr.status_id = s.id").
+		LeftJoin("teams t ON pr.team_id = t.id").
+		OrderBy(sortColumn+" "+direction, "pr.id "+direction).
+		Limit(uint64(filter.Limit)).
+		PlaceholderFormat(sq.Dollar)
+
+	if len(filter.Statuses) > 0 {
+		builder = builder.Where(sq.Eq{"s.name": filter.Statuses})
+	}
+	if filter.AuthorId != "" {
+		builder = builder.Where(sq.Eq{"pr.author_id": filter.AuthorId})
+	}
+	if filter.TeamName != "" {
+		builder = builder.Where(sq.Eq{"t.name": filter.TeamName})
+	}
+	if filter.ReviewerId != "" {
+		builder = builder.Where("EXISTS (SELECT 1 FROM pull_requests_users pru WHERE pru.pr_id = pr.id AND pru.user_id = ?)", filter.ReviewerId)
+	}
+	if filter.NeedMoreReviewers != nil {
+		builder = builder.Where(sq.Eq{"pr.need_more_reviewers": *filter.NeedMoreReviewers})
+	}
+	if filter.CreatedFrom != nil {
+		builder = builder.Where(sq.GtOrEq{"pr.created_at": *filter.CreatedFrom})
+	}
+	if filter.CreatedTo != nil {
+		builder = builder.Where(sq.Lt{"pr.created_at": *filter.CreatedTo})
+	}
+	if filter.MergedFrom != nil {
+		builder = builder.Where(sq.GtOrEq{"pr.merged_at": *filter.MergedFrom})
+	}
+	if filter.MergedTo != nil {
+		builder = builder.Where(sq.Lt{"pr.merged_at": *filter.MergedTo})
+	}
+	if filter.Sort == models.PRSortMergedAt {
+		builder = builder.Where("pr.merged_at IS NOT NULL")
+	}
+	if filter.Query != "" {
+		builder = builder.Where("pr.title ILIKE ?", "%"+likeEscaper.Replace(filter.Query)+"%")
+	}
+	if filter.After != nil {
+		builder = builder.Where("("+sortColumn+", pr.id) "+cmp+" (?::"+sortType+", ?::uuid)", filter.After.Value, filter.After.Id)
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	prs := make([]*models.PullRequest, 0)
+	for rows.Next() {
+		var pr models.PullRequest
+		if err := rows.Scan(
+			&pr.Id, &pr.Title, &pr.AuthorId, &pr.TeamId, &pr.TeamName, &pr.Status, &pr.NeedMoreReviewers, &pr.NeedMoreReviewersReason, &pr.Labels, &pr.CreatedAt, &pr.MergedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		prs = append(prs, &pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, pr := range prs {
+		pr.Reviewers, pr.Assignments, err = s.getAssignments(ctx, s.db, pr.Id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return prs, nil
+}
+
+// likeEscaper экранирует спецсимволы шаблона LIKE, чтобы подстрока искалась буквально
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pr-review/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrReviewerRuleNotFound = errors.New("reviewer rule not found")
+	ErrReviewerRuleExists   = errors.New("reviewer rule for this author and reviewer already exists")
+)
+
+// GetReviewerRules возвращает правила подбора ревьюверов команды teamId
+func (s *Storage) GetReviewerRules(ctx context.Context, tx pgx.Tx, teamId string) ([]*models.ReviewerRule, error) {
+	const op = "postgres.GetReviewerRules"
+
+	rows, err := s.conn(tx).Query(ctx, `
+		SELECT r.id, t.name, r.kind, r.author_id, r.reviewer_id
+		FROM reviewer_rules r
+		JOIN teams t ON r.team_id = t.id
+		WHERE r.team_id = $1
+		ORDER BY r.author_id, r.reviewer_id
+	`, teamId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	rules := make([]*models.ReviewerRule, 0)
+	for rows.Next() {
+		var rule models.ReviewerRule
+		if err := rows.Scan(&rule.Id, &rule.TeamName, &rule.Kind, &rule.AuthorId, &rule.ReviewerId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		rules = append(rules, &rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rules, nil
+}
+
+func (s *Storage) AddReviewerRule(ctx context.Context, teamId string, rule *models.ReviewerRule) error {
+	const op = "postgres.AddReviewerRule"
+
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO reviewer_rules (id, team_id, kind, author_id, reviewer_id)
+		VALUES ($1, $2, $3, $4, $5)
+	`, rule.Id, teamId, rule.Kind, rule.AuthorId, rule.ReviewerId)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok {
+			switch pgErr.Code {
+			case "23505":
+				return ErrReviewerRuleExists
+			case "23503":
+				return ErrUserNotFound
+			}
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteReviewerRule(ctx context.Context, id string) error {
+	const op = "postgres.DeleteReviewerRule"
+
+	cmd, err := s.db.Exec(ctx, `DELETE FROM reviewer_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrReviewerRuleNotFound
+	}
+
+	return nil
+}
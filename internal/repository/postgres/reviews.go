@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-review/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AddReview сохраняет решение ревьювера, время решения проставляется базой
+func (s *Storage) AddReview(ctx context.Context, tx pgx.Tx, review *models.Review) error {
+	const op = "postgres.AddReview"
+
+	err := tx.QueryRow(ctx, `
+		INSERT INTO reviews (id, pr_id, user_id, decision, comment)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`, review.Id, review.PrId, review.ReviewerId, review.Decision, review.Comment).Scan(&review.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CountApprovals возвращает количество назначенных на PR ревьюверов, последнее решение которых - APPROVED
+func (s *Storage) CountApprovals(ctx context.Context, tx pgx.Tx, prId string) (int, error) {
+	const op = "postgres.CountApprovals"
+
+	var count int
+	err := tx.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM pull_requests_users pru
+		WHERE pru.pr_id = $1 AND (
+			SELECT r.decision FROM reviews r
+			WHERE r.pr_id = pru.pr_id AND r.user_id = pru.user_id
+			ORDER BY r.position DESC
+			LIMIT 1
+		) = 'APPROVED'
+	`, prId).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"pr-review/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// unreviewedAssignments назначения pru на открытые PR'ы команд с SLA, по которым ревьювер не принял решения после назначения
+// и которые назначены не меньше SLA календарных часов назад. Рабочие часы проверяются вызывающим
+const unreviewedAssignments = `
+	FROM pull_requests_users pru
+	JOIN pull_requests pr ON pru.pr_id = pr.id
+	JOIN teams t ON pr.team_id = t.id
+	WHERE t.review_sla_hours IS NOT NULL
+	AND pr.status_id = (SELECT id FROM statuses WHERE name = 'OPEN')
+	AND pru.assigned_at <= now() - make_interval(hours => t.review_sla_hours)
+	AND NOT EXISTS (
+		SELECT 1 FROM reviews r
+		WHERE r.pr_id = pru.pr_id AND r.user_id = pru.user_id AND r.created_at >= pru.assigned_at
+	)`
+
+const unreviewedAssignmentsColumns = `SELECT pru.pr_id, pr.title, pr.author_id, pru.user_id, pru.assigned_at, pru.escalated_at, t.review_sla_hours`
+
+// GetUnreviewedAssignments возвращает назначения без решения на открытые PR'ы команды teamId,
+// которые могли нарушить SLA команды, в порядке PR'ов и времени назначения
+func (s *Storage) GetUnreviewedAssignments(ctx context.Context, teamId string) ([]*models.OverdueReview, error) {
+	const op = "postgres.GetUnreviewedAssignments"
+
+	rows, err := s.db.Query(ctx, unreviewedAssignmentsColumns+unreviewedAssignments+`
+		AND t.id = $1
+		ORDER BY pru.pr_id, pru.assigned_at, pru.user_id
+	`, teamId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	return scanOverdueReviews(rows, op)
+}
+
+// ClaimUnreviewedAssignments блокирует и возвращает до limit ещё не эскалированных назначений без решения, которые могли нарушить SLA,
+// после пары (afterPrId, afterUserId) в порядке PR'ов и ревьюверов.
+// Назначения, заблокированные другими транзакциями, пропускаются, поэтому несколько реплик не эскалируют одно назначение дважды
+func (s *Storage) ClaimUnreviewedAssignments(ctx context.Context, tx pgx.Tx, afterPrId string, afterUserId string, limit int) ([]*models.OverdueReview, error) {
+	const op = "postgres.ClaimUnreviewedAssignments"
+
+	rows, err := tx.Query(ctx, unreviewedAssignmentsColumns+unreviewedAssignments+`
+		AND pru.escalated_at IS NULL AND (pru.pr_id, pru.user_id) > ($1, $2)
+		ORDER BY pru.pr_id, pru.user_id
+		LIMIT $3
+		FOR UPDATE OF pru SKIP LOCKED
+	`, afterPrId, afterUserId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	return scanOverdueReviews(rows, op)
+}
+
+// MarkEscalated запоминает, что назначение эскалировано, но ревьювер остался, чтобы не эскалировать его повторно
+func (s *Storage) MarkEscalated(ctx context.Context, tx pgx.Tx, prId string, userId string) error {
+	const op = "postgres.MarkEscalated"
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE pull_requests_users SET escalated_at = now()
+		WHERE pr_id = $1 AND user_id = $2
+	`, prId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrPRNotFound)
+	}
+
+	return nil
+}
+
+func scanOverdueReviews(rows pgx.Rows, op string) ([]*models.OverdueReview, error) {
+	reviews := make([]*models.OverdueReview, 0)
+	for rows.Next() {
+		var review models.OverdueReview
+		err := rows.Scan(&review.PrId, &review.Title, &review.AuthorId, &review.ReviewerId,
+			&review.AssignedAt, &review.EscalatedAt, &review.SLAHours)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		reviews = append(reviews, &review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return reviews, nil
+}
@@ -12,6 +12,7 @@ import (
 
 var (
 	ErrTeamAlredyExists = errors.New("team already exists")
+	ErrTeamNotFound     = errors.New("team not found")
 )
 
 func (s *Storage) TeamExists(ctx context.Context, name string) (bool, error) {
@@ -33,7 +34,10 @@ func (s *Storage) TeamExists(ctx context.Context, name string) (bool, error) {
 func (s *Storage) CreateTeam(ctx context.Context, tx pgx.Tx, team *models.Team) error {
 	const op = "postgres.CreateTeam"
 
-	_, err := tx.Exec(ctx, `INSERT INTO teams (id, name) VALUES ($1, $2)`, team.Id, team.Name)
+	_, err := tx.Exec(ctx, `
+		INSERT INTO teams (id, name, reviewer_strategy)
+		VALUES ($1, $2, NULLIF($3, ''))
+	`, team.Id, team.Name, team.ReviewerStrategy)
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
 			return fmt.Errorf("%s: %w", op, ErrTeamAlredyExists)
@@ -43,3 +47,308 @@ func (s *Storage) CreateTeam(ctx context.Context, tx pgx.Tx, team *models.Team)
 
 	return nil
 }
+
+// GetTeamByPRId возвращает команду PR'а
+func (s *Storage) GetTeamByPRId(ctx context.Context, tx pgx.Tx, prId string) (*models.Team, error) {
+	const op = "postgres.GetTeamByPRId"
+
+	var team models.Team
+	err := tx.QueryRow(ctx, `
+		SELECT t.id, t.name, COALESCE(t.reviewer_strategy, ''), t.reviewers_per_pr, t.max_open_reviews, t.required_approvals, t.review_sla_hours
+		FROM pull_requests pr
+		JOIN teams t ON pr.team_id = t.id
+		WHERE pr.id = $1
+	`, prId).Scan(&team.Id, &team.Name, &team.ReviewerStrategy, &team.ReviewersPerPR, &team.MaxOpenReviews, &team.RequiredApprovals, &team.ReviewSLAHours)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, ErrTeamNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &team, nil
+}
+
+// GetTeamByName возвращает команду по имени. tx может быть nil, тогда запрос выполняется вне транзакции
+func (s *Storage) GetTeamByName(ctx context.Context, tx pgx.Tx, name string) (*models.Team, error) {
+	const op = "postgres.GetTeamByName"
+
+	var team models.Team
+	err := s.conn(tx).QueryRow(ctx, `
+		SELECT id, name, COALESCE(reviewer_strategy, ''), reviewers_per_pr, max_open_reviews, required_approvals, review_sla_hours
+		FROM teams
+		WHERE name = $1
+	`, name).Scan(&team.Id, &team.Name, &team.ReviewerStrategy, &team.ReviewersPerPR, &team.MaxOpenReviews, &team.RequiredApprovals, &team.ReviewSLAHours)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, ErrTeamNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &team, nil
+}
+
+// UpdateTeamSettings сохраняет настройки назначения ревьюверов команды
+func (s *Storage) UpdateTeamSettings(ctx context.Context, tx pgx.Tx, team *models.Team) error {
+	const op = "postgres.UpdateTeamSettings"
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE teams SET reviewer_strategy = NULLIF($2, ''), reviewers_per_pr = $3, max_open_reviews = $4, required_approvals = $5, review_sla_hours = $6
+		WHERE id = $1
+	`, team.Id, team.ReviewerStrategy, team.ReviewersPerPR, team.MaxOpenReviews, team.RequiredApprovals, team.ReviewSLAHours)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrTeamNotFound)
+	}
+
+	return nil
+}
+
+// RefreshTeamNeedMoreReviewers пересчитывает need_more_reviewers у открытых PR'ов команды по её квоте ревьюверов.
+// Причина у PR'ов, которым по-прежнему не хватает ревьюверов, сохраняется, у остальных - сбрасывается
+// Возвращает изменившиеся причины как события истории PR'ов, операцию и автора изменения заполняет вызывающий
+func (s *Storage) RefreshTeamNeedMoreReviewers(ctx context.Context, tx pgx.Tx, teamId string) ([]*models.PREvent, error) {
+	const op = "postgres.RefreshTeamNeedMoreReviewers"
+
+	rows, err := tx.Query(ctx, `
+		WITH old AS (
+			SELECT pr.id, COALESCE(pr.need_more_reviewers_reason, '') AS reason
+			FROM pull_requests pr
+			WHERE pr.team_id = $1 AND pr.status_id = (SELECT id FROM statuses WHERE name = 'OPEN')
+			FOR UPDATE OF pr
+		)
+		UPDATE pull_requests pr
+		SET need_more_reviewers = (
+			SELECT COUNT(*) FROM pull_requests_users pru WHERE pru.pr_id = pr.id
+		) < t.reviewers_per_pr,
+		need_more_reviewers_reason = CASE WHEN (
+			SELECT COUNT(*) FROM pull_requests_users pru WHERE pru.pr_id = pr.id
+		) < t.reviewers_per_pr THEN COALESCE(pr.need_more_reviewers_reason, 'NO_CANDIDATES') END
+		FROM old, teams t
+		WHERE pr.id = old.id AND t.id = $1
+		RETURNING pr.id, old.reason, COALESCE(pr.need_more_reviewers_reason, '')
+	`, teamId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	changes := make([]*models.PREvent, 0)
+	for rows.Next() {
+		change := &models.PREvent{Kind: models.EventNeedMoreReviewersChanged}
+		if err := rows.Scan(&change.PrId, &change.From, &change.To); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if change.From != change.To {
+			changes = append(changes, change)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return changes, nil
+}
+
+// GetFallbackTeams возвращает резервные команды в порядке их приоритета
+func (s *Storage) GetFallbackTeams(ctx context.Context, tx pgx.Tx, teamId string) ([]*models.Team, error) {
+	const op = "postgres.GetFallbackTeams"
+
+	rows, err := s.conn(tx).Query(ctx, `
+		SELECT t.id, t.name, COALESCE(t.reviewer_strategy, ''), t.reviewers_per_pr, t.max_open_reviews, t.required_approvals, t.review_sla_hours
+		FROM team_fallbacks tf
+		JOIN teams t ON tf.fallback_team_id = t.id
+		WHERE tf.team_id = $1
+		ORDER BY tf.position
+	`, teamId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	teams := make([]*models.Team, 0)
+	for rows.Next() {
+		var team models.Team
+		if err := rows.Scan(&team.Id, &team.Name, &team.ReviewerStrategy, &team.ReviewersPerPR, &team.MaxOpenReviews, &team.RequiredApprovals, &team.ReviewSLAHours); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		teams = append(teams, &team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return teams, nil
+}
+
+// SetFallbackTeams заменяет список резервных команд, порядок fallbackTeamIds задаёт приоритет
+func (s *Storage) SetFallbackTeams(ctx context.Context, tx pgx.Tx, teamId string, fallbackTeamIds []string) error {
+	const op = "postgres.SetFallbackTeams"
+
+	_, err := tx.Exec(ctx, `DELETE FROM team_fallbacks WHERE team_id = $1`, teamId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i, fallbackTeamId := range fallbackTeamIds {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO team_fallbacks (team_id, fallback_team_id, position)
+			VALUES ($1, $2, $3)
+		`, teamId, fallbackTeamId, i)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+// GetLastReviewer возвращает последнего назначенного ревьювера команды и блокирует строку команды до конца транзакции
+func (s *Storage) GetLastReviewer(ctx context.Context, tx pgx.Tx, teamId string) (string, error) {
+	const op = "postgres.GetLastReviewer"
+
+	var userId string
+	err := tx.QueryRow(ctx, `
+		SELECT COALESCE(last_reviewer_id::text, '') FROM teams WHERE id = $1 FOR UPDATE
+	`, teamId).Scan(&userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, ErrTeamNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userId, nil
+}
+
+func (s *Storage) SetLastReviewer(ctx context.Context, tx pgx.Tx, teamId string, userId string) error {
+	const op = "postgres.SetLastReviewer"
+
+	cmd, err := tx.Exec(ctx, `UPDATE teams SET last_reviewer_id = $2 WHERE id = $1`, teamId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrTeamNotFound)
+	}
+
+	return nil
+}
+
+// RenameTeam меняет имя команды
+func (s *Storage) RenameTeam(ctx context.Context, tx pgx.Tx, teamId string, name string) error {
+	const op = "postgres.RenameTeam"
+
+	cmd, err := tx.Exec(ctx, `UPDATE teams SET name = $2 WHERE id = $1`, teamId, name)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return fmt.Errorf("%s: %w", op, ErrTeamAlredyExists)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrTeamNotFound)
+	}
+
+	return nil
+}
+
+// CountTeamActivePRs блокирует строку команды и возвращает число её PR'ов в статусах OPEN и DRAFT.
+// Блокировка не даёт создать PR команды до конца транзакции
+func (s *Storage) CountTeamActivePRs(ctx context.Context, tx pgx.Tx, teamId string) (int, error) {
+	const op = "postgres.CountTeamActivePRs"
+
+	var locked string
+	err := tx.QueryRow(ctx, `SELECT id FROM teams WHERE id = $1 FOR UPDATE`, teamId).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, ErrTeamNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var count int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM pull_requests pr
+		JOIN statuses s ON s.id = pr.status_id
+		WHERE pr.team_id = $1 AND s.name IN ('OPEN', 'DRAFT')
+	`, teamId).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// DeleteTeam удаляет команду вместе с её правилами ревьюверов, владением путями и резервными командами.
+// Участники исключаются из команды, у кого она была основной, основной команды не остаётся. PR'ы команды остаются без команды
+func (s *Storage) DeleteTeam(ctx context.Context, tx pgx.Tx, teamId string) error {
+	const op = "postgres.DeleteTeam"
+
+	cmd, err := tx.Exec(ctx, `DELETE FROM teams WHERE id = $1`, teamId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, ErrTeamNotFound)
+	}
+
+	return nil
+}
+
+// TransferTeamRules передаёт команде toTeamId правила ревьюверов, владение путями и резервные команды команды fromTeamId.
+// Ссылки других команд на fromTeamId как на резервную заменяются на toTeamId. Дубликаты того, что у toTeamId уже есть,
+// не переносятся и удаляются вместе с fromTeamId
+func (s *Storage) TransferTeamRules(ctx context.Context, tx pgx.Tx, fromTeamId string, toTeamId string) error {
+	const op = "postgres.TransferTeamRules"
+
+	_, err := tx.Exec(ctx, `
+		UPDATE reviewer_rules r SET team_id = $2
+		WHERE r.team_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM reviewer_rules x
+			WHERE x.team_id = $2 AND x.author_id = r.author_id AND x.reviewer_id = r.reviewer_id
+		)
+	`, fromTeamId, toTeamId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE ownership_rule_owners o SET team_id = $2
+		WHERE o.team_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM ownership_rule_owners x WHERE x.rule_id = o.rule_id AND x.team_id = $2
+		)
+	`, fromTeamId, toTeamId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// резервные команды fromTeamId добавляются в конец списка toTeamId в прежнем порядке
+	_, err = tx.Exec(ctx, `
+		INSERT INTO team_fallbacks (team_id, fallback_team_id, position)
+		SELECT $2, tf.fallback_team_id,
+			(SELECT COALESCE(MAX(position), -1) + 1 FROM team_fallbacks WHERE team_id = $2) + tf.position
+		FROM team_fallbacks tf
+		WHERE tf.team_id = $1 AND tf.fallback_team_id <> $2
+		ON CONFLICT DO NOTHING
+	`, fromTeamId, toTeamId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE team_fallbacks tf SET fallback_team_id = $2
+		WHERE tf.fallback_team_id = $1 AND tf.team_id <> $2 AND NOT EXISTS (
+			SELECT 1 FROM team_fallbacks x WHERE x.team_id = tf.team_id AND x.fallback_team_id = $2
+		)
+	`, fromTeamId, toTeamId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"pr-review/internal/http/dto"
 	"pr-review/internal/models"
 
 	"github.com/jackc/pgx/v5"
@@ -14,14 +13,28 @@ import (
 var (
 	ErrUserNotFound = errors.New("user not found")
 	ErrUserExists   = errors.New("user exists with username")
+	// ErrUserOffboarded уволенного пользователя нельзя активировать и добавить в команду
+	ErrUserOffboarded = errors.New("user is offboarded")
 )
 
+// activeExpr активность пользователя u с учётом увольнения и периодов отсутствия: флаг is_active, пользователь не уволен
+// и нет ни одного текущего отсутствия
+const activeExpr = `(u.is_active AND u.offboarded_at IS NULL AND NOT EXISTS (
+	SELECT 1 FROM absences a WHERE a.user_id = u.id AND a.starts_at <= now() AND a.ends_at > now()
+))`
+
+// userTeamsExpr названия всех команд пользователя u по алфавиту
+const userTeamsExpr = `ARRAY(
+	SELECT t2.name FROM team_members tm JOIN teams t2 ON tm.team_id = t2.id WHERE tm.user_id = u.id ORDER BY t2.name
+)`
+
 func (s *Storage) GetTeamMembers(ctx context.Context, name string) ([]*models.Member, error) {
 	const op = "postgres.GetTeamMembers"
 
-	rows, err := s.db.Query(ctx, `SELECT id, username, is_active
-	FROM users
-	WHERE team_id = (
+	rows, err := s.db.Query(ctx, `SELECT u.id, u.username, `+activeExpr+`, u.tags, u.max_open_reviews
+	FROM team_members tm
+	JOIN users u ON tm.user_id = u.id
+	WHERE tm.team_id = (
 		SELECT id FROM teams WHERE name = $1
 	)`, name)
 	if err != nil {
@@ -32,7 +45,7 @@ func (s *Storage) GetTeamMembers(ctx context.Context, name string) ([]*models.Me
 	users := make([]*models.Member, 0)
 	for rows.Next() {
 		var user models.Member
-		if err := rows.Scan(&user.Id, &user.Username, &user.IsActive); err != nil {
+		if err := rows.Scan(&user.Id, &user.Username, &user.IsActive, &user.Tags, &user.MaxOpenReviews); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 		users = append(users, &user)
@@ -44,25 +57,32 @@ func (s *Storage) GetTeamMembers(ctx context.Context, name string) ([]*models.Me
 	return users, nil
 }
 
+// AddOrUpdateTeamMembers добавляет пользователей в команду teamId, не исключая из других команд, и обновляет их данные.
+// Для пользователей без основной команды она становится основной. Уволенных пользователей добавить нельзя: ErrUserOffboarded
 func (s *Storage) AddOrUpdateTeamMembers(ctx context.Context, tx pgx.Tx, teamId string, members []*models.Member) error {
 	const op = "postgres.AddOrUpdateTeamMembers"
 
 	for _, member := range members {
-		var count int
-		err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE id = $1", member.Id).Scan(&count)
-		if err != nil {
+		var offboarded bool
+		err := tx.QueryRow(ctx, "SELECT offboarded_at IS NOT NULL FROM users WHERE id = $1 FOR UPDATE", member.Id).Scan(&offboarded)
+		exists := err == nil
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 			return fmt.Errorf("%s: %w", op, err)
 		}
-		if count == 0 {
+		if offboarded {
+			return fmt.Errorf("%w %s", ErrUserOffboarded, member.Id)
+		}
+		if !exists {
 			_, err = tx.Exec(ctx, `
-				INSERT INTO users (id, username, team_id, is_active)
-				VALUES ($1, $2, $3, $4)
-			`, member.Id, member.Username, teamId, member.IsActive)
+				INSERT INTO users (id, username, team_id, is_active, tags, max_open_reviews)
+				VALUES ($1, $2, $3, $4, COALESCE($5, '{}'), NULLIF($6::int, 0))
+			`, member.Id, member.Username, teamId, member.IsActive, member.Tags, member.MaxOpenReviews)
 		} else {
 			_, err = tx.Exec(ctx, `
-				UPDATE users SET username = $2, team_id = $3, is_active = $4
+				UPDATE users SET username = $2, team_id = COALESCE(team_id, $3), is_active = $4, tags = COALESCE($5, tags),
+					max_open_reviews = CASE WHEN $6::int IS NULL THEN max_open_reviews ELSE NULLIF($6::int, 0) END
 				WHERE id = $1
-			`, member.Id, member.Username, teamId, member.IsActive)
+			`, member.Id, member.Username, teamId, member.IsActive, member.Tags, member.MaxOpenReviews)
 		}
 		if err != nil {
 			if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
@@ -70,20 +90,177 @@ func (s *Storage) AddOrUpdateTeamMembers(ctx context.Context, tx pgx.Tx, teamId
 			}
 			return fmt.Errorf("%s: %w", op, err)
 		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO team_members (team_id, user_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, teamId, member.Id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
 	return nil
 }
 
-func (s *Storage) UserSetIsActive(ctx context.Context, reqDTO *dto.SetIsActiveRequest) error {
+// RemoveTeamMembers убирает пользователей userIds из команды teamId и возвращает id тех, кто в ней состоял.
+// Если команда была основной, у пользователя не остаётся основной команды
+func (s *Storage) RemoveTeamMembers(ctx context.Context, tx pgx.Tx, teamId string, userIds []string) ([]string, error) {
+	const op = "postgres.RemoveTeamMembers"
+
+	_, err := tx.Exec(ctx, `UPDATE users SET team_id = NULL WHERE team_id = $1 AND id = ANY($2)`, teamId, userIds)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		DELETE FROM team_members
+		WHERE team_id = $1 AND user_id = ANY($2)
+		RETURNING user_id
+	`, teamId, userIds)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	removed := make([]string, 0, len(userIds))
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		removed = append(removed, userId)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return removed, nil
+}
+
+// MoveTeamMembers переводит всех участников и PR'ы команды fromTeamId в команду toTeamId и возвращает id участников.
+// У кого fromTeamId была основной, основной становится toTeamId
+func (s *Storage) MoveTeamMembers(ctx context.Context, tx pgx.Tx, fromTeamId string, toTeamId string) ([]string, error) {
+	const op = "postgres.MoveTeamMembers"
+
+	_, err := tx.Exec(ctx, `UPDATE users SET team_id = $2 WHERE team_id = $1`, fromTeamId, toTeamId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE pull_requests SET team_id = $2 WHERE team_id = $1`, fromTeamId, toTeamId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO team_members (team_id, user_id)
+		SELECT $2, user_id FROM team_members WHERE team_id = $1
+		ON CONFLICT DO NOTHING
+	`, fromTeamId, toTeamId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		DELETE FROM team_members
+		WHERE team_id = $1
+		RETURNING user_id
+	`, fromTeamId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	moved := make([]string, 0)
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		moved = append(moved, userId)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return moved, nil
+}
+
+// DeactivateTeamMembers деактивирует участников команды, которые не состоят в других командах, и возвращает их id.
+// Из самой команды участники не исключаются
+func (s *Storage) DeactivateTeamMembers(ctx context.Context, tx pgx.Tx, teamId string) ([]string, error) {
+	const op = "postgres.DeactivateTeamMembers"
+
+	rows, err := tx.Query(ctx, `
+		UPDATE users u SET is_active = false
+		WHERE EXISTS (SELECT 1 FROM team_members tm WHERE tm.user_id = u.id AND tm.team_id = $1)
+		AND NOT EXISTS (SELECT 1 FROM team_members tm WHERE tm.user_id = u.id AND tm.team_id <> $1)
+		RETURNING u.id
+	`, teamId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deactivated := make([]string, 0)
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		deactivated = append(deactivated, userId)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deactivated, nil
+}
+
+// UserSetIsActive меняет флаг активности пользователя. Уволенного пользователя активировать нельзя: ErrUserOffboarded
+func (s *Storage) UserSetIsActive(ctx context.Context, tx pgx.Tx, userId string, isActive bool) error {
 	const op = "postgres.UserSetIsActive"
 
-	if reqDTO.IsActive == nil {
-		return fmt.Errorf("%s: isActive is nil", op)
+	cmd, err := s.conn(tx).Exec(ctx, `UPDATE users SET is_active = $1 WHERE id = $2 AND (NOT $1 OR offboarded_at IS NULL)`, isActive, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		var exists bool
+		err = s.conn(tx).QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`, userId).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if exists {
+			return ErrUserOffboarded
+		}
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UserSetTags заменяет теги пользователя
+func (s *Storage) UserSetTags(ctx context.Context, userId string, tags []string) error {
+	const op = "postgres.UserSetTags"
+
+	cmd, err := s.db.Exec(ctx, `UPDATE users SET tags = $1 WHERE id = $2`, tags, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrUserNotFound
 	}
-	isActive := *reqDTO.IsActive
 
-	cmd, err := s.db.Exec(ctx, `UPDATE users SET is_active = $1 WHERE id = $2`, isActive, reqDTO.UserId)
+	return nil
+}
+
+// UserSetMaxOpenReviews задаёт ограничение открытых ревью пользователя, 0 - снимает его
+func (s *Storage) UserSetMaxOpenReviews(ctx context.Context, userId string, limit int) error {
+	const op = "postgres.UserSetMaxOpenReviews"
+
+	cmd, err := s.db.Exec(ctx, `UPDATE users SET max_open_reviews = NULLIF($1::int, 0) WHERE id = $2`, limit, userId)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -94,11 +271,90 @@ func (s *Storage) UserSetIsActive(ctx context.Context, reqDTO *dto.SetIsActiveRe
 	return nil
 }
 
+// UserSetPrimaryTeam делает команду teamName основной для пользователя. Если он в ней не состоит, возвращается ErrUserNotFound
+func (s *Storage) UserSetPrimaryTeam(ctx context.Context, userId string, teamName string) error {
+	const op = "postgres.UserSetPrimaryTeam"
+
+	cmd, err := s.db.Exec(ctx, `
+		UPDATE users u SET team_id = tm.team_id
+		FROM team_members tm
+		JOIN teams t ON tm.team_id = t.id
+		WHERE u.id = $1 AND tm.user_id = u.id AND t.name = $2
+	`, userId, teamName)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// OffboardUser увольняет пользователя: деактивирует его и исключает из всех команд, строка пользователя остаётся.
+// Если пользователь не найден или уже уволен, возвращается ErrUserNotFound
+func (s *Storage) OffboardUser(ctx context.Context, tx pgx.Tx, userId string) error {
+	const op = "postgres.OffboardUser"
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE users SET is_active = false, team_id = NULL, offboarded_at = now()
+		WHERE id = $1 AND offboarded_at IS NULL
+	`, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM team_members WHERE user_id = $1`, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetReviewLimits возвращает ограничения открытых ревью пользователей: своё или, если не задано, основной команды.
+// Пользователи без ограничения в результат не попадают. Строки пользователей с ограничением блокируются до конца транзакции,
+// чтобы параллельные назначения не превысили ограничение
+func (s *Storage) GetReviewLimits(ctx context.Context, tx pgx.Tx, userIds []string) (map[string]int, error) {
+	const op = "postgres.GetReviewLimits"
+
+	rows, err := tx.Query(ctx, `
+		SELECT u.id, COALESCE(u.max_open_reviews, t.max_open_reviews)
+		FROM users u
+		LEFT JOIN teams t ON u.team_id = t.id
+		WHERE u.id = ANY($1) AND COALESCE(u.max_open_reviews, t.max_open_reviews) IS NOT NULL
+		ORDER BY u.id
+		FOR UPDATE OF u
+	`, userIds)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	limits := make(map[string]int, len(userIds))
+	for rows.Next() {
+		var userId string
+		var limit int
+		if err := rows.Scan(&userId, &limit); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		limits[userId] = limit
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return limits, nil
+}
+
 func (s *Storage) GetUserById(ctx context.Context, id string) (*models.User, error) {
 	const op = "postgres.GetUserById"
 
 	var user models.User
-	err := s.db.QueryRow(ctx, `SELECT u.id, u.username, t.name, u.is_active FROM users u JOIN teams t ON u.team_id = t.id WHERE u.id = $1`, id).Scan(&user.Id, &user.Username, &user.TeamName, &user.IsActive)
+	err := s.db.QueryRow(ctx, `SELECT u.id, u.username, COALESCE(t.name, ''), `+userTeamsExpr+`, `+activeExpr+`, u.tags, u.max_open_reviews, u.offboarded_at FROM users u LEFT JOIN teams t ON u.team_id = t.id WHERE u.id = $1`, id).Scan(&user.Id, &user.Username, &user.TeamName, &user.Teams, &user.IsActive, &user.Tags, &user.MaxOpenReviews, &user.OffboardedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrUserNotFound
@@ -109,6 +365,128 @@ func (s *Storage) GetUserById(ctx context.Context, id string) (*models.User, err
 	return &user, nil
 }
 
+// GetUserIdByUsername возвращает id пользователя по его username
+func (s *Storage) GetUserIdByUsername(ctx context.Context, tx pgx.Tx, username string) (string, error) {
+	const op = "postgres.GetUserIdByUsername"
+
+	var id string
+	err := s.conn(tx).QueryRow(ctx, `SELECT id FROM users WHERE username = $1`, username).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// GetUsersWithTeamByIds возвращает пользователей из userIds вместе с их основной и всеми командами в порядке id.
+// tx может быть nil, тогда запрос выполняется вне транзакции
+func (s *Storage) GetUsersWithTeamByIds(ctx context.Context, tx pgx.Tx, userIds []string) ([]*models.User, error) {
+	const op = "postgres.GetUsersWithTeamByIds"
+
+	rows, err := s.conn(tx).Query(ctx, `
+		SELECT u.id, u.username, COALESCE(t.name, ''), `+userTeamsExpr+`, `+activeExpr+`, u.tags, u.max_open_reviews, u.offboarded_at
+		FROM users u
+		LEFT JOIN teams t ON u.team_id = t.id
+		WHERE u.id = ANY($1)
+		ORDER BY u.id
+	`, userIds)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.Id, &user.Username, &user.TeamName, &user.Teams, &user.IsActive, &user.Tags, &user.MaxOpenReviews, &user.OffboardedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		users = append(users, &user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// GetUsersByIds возвращает пользователей из userIds в порядке id
+func (s *Storage) GetUsersByIds(ctx context.Context, tx pgx.Tx, userIds []string) ([]*models.Member, error) {
+	const op = "postgres.GetUsersByIds"
+
+	rows, err := tx.Query(ctx, `
+		SELECT u.id, u.username, `+activeExpr+`, u.tags, u.max_open_reviews
+		FROM users u
+		WHERE u.id = ANY($1)
+		ORDER BY u.id
+	`, userIds)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	members := make([]*models.Member, 0)
+	for rows.Next() {
+		var member models.Member
+		if err := rows.Scan(&member.Id, &member.Username, &member.IsActive, &member.Tags, &member.MaxOpenReviews); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		members = append(members, &member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// LockUsers блокирует строки пользователей до конца транзакции.
+// Блокировки берутся в порядке id, чтобы параллельные транзакции не приводили к дедлокам
+func (s *Storage) LockUsers(ctx context.Context, tx pgx.Tx, userIds []string) error {
+	const op = "postgres.LockUsers"
+
+	rows, err := tx.Query(ctx, `SELECT id FROM users WHERE id = ANY($1) ORDER BY id FOR UPDATE`, userIds)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetUsersIsActive блокирует строки пользователей из userIds до конца транзакции и возвращает их флаги активности.
+// Блокировки берутся в порядке id, отсутствующих пользователей в результате нет
+func (s *Storage) GetUsersIsActive(ctx context.Context, tx pgx.Tx, userIds []string) (map[string]bool, error) {
+	const op = "postgres.GetUsersIsActive"
+
+	rows, err := tx.Query(ctx, `SELECT id, is_active FROM users WHERE id = ANY($1) ORDER BY id FOR UPDATE`, userIds)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	flags := make(map[string]bool, len(userIds))
+	for rows.Next() {
+		var id string
+		var isActive bool
+		if err := rows.Scan(&id, &isActive); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		flags[id] = isActive
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return flags, nil
+}
+
 func (s *Storage) DeleteUsers(ctx context.Context) error {
 	const op = "postgres.DeleteUsers"
 
@@ -0,0 +1,205 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"pr-review/internal/repository/postgres"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAbsenceNotFound = errors.New("absence not found")
+)
+
+// absencesBatchSize сколько начавшихся периодов отсутствия обрабатывается в одной транзакции
+const absencesBatchSize = 100
+
+// AddAbsence добавляет пользователю период отсутствия. Если период уже начался,
+// открытые ревью пользователя переназначаются сразу, иначе - воркером при наступлении начала периода
+func (uc *Usecases) AddAbsence(ctx context.Context, reqDTO *dto.AddAbsenceRequest) (*models.Absence, error) {
+	const op = "usecases.AddAbsence"
+	log := uc.log.With(slog.String("op", op), slog.String("user_id", reqDTO.UserId))
+
+	absence := &models.Absence{
+		Id:       uuid.NewString(),
+		UserId:   reqDTO.UserId,
+		StartsAt: reqDTO.StartsAt,
+		EndsAt:   reqDTO.EndsAt,
+		Reason:   reqDTO.Reason,
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	started, err := uc.db.AddAbsence(ctx, tx, absence)
+	if err != nil {
+		if errors.Is(err, postgres.ErrUserNotFound) {
+			log.Warn("user not found")
+			err = ErrUserNotFound
+			return nil, err
+		}
+		log.Error("error adding absence", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if started {
+		_, err = uc.reassignReviews(ctx, tx, absence.UserId, models.TriggerAbsence)
+		if err != nil {
+			log.Error("error reassigning reviews of absent user", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	log.Debug("absence added successfully", slog.Bool("started", started))
+	return absence, nil
+}
+
+// GetAbsences возвращает текущие и будущие периоды отсутствия пользователя
+func (uc *Usecases) GetAbsences(ctx context.Context, userId string) ([]*models.Absence, error) {
+	const op = "usecases.GetAbsences"
+	log := uc.log.With(slog.String("op", op), slog.String("user_id", userId))
+
+	_, err := uc.db.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrUserNotFound) {
+			log.Warn("user not found")
+			return nil, ErrUserNotFound
+		}
+		log.Error("error getting user by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	absences, err := uc.db.GetAbsences(ctx, userId)
+	if err != nil {
+		log.Error("error getting absences", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Debug("absences got successfully", slog.Int("absences_count", len(absences)))
+	return absences, nil
+}
+
+// DeleteAbsence удаляет период отсутствия. Если период уже шёл, пользователь снова становится активным
+// и сразу назначается на PR'ы, которым не хватает ревьюверов, но снятые с него ревью обратно не возвращаются
+func (uc *Usecases) DeleteAbsence(ctx context.Context, id string) error {
+	const op = "usecases.DeleteAbsence"
+	log := uc.log.With(slog.String("op", op), slog.String("absence_id", id))
+
+	userId, err := uc.db.DeleteAbsence(ctx, id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrAbsenceNotFound) {
+			log.Warn("absence not found")
+			return ErrAbsenceNotFound
+		}
+		log.Error("error deleting absence", slog.String("error", err.Error()))
+		return err
+	}
+
+	// если период уже шёл, пользователь снова активен и может дозаполнить PR'ы
+	uc.backfillAfterActivation(ctx, []string{userId})
+
+	log.Debug("absence deleted successfully")
+	return nil
+}
+
+// RunAbsenceWorker раз в interval переназначает открытые ревью пользователей, у которых начался период отсутствия.
+// Работает до отмены ctx
+func (uc *Usecases) RunAbsenceWorker(ctx context.Context, interval time.Duration) {
+	const op = "usecases.RunAbsenceWorker"
+	log := uc.log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("absence worker stopped")
+			return
+		case <-ticker.C:
+			count, err := uc.ReassignAbsentReviews(ctx)
+			if err != nil {
+				log.Error("error reassigning reviews of absent users", slog.String("error", err.Error()))
+				continue
+			}
+			if count > 0 {
+				log.Info("reassigned reviews of absent users", slog.Int("absences_count", count))
+			}
+		}
+	}
+}
+
+// ReassignAbsentReviews обрабатывает все начавшиеся периоды отсутствия: снимает открытые ревью с отсутствующих
+// и подбирает замену. Периоды обрабатываются пачками, каждая в своей транзакции. Возвращает количество обработанных периодов
+func (uc *Usecases) ReassignAbsentReviews(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		count, err := uc.reassignAbsentReviewsBatch(ctx)
+		if err != nil {
+			return total, err
+		}
+		total += count
+		if count < absencesBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (uc *Usecases) reassignAbsentReviewsBatch(ctx context.Context) (int, error) {
+	const op = "usecases.reassignAbsentReviewsBatch"
+	log := uc.log.With(slog.String("op", op))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	absences, err := uc.db.ClaimStartedAbsences(ctx, tx, absencesBatchSize)
+	if err != nil {
+		log.Error("error claiming started absences", slog.String("error", err.Error()))
+		return 0, err
+	}
+
+	for _, absence := range absences {
+		_, err = uc.reassignReviews(ctx, tx, absence.UserId, models.TriggerAbsence)
+		if err != nil {
+			log.Error("error reassigning reviews of absent user", slog.String("user_id", absence.UserId), slog.String("error", err.Error()))
+			return 0, err
+		}
+	}
+
+	return len(absences), nil
+}
@@ -0,0 +1,701 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"pr-review/internal/models"
+	"pr-review/internal/repository/postgres"
+	"pr-review/internal/utils"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// assignment параметры подбора ревьюверов на PR
+type assignment struct {
+	// team команда автора PR'а
+	team *models.Team
+	prId string
+	// trigger операция, при которой подбираются ревьюверы
+	trigger models.DecisionTrigger
+	// count сколько ревьюверов нужно назначить
+	count int
+	// exclude пользователи, которых нельзя назначать (например, заменяемый ревьювер)
+	exclude []string
+	// skipEmpty не сохранять запись о подборе, если никто не назначен (периодическое дозаполнение)
+	skipEmpty bool
+	// requested явно запрошенные ревьюверы: назначаются только они, без стратегии, квоты и ограничения открытых ревью
+	requested []*models.Member
+
+	// поля ниже заполняет assignReviewers
+
+	authorId string
+	// assigned ревьюверы, уже назначенные на PR
+	assigned []string
+	// uncovered метки PR'а, которых ещё нет в тегах назначенных ревьюверов
+	uncovered []string
+	// rng источник случайности для стратегий, инициализирован decision.Seed
+	rng *rand.Rand
+	// decision запись о подборе, на каждую группу кандидатов в неё добавляется шаг
+	decision *models.AssignmentDecision
+	// capacityLimited хотя бы один кандидат пропущен из-за ограничения открытых ревью
+	capacityLimited bool
+	// rules правила команды для автора PR'а
+	rules []*models.ReviewerRule
+	// applied правила, которые запретили или потребовали назначить кандидата
+	applied []*models.ReviewerRule
+}
+
+// assignReviewers назначает на PR до a.count ревьюверов и сохраняет запись о том, как они были выбраны
+func (uc *Usecases) assignReviewers(ctx context.Context, tx pgx.Tx, a *assignment) ([]string, error) {
+	pr, err := uc.db.GetPRById(ctx, tx, a.prId)
+	if err != nil {
+		return nil, err
+	}
+	a.authorId = pr.AuthorId
+	a.assigned = slices.Clone(pr.Reviewers)
+
+	rules, err := uc.db.GetReviewerRules(ctx, tx, a.team.Id)
+	if err != nil {
+		return nil, err
+	}
+	a.rules = slices.DeleteFunc(rules, func(rule *models.ReviewerRule) bool {
+		return rule.AuthorId != a.authorId
+	})
+
+	a.uncovered, err = uc.db.GetUncoveredLabels(ctx, tx, a.prId)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := rand.Int63()
+	a.rng = rand.New(rand.NewSource(seed))
+	a.decision = &models.AssignmentDecision{
+		Id:      uuid.NewString(),
+		PrId:    a.prId,
+		Trigger: a.trigger,
+		Seed:    seed,
+		Steps:   make([]*models.DecisionStep, 0),
+	}
+
+	reviewers, err := uc.pickReviewers(ctx, tx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.skipEmpty && len(reviewers) == 0 {
+		return reviewers, nil
+	}
+
+	events := make([]*models.PREvent, 0, len(reviewers))
+	for _, reviewerId := range reviewers {
+		events = append(events, &models.PREvent{PrId: a.prId, Kind: models.EventAssigned, UserId: reviewerId})
+	}
+	err = uc.recordEvents(ctx, tx, a.trigger, events...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.db.AddAssignmentDecision(ctx, tx, a.decision)
+	if err != nil {
+		return nil, err
+	}
+
+	return reviewers, nil
+}
+
+// pickReviewers назначает сначала ревьюверов, обязательных по правилам команды, затем из владельцев изменённых файлов,
+// затем из команды автора, затем по порядку из её резервных команд.
+// Порядок кандидатов внутри каждой команды задаёт стратегия этой команды,
+// но первыми идут кандидаты, покрывающие ещё не покрытые метки PR'а
+func (uc *Usecases) pickReviewers(ctx context.Context, tx pgx.Tx, a *assignment) ([]string, error) {
+	if a.requested != nil {
+		return uc.assignRequested(ctx, tx, a)
+	}
+
+	reviewers, err := uc.assignIncluded(ctx, tx, a)
+	if err != nil {
+		return nil, err
+	}
+	if len(reviewers) >= a.count {
+		return reviewers, nil
+	}
+
+	ownerReviewers, err := uc.assignOwners(ctx, tx, a)
+	if err != nil {
+		return nil, err
+	}
+	reviewers = append(reviewers, ownerReviewers...)
+	if len(reviewers) >= a.count {
+		return reviewers, nil
+	}
+
+	members, err := uc.db.GetTeamMembersById(ctx, tx, a.team.Id)
+	if err != nil {
+		return nil, err
+	}
+	teamReviewers, err := uc.assignFromTeam(ctx, tx, a, a.team, members, a.count-len(reviewers), models.SourceTeam)
+	if err != nil {
+		return nil, err
+	}
+	reviewers = append(reviewers, teamReviewers...)
+	if len(reviewers) >= a.count {
+		return reviewers, nil
+	}
+
+	fallbackTeams, err := uc.db.GetFallbackTeams(ctx, tx, a.team.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, fallbackTeam := range fallbackTeams {
+		members, err := uc.db.GetTeamMembersById(ctx, tx, fallbackTeam.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		fallbackReviewers, err := uc.assignFromTeam(ctx, tx, a, fallbackTeam, members, a.count-len(reviewers), models.SourceFallback)
+		if err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, fallbackReviewers...)
+		if len(reviewers) >= a.count {
+			break
+		}
+	}
+
+	return reviewers, nil
+}
+
+// assignIncluded назначает ревьюверов, которых правила INCLUDE требуют добавлять на все PR'ы автора.
+// Они назначаются, даже если их больше a.count, и без учёта ограничения открытых ревью
+func (uc *Usecases) assignIncluded(ctx context.Context, tx pgx.Tx, a *assignment) ([]string, error) {
+	includedIds := make([]string, 0)
+	for _, rule := range a.rules {
+		if rule.Kind == models.RuleInclude {
+			includedIds = append(includedIds, rule.ReviewerId)
+		}
+	}
+	if len(includedIds) == 0 {
+		return nil, nil
+	}
+
+	members, err := uc.db.GetUsersByIds(ctx, tx, includedIds)
+	if err != nil {
+		return nil, err
+	}
+
+	step := &models.DecisionStep{
+		Source:     models.SourceRule,
+		Labels:     slices.Clone(a.uncovered),
+		Candidates: make([]string, 0, len(members)),
+		Excluded:   make([]*models.ExcludedCandidate, 0),
+		Assigned:   make([]string, 0, len(members)),
+	}
+	a.decision.Steps = append(a.decision.Steps, step)
+
+	reviewers := make([]string, 0, len(members))
+	for _, m := range members {
+		if excluded := a.excludedCandidate(m); excluded != nil {
+			step.Excluded = append(step.Excluded, excluded)
+			continue
+		}
+		step.Candidates = append(step.Candidates, m.Id)
+
+		reviewerId, err := uc.db.AssignPRToUser(ctx, tx, a.prId, []*models.Member{m}, models.SourceRule, "")
+		if err != nil {
+			return nil, err
+		}
+		if reviewerId == "" {
+			continue
+		}
+		reviewers = append(reviewers, reviewerId)
+		a.assigned = append(a.assigned, reviewerId)
+		a.uncovered = uncoveredLabels(a.uncovered, m.Tags)
+		a.apply(a.rule(m.Id, models.RuleInclude))
+		step.Assigned = append(step.Assigned, reviewerId)
+	}
+
+	return reviewers, nil
+}
+
+// assignRequested назначает явно запрошенных ревьюверов из любых команд. Уже назначенные пропускаются,
+// а автор, неактивный пользователь или запрещённый правилом команды ревьювер отклоняют весь запрос
+func (uc *Usecases) assignRequested(ctx context.Context, tx pgx.Tx, a *assignment) ([]string, error) {
+	step := &models.DecisionStep{
+		Source:     models.SourceRequested,
+		Labels:     slices.Clone(a.uncovered),
+		Candidates: memberIds(a.requested),
+		Excluded:   make([]*models.ExcludedCandidate, 0),
+		Assigned:   make([]string, 0, len(a.requested)),
+	}
+	a.decision.Steps = append(a.decision.Steps, step)
+
+	reviewers := make([]string, 0, len(a.requested))
+	for _, m := range a.requested {
+		switch a.exclusionReason(m) {
+		case models.ExclusionAuthor:
+			return nil, ErrReviewerIsAuthor
+		case models.ExclusionInactive:
+			return nil, ErrReviewerInactive
+		case models.ExclusionRule:
+			return nil, ErrReviewerExcludedByRule
+		case models.ExclusionAlreadyAssigned:
+			step.Excluded = append(step.Excluded, a.excludedCandidate(m))
+			continue
+		}
+
+		reviewerId, err := uc.db.AssignPRToUser(ctx, tx, a.prId, []*models.Member{m}, models.SourceRequested, "")
+		if err != nil {
+			return nil, err
+		}
+		if reviewerId == "" {
+			continue
+		}
+		reviewers = append(reviewers, reviewerId)
+		a.assigned = append(a.assigned, reviewerId)
+		a.uncovered = uncoveredLabels(a.uncovered, m.Tags)
+		step.Assigned = append(step.Assigned, reviewerId)
+	}
+
+	return reviewers, nil
+}
+
+// assignOwners назначает по одному ревьюверу на каждую группу владельцев изменённых в PR'е файлов.
+// Группы, владеющие большим количеством файлов, обрабатываются первыми
+func (uc *Usecases) assignOwners(ctx context.Context, tx pgx.Tx, a *assignment) ([]string, error) {
+	files, err := uc.db.GetPRFiles(ctx, tx, a.prId)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	rules, err := uc.db.GetOwnershipRules(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := make([]string, 0, a.count)
+	for _, rule := range ownersOfFiles(rules, files) {
+		if len(reviewers) >= a.count {
+			break
+		}
+
+		ownerReviewers, err := uc.assignRuleOwner(ctx, tx, a, rule)
+		if err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, ownerReviewers...)
+	}
+
+	return reviewers, nil
+}
+
+// assignRuleOwner назначает одного ревьювера из владельцев правила: сначала из команд-владельцев, затем из пользователей-владельцев.
+// Если кто-то из владельцев уже назначен на PR (например, при замене другого ревьювера), никто не назначается
+func (uc *Usecases) assignRuleOwner(ctx context.Context, tx pgx.Tx, a *assignment, rule *models.OwnershipRule) ([]string, error) {
+	isAssigned := func(id string) bool { return slices.Contains(a.assigned, id) }
+	if slices.ContainsFunc(rule.Users, isAssigned) {
+		return nil, nil
+	}
+
+	teams := make([]*models.Team, 0, len(rule.Teams))
+	teamsMembers := make([][]*models.Member, 0, len(rule.Teams))
+	for _, teamName := range rule.Teams {
+		team, err := uc.db.GetTeamByName(ctx, tx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		members, err := uc.db.GetTeamMembersById(ctx, tx, team.Id)
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(memberIds(members), isAssigned) {
+			return nil, nil
+		}
+		teams = append(teams, team)
+		teamsMembers = append(teamsMembers, members)
+	}
+
+	for i, team := range teams {
+		reviewers, err := uc.assignFromTeam(ctx, tx, a, team, teamsMembers[i], 1, models.SourceOwner)
+		if err != nil {
+			return nil, err
+		}
+		if len(reviewers) > 0 {
+			return reviewers, nil
+		}
+	}
+
+	if len(rule.Users) == 0 {
+		return nil, nil
+	}
+	members, err := uc.db.GetUsersByIds(ctx, tx, rule.Users)
+	if err != nil {
+		return nil, err
+	}
+	return uc.assignFromTeam(ctx, tx, a, nil, members, 1, models.SourceOwner)
+}
+
+// ownersOfFiles возвращает правила владения, которые применяются к файлам, в порядке убывания количества файлов.
+// К файлу применяется последнее подходящее правило, правила с одинаковыми владельцами объединяются
+func ownersOfFiles(rules []*models.OwnershipRule, files []string) []*models.OwnershipRule {
+	patterns := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		// некорректные шаблоны отсекаются при сохранении правил, здесь их просто пропускаем
+		patterns[i], _ = utils.CompileOwnersPattern(rule.Pattern)
+	}
+
+	filesCount := make(map[string]int)
+	owners := make([]*models.OwnershipRule, 0)
+	for _, file := range files {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if patterns[i] == nil || !patterns[i].MatchString(file) {
+				continue
+			}
+			if len(rules[i].Teams) == 0 && len(rules[i].Users) == 0 {
+				break
+			}
+
+			key := ownersKey(rules[i])
+			if filesCount[key] == 0 {
+				owners = append(owners, rules[i])
+			}
+			filesCount[key]++
+			break
+		}
+	}
+
+	sort.SliceStable(owners, func(i, j int) bool {
+		return filesCount[ownersKey(owners[i])] > filesCount[ownersKey(owners[j])]
+	})
+	return owners
+}
+
+// assignFromTeam назначает до count ревьюверов из участников members команды team и записывает шаг в a.decision.
+// Если team == nil, кандидаты не относятся к одной команде и перемешиваются случайно
+func (uc *Usecases) assignFromTeam(ctx context.Context, tx pgx.Tx, a *assignment, team *models.Team, members []*models.Member, count int, source models.AssignmentSource) ([]string, error) {
+	step := &models.DecisionStep{
+		Source:     source,
+		Strategy:   uc.strategy(team),
+		Labels:     slices.Clone(a.uncovered),
+		Candidates: make([]string, 0),
+		Excluded:   make([]*models.ExcludedCandidate, 0),
+		Assigned:   make([]string, 0),
+	}
+	if team != nil {
+		step.TeamName = team.Name
+	}
+	a.decision.Steps = append(a.decision.Steps, step)
+
+	candidates := make([]*models.Member, 0, len(members))
+	for _, m := range members {
+		if excluded := a.excludedCandidate(m); excluded != nil {
+			step.Excluded = append(step.Excluded, excluded)
+			continue
+		}
+		candidates = append(candidates, m)
+	}
+	candidates, err := uc.withinCapacity(ctx, tx, a, step, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceTeamId string
+	if source != models.SourceTeam && team != nil {
+		sourceTeamId = team.Id
+	}
+
+	selector := uc.selectors[step.Strategy]
+	ordered, err := selector.Order(ctx, tx, a.rng, team, candidates)
+	if err != nil {
+		return nil, err
+	}
+	ordered = coverLabels(ordered, a.uncovered)
+	step.Candidates = memberIds(ordered)
+
+	reviewers := make([]string, 0, count)
+	for range count {
+		reviewerId, err := uc.db.AssignPRToUser(ctx, tx, a.prId, ordered, source, sourceTeamId)
+		if err != nil {
+			return nil, err
+		}
+		if reviewerId == "" {
+			break
+		}
+		reviewers = append(reviewers, reviewerId)
+		a.assigned = append(a.assigned, reviewerId)
+		step.Assigned = append(step.Assigned, reviewerId)
+
+		idx := slices.IndexFunc(ordered, func(m *models.Member) bool {
+			return m.Id == reviewerId
+		})
+		a.uncovered = uncoveredLabels(a.uncovered, ordered[idx].Tags)
+
+		if err := selector.Assigned(ctx, tx, team, reviewerId); err != nil {
+			return nil, err
+		}
+	}
+
+	return reviewers, nil
+}
+
+// exclusionReason возвращает причину, по которой участник не может быть назначен, или пустую строку
+func (a *assignment) exclusionReason(m *models.Member) models.ExclusionReason {
+	switch {
+	case m.Id == a.authorId:
+		return models.ExclusionAuthor
+	case slices.Contains(a.exclude, m.Id):
+		return models.ExclusionReplaced
+	case !m.IsActive:
+		return models.ExclusionInactive
+	case slices.Contains(a.assigned, m.Id):
+		return models.ExclusionAlreadyAssigned
+	case a.rule(m.Id, models.RuleExclude) != nil:
+		return models.ExclusionRule
+	}
+	return ""
+}
+
+// excludedCandidate возвращает запись об исключении участника из кандидатов или nil, если его можно назначить.
+// Сработавшее запрещающее правило запоминается в a.applied
+func (a *assignment) excludedCandidate(m *models.Member) *models.ExcludedCandidate {
+	reason := a.exclusionReason(m)
+	if reason == "" {
+		return nil
+	}
+
+	excluded := &models.ExcludedCandidate{
+		UserId: m.Id,
+		Reason: reason,
+	}
+	if reason == models.ExclusionRule {
+		rule := a.rule(m.Id, models.RuleExclude)
+		excluded.RuleId = rule.Id
+		a.apply(rule)
+	}
+	return excluded
+}
+
+// rule возвращает правило вида kind для ревьювера reviewerId или nil
+func (a *assignment) rule(reviewerId string, kind models.ReviewerRuleKind) *models.ReviewerRule {
+	for _, rule := range a.rules {
+		if rule.ReviewerId == reviewerId && rule.Kind == kind {
+			return rule
+		}
+	}
+	return nil
+}
+
+// apply запоминает сработавшее правило, каждое правило - один раз
+func (a *assignment) apply(rule *models.ReviewerRule) {
+	if !slices.Contains(a.applied, rule) {
+		a.applied = append(a.applied, rule)
+	}
+}
+
+// assignOpenedPR назначает ревьюверов до квоты команды автора на PR, который стал открытым:
+// создан, готов к ревью после черновика или переоткрыт. Уже назначенные ревьюверы входят в квоту.
+// Если автор не состоит в команде, ревьюверы не назначаются
+func (uc *Usecases) assignOpenedPR(ctx context.Context, tx pgx.Tx, prId string, trigger models.DecisionTrigger) (*assignment, error) {
+	team, err := uc.db.GetTeamByPRId(ctx, tx, prId)
+	if errors.Is(err, postgres.ErrTeamNotFound) {
+		return &assignment{prId: prId, trigger: trigger}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	pr, err := uc.db.GetPRById(ctx, tx, prId)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &assignment{
+		team:    team,
+		prId:    prId,
+		trigger: trigger,
+		count:   max(team.ReviewersPerPR-len(pr.Reviewers), 0),
+	}
+	_, err = uc.assignReviewers(ctx, tx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.refreshNeedMoreReviewers(ctx, tx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// reassignReviews снимает с пользователя все открытые ревью и подбирает на каждый PR замену по стратегии команды PR'а.
+// Если замена не нашлась или ревьюверов меньше квоты команды, у PR'а выставляется need_more_reviewers.
+// На PR'ы без команды замена не подбирается. Возвращает снятые ревью с заменами
+func (uc *Usecases) reassignReviews(ctx context.Context, tx pgx.Tx, userId string, trigger models.DecisionTrigger) ([]*models.ReviewReassignment, error) {
+	prIds, err := uc.db.UnassignPRsFromUser(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.replaceReviewer(ctx, tx, userId, prIds, trigger)
+}
+
+// reassignTeamReviews как reassignReviews, но снимает пользователя только с ревью, связанных с командой teamId
+func (uc *Usecases) reassignTeamReviews(ctx context.Context, tx pgx.Tx, userId string, teamId string, trigger models.DecisionTrigger) ([]*models.ReviewReassignment, error) {
+	prIds, err := uc.db.UnassignTeamPRsFromUser(ctx, tx, userId, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.replaceReviewer(ctx, tx, userId, prIds, trigger)
+}
+
+// replaceReviewer подбирает замену снятому с PR'ов prIds пользователю userId и возвращает, кого назначили на каждый PR
+func (uc *Usecases) replaceReviewer(ctx context.Context, tx pgx.Tx, userId string, prIds []string, trigger models.DecisionTrigger) ([]*models.ReviewReassignment, error) {
+	reassignments := make([]*models.ReviewReassignment, 0, len(prIds))
+	for _, prId := range prIds {
+		err := uc.recordUnassigned(ctx, tx, trigger, prId, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		reassignment := &models.ReviewReassignment{PrId: prId, ReplacedBy: []string{}}
+		reassignments = append(reassignments, reassignment)
+
+		pr, err := uc.db.GetPRById(ctx, tx, prId)
+		if err != nil {
+			return nil, err
+		}
+		if pr.Status != models.StatusOpen {
+			// с черновика ревьювер только снимается, ревьюверы подберутся, когда он станет готов к ревью
+			continue
+		}
+
+		team, err := uc.db.GetTeamByPRId(ctx, tx, prId)
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			// у PR'а нет команды, замену подобрать не из кого
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		a := &assignment{
+			team:    team,
+			prId:    prId,
+			trigger: trigger,
+			count:   1,
+			exclude: []string{userId},
+		}
+		assigned, err := uc.assignReviewers(ctx, tx, a)
+		if err != nil {
+			return nil, err
+		}
+		reassignment.ReplacedBy = append(reassignment.ReplacedBy, assigned...)
+
+		err = uc.refreshNeedMoreReviewers(ctx, tx, a)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return reassignments, nil
+}
+
+// refreshNeedMoreReviewers выставляет need_more_reviewers после подбора a, если ревьюверов у PR'а меньше квоты команды.
+// Если при подборе кого-то пропустили из-за ограничения открытых ревью, причина - CAPACITY_EXCEEDED
+func (uc *Usecases) refreshNeedMoreReviewers(ctx context.Context, tx pgx.Tx, a *assignment) error {
+	pr, err := uc.db.GetPRById(ctx, tx, a.prId)
+	if err != nil {
+		return err
+	}
+
+	var reason models.NeedMoreReviewersReason
+	if len(pr.Reviewers) < a.team.ReviewersPerPR {
+		reason = models.ReasonNoCandidates
+		if a.capacityLimited {
+			reason = models.ReasonCapacityExceeded
+		}
+	}
+	return uc.setNeedMoreReviewers(ctx, tx, a.trigger, a.prId, pr.NeedMoreReviewersReason, reason)
+}
+
+// withinCapacity убирает из кандидатов тех, у кого открытых ревью уже не меньше их ограничения, и записывает их в step
+func (uc *Usecases) withinCapacity(ctx context.Context, tx pgx.Tx, a *assignment, step *models.DecisionStep, candidates []*models.Member) ([]*models.Member, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	ids := memberIds(candidates)
+	limits, err := uc.db.GetReviewLimits(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(limits) == 0 {
+		return candidates, nil
+	}
+
+	loads, err := uc.db.GetOpenReviewsCount(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(candidates, func(m *models.Member) bool {
+		limit, ok := limits[m.Id]
+		if !ok || loads[m.Id] < limit {
+			return false
+		}
+		step.Excluded = append(step.Excluded, &models.ExcludedCandidate{
+			UserId: m.Id,
+			Reason: models.ExclusionAtCapacity,
+		})
+		a.capacityLimited = true
+		return true
+	}), nil
+}
+
+// coverLabels переупорядочивает кандидатов так, чтобы первыми шли покрывающие метки labels:
+// на каждом шаге берётся кандидат, покрывающий больше всего ещё не покрытых меток, при равенстве - более ранний.
+// Остальные кандидаты идут следом в исходном порядке
+func coverLabels(candidates []*models.Member, labels []string) []*models.Member {
+	if len(labels) == 0 {
+		return candidates
+	}
+
+	rest := slices.Clone(candidates)
+	ordered := make([]*models.Member, 0, len(candidates))
+	for len(labels) > 0 {
+		best, bestCovered := -1, 0
+		for i, c := range rest {
+			if covered := len(labels) - len(uncoveredLabels(labels, c.Tags)); covered > bestCovered {
+				best, bestCovered = i, covered
+			}
+		}
+		if best < 0 {
+			break
+		}
+		ordered = append(ordered, rest[best])
+		labels = uncoveredLabels(labels, rest[best].Tags)
+		rest = slices.Delete(rest, best, best+1)
+	}
+
+	return append(ordered, rest...)
+}
+
+// uncoveredLabels возвращает метки из labels, которых нет в tags
+func uncoveredLabels(labels []string, tags []string) []string {
+	return slices.DeleteFunc(slices.Clone(labels), func(label string) bool {
+		return slices.Contains(tags, label)
+	})
+}
+
+func ownersKey(rule *models.OwnershipRule) string {
+	return strings.Join(rule.Teams, ",") + "|" + strings.Join(rule.Users, ",")
+}
@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"pr-review/internal/models"
+	"pr-review/internal/repository/postgres"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// backfillBatchSize сколько PR'ов дозаполняется в одной транзакции
+const backfillBatchSize = 100
+
+// RunBackfillWorker раз в interval дозаполняет ревьюверами открытые PR'ы, которым их не хватает до квоты команды.
+// Работает до отмены ctx
+func (uc *Usecases) RunBackfillWorker(ctx context.Context, interval time.Duration) {
+	const op = "usecases.RunBackfillWorker"
+	log := uc.log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("backfill worker stopped")
+			return
+		case <-ticker.C:
+			count, err := uc.BackfillReviewers(ctx)
+			if err != nil {
+				log.Error("error backfilling reviewers", slog.String("error", err.Error()))
+				continue
+			}
+			if count > 0 {
+				log.Info("backfilled reviewers", slog.Int("prs_count", count))
+			}
+		}
+	}
+}
+
+// BackfillReviewers подбирает недостающих до квоты команды ревьюверов всем открытым PR'ам с need_more_reviewers
+// по обычным правилам подбора. PR'ы перебираются по id пачками, каждая в своей транзакции,
+// поэтому PR'ы, которые дозаполнить не удалось, не обрабатываются повторно за один проход.
+// Возвращает количество PR'ов, которым назначен хотя бы один ревьювер
+func (uc *Usecases) BackfillReviewers(ctx context.Context) (int, error) {
+	return uc.backfill(ctx, nil)
+}
+
+// backfill как BackfillReviewers, но если передан userIds - только PR'ы, на которые можно подобрать этих пользователей
+func (uc *Usecases) backfill(ctx context.Context, userIds []string) (int, error) {
+	total := 0
+	afterId := uuid.Nil.String()
+	for {
+		prIds, filled, err := uc.backfillBatch(ctx, afterId, userIds)
+		if err != nil {
+			return total, err
+		}
+		total += filled
+		if len(prIds) < backfillBatchSize {
+			return total, nil
+		}
+		afterId = prIds[len(prIds)-1]
+	}
+}
+
+func (uc *Usecases) backfillBatch(ctx context.Context, afterId string, userIds []string) ([]string, int, error) {
+	const op = "usecases.backfillBatch"
+	log := uc.log.With(slog.String("op", op))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	prIds, err := uc.db.ClaimUnderstaffedPRs(ctx, tx, afterId, backfillBatchSize, userIds)
+	if err != nil {
+		log.Error("error claiming understaffed PRs", slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+
+	filled := 0
+	for _, prId := range prIds {
+		var assigned bool
+		assigned, err = uc.backfillPR(ctx, tx, prId)
+		if err != nil {
+			log.Error("error backfilling PR", slog.String("pr_id", prId), slog.String("error", err.Error()))
+			return nil, 0, err
+		}
+		if assigned {
+			filled++
+		}
+	}
+
+	return prIds, filled, nil
+}
+
+// backfillPR назначает на PR недостающих до квоты команды ревьюверов и пересчитывает need_more_reviewers
+func (uc *Usecases) backfillPR(ctx context.Context, tx pgx.Tx, prId string) (bool, error) {
+	team, err := uc.db.GetTeamByPRId(ctx, tx, prId)
+	if errors.Is(err, postgres.ErrTeamNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	pr, err := uc.db.GetPRById(ctx, tx, prId)
+	if err != nil {
+		return false, err
+	}
+
+	a := &assignment{
+		team:      team,
+		prId:      prId,
+		trigger:   models.TriggerBackfill,
+		count:     max(team.ReviewersPerPR-len(pr.Reviewers), 0),
+		skipEmpty: true,
+	}
+	var reviewers []string
+	if a.count > 0 {
+		reviewers, err = uc.assignReviewers(ctx, tx, a)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = uc.refreshNeedMoreReviewers(ctx, tx, a)
+	if err != nil {
+		return false, err
+	}
+
+	return len(reviewers) > 0, nil
+}
+
+// backfillAfterActivation сразу дозаполняет PR'ы, на которые можно подобрать активированных пользователей userIds:
+// PR'ы их команд и команд, для которых их команды резервные. Остальные PR'ы дозаполнит воркер.
+// Не прерывается при отмене ctx запроса, ошибка только логируется: пользователи уже активированы
+func (uc *Usecases) backfillAfterActivation(ctx context.Context, userIds []string) {
+	const op = "usecases.backfillAfterActivation"
+	log := uc.log.With(slog.String("op", op))
+
+	count, err := uc.backfill(context.WithoutCancel(ctx), userIds)
+	if err != nil {
+		log.Error("error backfilling reviewers", slog.String("error", err.Error()))
+		return
+	}
+	log.Debug("backfilled reviewers after activation", slog.Int("prs_count", count))
+}
@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"pr-review/internal/models"
+	"pr-review/internal/repository/postgres"
+	"pr-review/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetPRHistory возвращает историю PR'а в порядке событий
+func (uc *Usecases) GetPRHistory(ctx context.Context, prId string) ([]*models.PREvent, error) {
+	const op = "usecases.GetPRHistory"
+	log := uc.log.With(slog.String("op", op), slog.String("pr_id", prId))
+
+	_, err := uc.db.GetPRById(ctx, nil, prId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return nil, ErrPRNotFound
+		}
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	events, err := uc.db.GetPREvents(ctx, prId)
+	if err != nil {
+		log.Error("error getting PR events", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Debug("PR history got successfully", slog.Int("events_count", len(events)))
+	return events, nil
+}
+
+// recordEvents сохраняет события истории PR'ов с операцией reason, выполняющим операцию считается actor из контекста
+func (uc *Usecases) recordEvents(ctx context.Context, tx pgx.Tx, reason models.DecisionTrigger, events ...*models.PREvent) error {
+	actor := utils.ActorFromContext(ctx)
+	for _, event := range events {
+		event.Reason = reason
+		event.Actor = actor
+	}
+	return uc.db.AddPREvents(ctx, tx, events)
+}
+
+// recordUnassigned сохраняет снятие ревьюверов userIds с PR'а prId
+func (uc *Usecases) recordUnassigned(ctx context.Context, tx pgx.Tx, reason models.DecisionTrigger, prId string, userIds ...string) error {
+	events := make([]*models.PREvent, 0, len(userIds))
+	for _, userId := range userIds {
+		events = append(events, &models.PREvent{PrId: prId, Kind: models.EventUnassigned, UserId: userId})
+	}
+	return uc.recordEvents(ctx, tx, reason, events...)
+}
+
+// recordStatusChanged сохраняет смену статуса PR'а prId
+func (uc *Usecases) recordStatusChanged(ctx context.Context, tx pgx.Tx, reason models.DecisionTrigger, prId string, from, to models.Status) error {
+	return uc.recordEvents(ctx, tx, reason, &models.PREvent{
+		PrId: prId,
+		Kind: models.EventStatusChanged,
+		From: string(from),
+		To:   string(to),
+	})
+}
+
+// setNeedMoreReviewers выставляет PR'у причину need_more_reviewers и, если она изменилась по сравнению с from, сохраняет событие
+func (uc *Usecases) setNeedMoreReviewers(ctx context.Context, tx pgx.Tx, reason models.DecisionTrigger, prId string, from, to models.NeedMoreReviewersReason) error {
+	err := uc.db.SetNeedMoreReviewers(ctx, tx, prId, to)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return nil
+	}
+	return uc.recordEvents(ctx, tx, reason, &models.PREvent{
+		PrId: prId,
+		Kind: models.EventNeedMoreReviewersChanged,
+		From: string(from),
+		To:   string(to),
+	})
+}
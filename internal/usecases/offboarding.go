@@ -0,0 +1,189 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrUserOffboarded         = errors.New("user is offboarded")
+	ErrTransferTargetNotFound = errors.New("user to transfer PRs to not found")
+	ErrTransferTargetInactive = errors.New("user to transfer PRs to is inactive")
+)
+
+// OffboardUser увольняет пользователя в одной транзакции: деактивирует его и исключает из всех команд,
+// переназначает его открытые ревью, а к его открытым PR'ам применяет политику: оставляет (KEEP), закрывает (CLOSE)
+// или передаёт пользователю transfer_to (TRANSFER). Строка пользователя, его решения и назначения в смерженных PR'ах
+// остаются для истории и статистики
+func (uc *Usecases) OffboardUser(ctx context.Context, reqDTO *dto.OffboardUserRequest) (*models.Offboarding, error) {
+	res, err := uc.offboardUser(ctx, reqDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	res.User, err = uc.db.GetUserById(ctx, reqDTO.UserId)
+	if err != nil {
+		uc.log.Error("error getting user by id", slog.String("op", "usecases.OffboardUser"), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (uc *Usecases) offboardUser(ctx context.Context, reqDTO *dto.OffboardUserRequest) (res *models.Offboarding, err error) {
+	const op = "usecases.OffboardUser"
+	policy := reqDTO.Policy
+	if policy == "" {
+		policy = models.OffboardKeep
+	}
+	log := uc.log.With(slog.String("op", op), slog.String("user_id", reqDTO.UserId), slog.String("policy", string(policy)))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		} else {
+			log.Debug("user offboarded successfully", slog.Int("reassigned", len(res.ReassignedPRs)), slog.Int("authored", len(res.AuthoredPRs)))
+		}
+	}()
+
+	// увольняемый и получатель его PR'ов блокируются, чтобы их не уволили и не деактивировали параллельно
+	userIds := []string{reqDTO.UserId}
+	if policy == models.OffboardTransfer {
+		userIds = append(userIds, reqDTO.TransferTo)
+	}
+	err = uc.db.LockUsers(ctx, tx, userIds)
+	if err != nil {
+		log.Error("error locking users", slog.String("error", err.Error()))
+		return nil, err
+	}
+	users, err := uc.db.GetUsersWithTeamByIds(ctx, tx, userIds)
+	if err != nil {
+		log.Error("error getting users by ids", slog.String("error", err.Error()))
+		return nil, err
+	}
+	var user, newAuthor *models.User
+	for _, u := range users {
+		switch u.Id {
+		case reqDTO.UserId:
+			user = u
+		case reqDTO.TransferTo:
+			newAuthor = u
+		}
+	}
+
+	if user == nil {
+		log.Warn("user not found")
+		err = ErrUserNotFound
+		return nil, err
+	}
+	if user.OffboardedAt != nil {
+		log.Warn("user is already offboarded")
+		err = ErrUserOffboarded
+		return nil, err
+	}
+	if policy == models.OffboardTransfer {
+		if newAuthor == nil {
+			log.Warn("user to transfer PRs to not found")
+			err = ErrTransferTargetNotFound
+			return nil, err
+		}
+		if newAuthor.OffboardedAt != nil {
+			log.Warn("user to transfer PRs to is offboarded")
+			err = ErrUserOffboarded
+			return nil, err
+		}
+		if !newAuthor.IsActive {
+			log.Warn("user to transfer PRs to is inactive")
+			err = ErrTransferTargetInactive
+			return nil, err
+		}
+	}
+
+	err = uc.db.OffboardUser(ctx, tx, reqDTO.UserId)
+	if err != nil {
+		log.Error("error offboarding user", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	res = &models.Offboarding{Policy: policy}
+	res.ReassignedPRs, err = uc.db.UnassignPRsFromUser(ctx, tx, reqDTO.UserId)
+	if err != nil {
+		log.Error("error unassigning reviews", slog.String("error", err.Error()))
+		return nil, err
+	}
+	_, err = uc.replaceReviewer(ctx, tx, reqDTO.UserId, res.ReassignedPRs, models.TriggerOffboarding)
+	if err != nil {
+		log.Error("error reassigning reviews of offboarded user", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	res.AuthoredPRs, err = uc.db.GetOpenPRIdsByAuthor(ctx, tx, reqDTO.UserId)
+	if err != nil {
+		log.Error("error getting authored PRs", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if policy == models.OffboardKeep {
+		return res, nil
+	}
+
+	for _, prId := range res.AuthoredPRs {
+		var pr *models.PullRequest
+		pr, err = uc.db.GetPRById(ctx, tx, prId)
+		if err != nil {
+			log.Error("error getting PR by id", slog.String("pr_id", prId), slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		switch policy {
+		case models.OffboardClose:
+			err = uc.closeOffboardedPR(ctx, tx, pr)
+			if err != nil {
+				log.Error("error closing authored PR", slog.String("pr_id", prId), slog.String("error", err.Error()))
+				return nil, err
+			}
+		case models.OffboardTransfer:
+			_, err = uc.changePRAuthor(ctx, tx, pr, &models.PullRequestShort{Id: pr.Id}, newAuthor, models.PolicyKeep)
+			if err != nil {
+				if errors.Is(err, ErrAuthorWithoutTeam) {
+					log.Warn("user to transfer PRs to has no primary team")
+					return nil, err
+				}
+				log.Error("error transferring authored PR", slog.String("pr_id", prId), slog.String("error", err.Error()))
+				return nil, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// closeOffboardedPR закрывает открытый PR pr уволенного автора и снимает с него ревьюверов
+func (uc *Usecases) closeOffboardedPR(ctx context.Context, tx pgx.Tx, pr *models.PullRequest) error {
+	err := uc.db.SetPRStatus(ctx, tx, pr.Id, models.StatusOpen, models.StatusClosed)
+	if err != nil {
+		return err
+	}
+	err = uc.recordStatusChanged(ctx, tx, models.TriggerOffboarding, pr.Id, models.StatusOpen, models.StatusClosed)
+	if err != nil {
+		return err
+	}
+
+	return uc.unassignClosedPR(ctx, tx, pr, models.TriggerOffboarding)
+}
@@ -0,0 +1,215 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"pr-review/internal/repository/postgres"
+	"pr-review/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrRuleNotFound      = errors.New("ownership rule not found")
+	ErrOwnerNotFound     = errors.New("owner not found")
+	ErrInvalidCodeowners = errors.New("invalid CODEOWNERS")
+)
+
+func (uc *Usecases) GetOwnershipRules(ctx context.Context) ([]*models.OwnershipRule, error) {
+	const op = "usecases.GetOwnershipRules"
+	log := uc.log.With(slog.String("op", op))
+
+	rules, err := uc.db.GetOwnershipRules(ctx, nil)
+	if err != nil {
+		log.Error("error getting ownership rules", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Debug("ownership rules got successfully", slog.Int("rules_count", len(rules)))
+	return rules, nil
+}
+
+func (uc *Usecases) AddOwnershipRule(ctx context.Context, reqDTO *dto.AddOwnershipRuleRequest) (*models.OwnershipRule, error) {
+	const op = "usecases.AddOwnershipRule"
+	log := uc.log.With(slog.String("op", op), slog.String("pattern", reqDTO.Pattern))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	rule := &models.OwnershipRule{
+		Id:      uuid.NewString(),
+		Pattern: reqDTO.Pattern,
+		Teams:   reqDTO.Teams,
+		Users:   reqDTO.Users,
+		TeamIds: make([]string, 0, len(reqDTO.Teams)),
+	}
+	for _, name := range reqDTO.Teams {
+		var team *models.Team
+		team, err = uc.db.GetTeamByName(ctx, tx, name)
+		if err != nil {
+			if errors.Is(err, postgres.ErrTeamNotFound) {
+				log.Warn("owner team not found", slog.String("team_name", name))
+				err = fmt.Errorf("%w: team %s", ErrOwnerNotFound, name)
+				return nil, err
+			}
+			log.Error("error getting team", slog.String("error", err.Error()))
+			return nil, err
+		}
+		rule.TeamIds = append(rule.TeamIds, team.Id)
+	}
+	for _, userId := range reqDTO.Users {
+		_, err = uc.db.GetUserById(ctx, userId)
+		if err != nil {
+			if errors.Is(err, postgres.ErrUserNotFound) {
+				log.Warn("owner user not found", slog.String("user_id", userId))
+				err = fmt.Errorf("%w: user %s", ErrOwnerNotFound, userId)
+				return nil, err
+			}
+			log.Error("error getting user by id", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	err = uc.db.AddOwnershipRule(ctx, tx, rule)
+	if err != nil {
+		log.Error("error adding ownership rule", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Debug("ownership rule added successfully")
+	return rule, nil
+}
+
+func (uc *Usecases) DeleteOwnershipRule(ctx context.Context, id string) error {
+	const op = "usecases.DeleteOwnershipRule"
+	log := uc.log.With(slog.String("op", op), slog.String("rule_id", id))
+
+	err := uc.db.DeleteOwnershipRule(ctx, id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrRuleNotFound) {
+			log.Warn("ownership rule not found")
+			return ErrRuleNotFound
+		}
+		log.Error("error deleting ownership rule", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Debug("ownership rule deleted successfully")
+	return nil
+}
+
+// ImportCodeowners заменяет все правила владения правилами из файла в формате CODEOWNERS.
+// Владелец @name ищется сначала среди команд, затем среди username пользователей
+func (uc *Usecases) ImportCodeowners(ctx context.Context, reqDTO *dto.ImportCodeownersRequest) ([]*models.OwnershipRule, error) {
+	const op = "usecases.ImportCodeowners"
+	log := uc.log.With(slog.String("op", op))
+
+	lines, err := utils.ParseCodeowners(reqDTO.Content)
+	if err != nil {
+		log.Warn("invalid CODEOWNERS", slog.String("error", err.Error()))
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCodeowners, err.Error())
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	err = uc.db.DeleteOwnershipRules(ctx, tx)
+	if err != nil {
+		log.Error("error deleting ownership rules", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	for _, line := range lines {
+		rule := &models.OwnershipRule{
+			Id:      uuid.NewString(),
+			Pattern: line.Pattern,
+			Teams:   make([]string, 0),
+			Users:   make([]string, 0),
+			TeamIds: make([]string, 0),
+		}
+		for _, owner := range line.Owners {
+			err = uc.resolveOwner(ctx, tx, rule, owner)
+			if err != nil {
+				if errors.Is(err, ErrOwnerNotFound) {
+					log.Warn("owner not found", slog.String("owner", owner))
+				} else {
+					log.Error("error resolving owner", slog.String("error", err.Error()))
+				}
+				return nil, err
+			}
+		}
+
+		err = uc.db.AddOwnershipRule(ctx, tx, rule)
+		if err != nil {
+			log.Error("error adding ownership rule", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	rules, err := uc.db.GetOwnershipRules(ctx, tx)
+	if err != nil {
+		log.Error("error getting ownership rules", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Debug("CODEOWNERS imported successfully", slog.Int("rules_count", len(rules)))
+	return rules, nil
+}
+
+// resolveOwner добавляет владельца в правило как команду, если команда с таким именем есть, иначе как пользователя
+func (uc *Usecases) resolveOwner(ctx context.Context, tx pgx.Tx, rule *models.OwnershipRule, owner string) error {
+	team, err := uc.db.GetTeamByName(ctx, tx, owner)
+	if err == nil {
+		rule.Teams = append(rule.Teams, team.Name)
+		rule.TeamIds = append(rule.TeamIds, team.Id)
+		return nil
+	}
+	if !errors.Is(err, postgres.ErrTeamNotFound) {
+		return err
+	}
+
+	userId, err := uc.db.GetUserIdByUsername(ctx, tx, owner)
+	if err != nil {
+		if errors.Is(err, postgres.ErrUserNotFound) {
+			return fmt.Errorf("%w: @%s", ErrOwnerNotFound, owner)
+		}
+		return err
+	}
+	rule.Users = append(rule.Users, userId)
+	return nil
+}
@@ -3,23 +3,40 @@ package usecases
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"pr-review/internal/http/dto"
 	"pr-review/internal/models"
 	"pr-review/internal/repository/postgres"
 	"pr-review/internal/utils"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 var (
-	ErrPRNotFound           = errors.New("resource not found")
-	ErrPRAlreadyExists      = errors.New("PR id already exists")
-	ErrNoCandidatesToAssign = errors.New("no active replacement candidate in team")
-	ErrPRMerged             = errors.New("cannot reassign on merged PR")
-	ErrUserNotReviewerOfPR  = errors.New("reviewer is not assigned to this PR")
+	ErrPRNotFound               = errors.New("resource not found")
+	ErrPRAlreadyExists          = errors.New("PR id already exists")
+	ErrNoCandidatesToAssign     = errors.New("no active replacement candidate in team")
+	ErrPRMerged                 = errors.New("cannot reassign on merged PR")
+	ErrUserNotReviewerOfPR      = errors.New("reviewer is not assigned to this PR")
+	ErrReviewMergedPR           = errors.New("cannot review merged PR")
+	ErrReviewClosedPR           = errors.New("cannot review closed PR")
+	ErrReviewDraftPR            = errors.New("cannot review draft PR")
+	ErrNotEnoughApprovals       = errors.New("not enough approvals to merge PR")
+	ErrIllegalTransition        = errors.New("illegal PR status transition")
+	ErrChangeAuthorMergedPR     = errors.New("cannot change author of merged PR")
+	ErrChangeReviewersMergedPR  = errors.New("cannot change reviewers of merged PR")
+	ErrRequestReviewersClosedPR = errors.New("cannot request reviewers on closed PR")
+	ErrRequestReviewersDraftPR  = errors.New("cannot request reviewers on draft PR")
+	ErrReviewerIsAuthor         = errors.New("author cannot review own PR")
+	ErrReviewerInactive         = errors.New("reviewer is inactive")
+	ErrReviewerExcludedByRule   = errors.New("reviewer is excluded from author's PRs by team rule")
+	ErrAuthorWithoutTeam        = errors.New("PR author has no primary team")
 )
 
-const maxReviewersPerPR = 2
-
 func (uc *Usecases) CreatePR(ctx context.Context, reqDTO *dto.CreatePRRequest) (*models.PullRequest, error) {
 	const op = "usecases.CreatePR"
 	log := uc.log.With(slog.String("op", op), slog.String("pr_id", reqDTO.Id))
@@ -42,22 +59,38 @@ func (uc *Usecases) CreatePR(ctx context.Context, reqDTO *dto.CreatePRRequest) (
 		}
 	}()
 
-	_, err = uc.db.GetUserById(ctx, reqDTO.AuthorID)
+	author, err := uc.db.GetUserById(ctx, reqDTO.AuthorID)
 	if err != nil {
 		if errors.Is(err, postgres.ErrUserNotFound) {
 			log.Warn("author not found")
-			return nil, ErrUserNotFound
+			err = ErrUserNotFound
+			return nil, err
 		}
 		log.Error("error getting user by id", slog.String("error", err.Error()))
 		return nil, err
 	}
+	team, err := uc.prTeam(ctx, tx, author, reqDTO.TeamName)
+	if err != nil {
+		if errors.Is(err, ErrAuthorWithoutTeam) || errors.Is(err, ErrAuthorNotInTeam) {
+			log.Warn("cannot determine PR team", slog.String("error", err.Error()))
+			return nil, err
+		}
+		log.Error("error getting PR team", slog.String("error", err.Error()))
+		return nil, err
+	}
 
+	// на черновик ревьюверы не назначаются, пока он не станет готов к ревью
+	status := models.StatusOpen
+	if reqDTO.Draft {
+		status = models.StatusDraft
+	}
 	err = uc.db.CreatePR(ctx, tx, &models.PullRequestShort{
 		Id:                reqDTO.Id,
 		Title:             reqDTO.Title,
 		AuthorId:          reqDTO.AuthorID,
-		Status:            models.StatusOpen,
-		NeedMoreReviewers: true,
+		TeamId:            team.Id,
+		Status:            status,
+		NeedMoreReviewers: !reqDTO.Draft,
 	})
 	if err != nil {
 		if errors.Is(err, postgres.ErrPRAlreadyExists) {
@@ -68,39 +101,41 @@ func (uc *Usecases) CreatePR(ctx context.Context, reqDTO *dto.CreatePRRequest) (
 		return nil, err
 	}
 
-	log.Debug("PR created successfully")
-
-	members, err := uc.db.GetMembers(ctx, tx, reqDTO.Id)
+	err = uc.recordEvents(ctx, tx, models.TriggerCreate, &models.PREvent{
+		PrId: reqDTO.Id,
+		Kind: models.EventCreated,
+		To:   string(status),
+	})
 	if err != nil {
-		log.Error("error getting members", slog.String("error", err.Error()))
+		log.Error("error recording PR event", slog.String("error", err.Error()))
 		return nil, err
 	}
-	log.Debug("members got successfully", slog.Int("members_count", len(members)))
 
-	members = onlyActiveMembers(members)
-	utils.Shuffle(members)
-
-	reviewers := make([]string, 0, maxReviewersPerPR)
-	for range maxReviewersPerPR {
-		assigneeId, err := uc.db.AssignPRToUser(ctx, tx, reqDTO.Id, members)
+	if len(reqDTO.Labels) > 0 {
+		err = uc.db.SetPRLabels(ctx, tx, reqDTO.Id, utils.NormalizeTags(reqDTO.Labels))
 		if err != nil {
-			log.Error("error assigning PR to user", slog.String("error", err.Error()))
+			log.Error("error setting PR labels", slog.String("error", err.Error()))
 			return nil, err
 		}
-		if assigneeId != "" {
-			reviewers = append(reviewers, assigneeId)
+	}
+
+	if len(reqDTO.Files) > 0 {
+		err = uc.db.AddPRFiles(ctx, tx, reqDTO.Id, reqDTO.Files)
+		if err != nil {
+			log.Error("error adding PR files", slog.String("error", err.Error()))
+			return nil, err
 		}
 	}
 
-	if len(reviewers) == 2 {
-		err = uc.db.UpdatePR(ctx, tx, &models.PullRequestShort{
-			Id:                reqDTO.Id,
-			NeedMoreReviewers: false,
-		})
+	var applied []*models.ReviewerRule
+	if !reqDTO.Draft {
+		var a *assignment
+		a, err = uc.assignOpenedPR(ctx, tx, reqDTO.Id, models.TriggerCreate)
 		if err != nil {
-			log.Error("error creating PR", slog.String("error", err.Error()))
+			log.Error("error assigning PR to users", slog.String("error", err.Error()))
 			return nil, err
 		}
+		applied = a.applied
 	}
 
 	pr, err := uc.db.GetPRById(ctx, tx, reqDTO.Id)
@@ -108,12 +143,31 @@ func (uc *Usecases) CreatePR(ctx context.Context, reqDTO *dto.CreatePRRequest) (
 		log.Error("error getting PR", slog.String("error", err.Error()))
 		return nil, err
 	}
+	pr.AppliedRules = applied
 
 	log.Debug("PR created successfully")
 
 	return pr, nil
 }
 
+// prTeam возвращает команду, в которой автор author открывает PR: teamName, если она указана, иначе основную команду автора.
+// В указанной команде автор должен состоять
+func (uc *Usecases) prTeam(ctx context.Context, tx pgx.Tx, author *models.User, teamName string) (*models.Team, error) {
+	if teamName == "" {
+		if author.TeamName == "" {
+			return nil, ErrAuthorWithoutTeam
+		}
+		teamName = author.TeamName
+	}
+	if !slices.Contains(author.Teams, teamName) {
+		return nil, ErrAuthorNotInTeam
+	}
+
+	return uc.db.GetTeamByName(ctx, tx, teamName)
+}
+
+// MergePR переводит PR в MERGED. Если в команде автора задано required_approvals,
+// PR мержится, только когда столько назначенных ревьюверов одобрили его последним решением
 func (uc *Usecases) MergePR(ctx context.Context, prId string) (*models.PullRequest, error) {
 	const op = "usecases.MergePR"
 	log := uc.log.With(slog.String("op", op), slog.String("pr_id", prId))
@@ -145,6 +199,34 @@ func (uc *Usecases) MergePR(ctx context.Context, prId string) (*models.PullReque
 		log.Warn("PR is already merged")
 		return pr, nil
 	}
+	if !pr.Status.CanTransitionTo(models.StatusMerged) {
+		log.Warn("illegal PR status transition", slog.String("status", string(pr.Status)))
+		err = fmt.Errorf("%w: %s -> %s", ErrIllegalTransition, pr.Status, models.StatusMerged)
+		return nil, err
+	}
+
+	// у PR'а автора без команды нет требования одобрений
+	team, err := uc.db.GetTeamByPRId(ctx, tx, prId)
+	if errors.Is(err, postgres.ErrTeamNotFound) {
+		team, err = &models.Team{}, nil
+	}
+	if err != nil {
+		log.Error("error getting team of PR", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if team.RequiredApprovals > 0 {
+		var approvals int
+		approvals, err = uc.db.CountApprovals(ctx, tx, prId)
+		if err != nil {
+			log.Error("error counting approvals", slog.String("error", err.Error()))
+			return nil, err
+		}
+		if approvals < team.RequiredApprovals {
+			log.Warn("not enough approvals to merge PR", slog.Int("approvals", approvals), slog.Int("required_approvals", team.RequiredApprovals))
+			err = ErrNotEnoughApprovals
+			return nil, err
+		}
+	}
 
 	err = uc.db.MergePR(ctx, tx, prId)
 	if err != nil {
@@ -156,6 +238,12 @@ func (uc *Usecases) MergePR(ctx context.Context, prId string) (*models.PullReque
 		return nil, err
 	}
 
+	err = uc.recordStatusChanged(ctx, tx, models.TriggerMerge, prId, pr.Status, models.StatusMerged)
+	if err != nil {
+		log.Error("error recording PR event", slog.String("error", err.Error()))
+		return nil, err
+	}
+
 	pr, err = uc.db.GetPRById(ctx, tx, prId)
 	if err != nil {
 		log.Error("error getting PR by id", slog.String("error", err.Error()))
@@ -209,48 +297,790 @@ func (uc *Usecases) ReassignPR(ctx context.Context, reqDTO *dto.ReassignPRReques
 		return nil, "", ErrPRMerged
 	}
 
-	err = uc.db.UnassignPRFromUser(ctx, tx, reqDTO.PullRequestID, reqDTO.OldReviewerID)
+	a, newReviewerId, err := uc.reassignReviewer(ctx, tx, reqDTO.PullRequestID, reqDTO.OldReviewerID, models.TriggerReassign)
 	if err != nil {
 		if errors.Is(err, postgres.ErrPRNotFound) {
 			log.Warn("PR not found")
-			return nil, "", ErrPRNotFound
+			err = ErrPRNotFound
+			return nil, "", err
 		}
-		log.Error("error unassigning PR from user", slog.String("error", err.Error()))
+		if errors.Is(err, ErrNoCandidatesToAssign) {
+			log.Warn("error assigning PR to user", slog.String("error", err.Error()))
+			return nil, "", err
+		}
+		log.Error("error reassigning reviewer", slog.String("error", err.Error()))
 		return nil, "", err
 	}
-	log.Debug("PR unassigned from old reviewer successfully")
 
-	members, err := uc.db.GetMembers(ctx, tx, reqDTO.PullRequestID)
+	pr, err = uc.db.GetPRById(ctx, tx, reqDTO.PullRequestID)
 	if err != nil {
-		log.Error("error getting members", slog.String("error", err.Error()))
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
 		return nil, "", err
 	}
-	log.Debug("members got successfully", slog.Int("members_count", len(members)))
+	pr.AppliedRules = a.applied
 
-	members = delMember(members, reqDTO.OldReviewerID)
-	members = onlyActiveMembers(members)
-	utils.Shuffle(members)
+	log.Debug("pr reassigned successfully")
+
+	return pr, newReviewerId, nil
+}
 
-	newReviewerId, err := uc.db.AssignPRToUser(ctx, tx, reqDTO.PullRequestID, members)
+// reassignReviewer снимает ревьювера oldReviewerId с PR'а и подбирает ему одну замену по стратегии команды автора.
+// Если замены нет, возвращает ErrNoCandidatesToAssign: снятие ревьювера должно откатиться вместе с транзакцией
+func (uc *Usecases) reassignReviewer(ctx context.Context, tx pgx.Tx, prId string, oldReviewerId string, trigger models.DecisionTrigger) (*assignment, string, error) {
+	err := uc.db.UnassignPRFromUser(ctx, tx, prId, oldReviewerId)
 	if err != nil {
-		log.Error("error assigning PR to user", slog.String("error", err.Error()))
 		return nil, "", err
 	}
-	if newReviewerId == "" {
-		err = ErrNoCandidatesToAssign
-		log.Warn("error assigning PR to user", slog.String("error", err.Error()))
+	err = uc.recordUnassigned(ctx, tx, trigger, prId, oldReviewerId)
+	if err != nil {
 		return nil, "", err
 	}
 
-	pr, err = uc.db.GetPRById(ctx, tx, reqDTO.PullRequestID)
+	team, err := uc.db.GetTeamByPRId(ctx, tx, prId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			return nil, "", ErrNoCandidatesToAssign
+		}
+		return nil, "", err
+	}
+
+	a := &assignment{
+		team:    team,
+		prId:    prId,
+		trigger: trigger,
+		count:   1,
+		exclude: []string{oldReviewerId},
+	}
+	newReviewers, err := uc.assignReviewers(ctx, tx, a)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(newReviewers) == 0 {
+		return nil, "", ErrNoCandidatesToAssign
+	}
+
+	err = uc.refreshNeedMoreReviewers(ctx, tx, a)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return a, newReviewers[0], nil
+}
+
+// AddReviewers назначает на PR явно запрошенных ревьюверов, в том числе из других команд, сверх автоматического подбора.
+// Уже назначенные пропускаются. Возвращает PR и ревьюверов, которые были назначены этим запросом
+func (uc *Usecases) AddReviewers(ctx context.Context, reqDTO *dto.AddReviewersRequest) (*models.PullRequest, []string, error) {
+	const op = "usecases.AddReviewers"
+	log := uc.log.With(slog.String("op", op), slog.String("pr_id", reqDTO.PullRequestID))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	pr, err := uc.db.GetPRById(ctx, tx, reqDTO.PullRequestID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPRNotFound) {
+			log.Warn("PR not found")
+			err = ErrPRNotFound
+			return nil, nil, err
+		}
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+	switch pr.Status {
+	case models.StatusMerged:
+		log.Warn("cannot change reviewers of merged PR")
+		err = ErrChangeReviewersMergedPR
+		return nil, nil, err
+	case models.StatusClosed:
+		log.Warn("cannot request reviewers on closed PR")
+		err = ErrRequestReviewersClosedPR
+		return nil, nil, err
+	case models.StatusDraft:
+		// у черновика нет ревьюверов, пока он не готов к ревью
+		log.Warn("cannot request reviewers on draft PR")
+		err = ErrRequestReviewersDraftPR
+		return nil, nil, err
+	}
+
+	reviewerIds := slices.Compact(slices.Sorted(slices.Values(reqDTO.ReviewerIDs)))
+	members, err := uc.db.GetUsersByIds(ctx, tx, reviewerIds)
+	if err != nil {
+		log.Error("error getting users by ids", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+	if len(members) != len(reviewerIds) {
+		log.Warn("one or more reviewers not found")
+		err = ErrUserNotFound
+		return nil, nil, err
+	}
+
+	team, err := uc.db.GetTeamByPRId(ctx, tx, pr.Id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			log.Warn("PR has no team")
+			err = ErrAuthorWithoutTeam
+			return nil, nil, err
+		}
+		log.Error("error getting team by PR id", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	a := &assignment{
+		team:      team,
+		prId:      pr.Id,
+		trigger:   models.TriggerRequest,
+		count:     len(members),
+		skipEmpty: true,
+		requested: members,
+	}
+	added, err := uc.assignReviewers(ctx, tx, a)
+	if err != nil {
+		if errors.Is(err, ErrReviewerIsAuthor) || errors.Is(err, ErrReviewerInactive) || errors.Is(err, ErrReviewerExcludedByRule) {
+			log.Warn("reviewer cannot be requested", slog.String("error", err.Error()))
+			return nil, nil, err
+		}
+		log.Error("error assigning requested reviewers", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	if pr.Status == models.StatusOpen {
+		err = uc.refreshNeedMoreReviewers(ctx, tx, a)
+		if err != nil {
+			log.Error("error refreshing need_more_reviewers", slog.String("error", err.Error()))
+			return nil, nil, err
+		}
+	}
+
+	pr, err = uc.db.GetPRById(ctx, tx, pr.Id)
+	if err != nil {
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	log.Debug("reviewers requested successfully", slog.Int("added_count", len(added)))
+	return pr, added, nil
+}
+
+// RemoveReviewer снимает ревьювера с PR'а. С backfill недостающие до квоты команды ревьюверы сразу подбираются по обычным правилам
+// (снятый ревьювер не подбирается), без него место остаётся пустым, а PR получает need_more_reviewers с причиной REVIEWER_REMOVED,
+// которую фоновое дозаполнение не трогает. Возвращает PR и подобранную замену, если она есть
+func (uc *Usecases) RemoveReviewer(ctx context.Context, reqDTO *dto.RemoveReviewerRequest) (*models.PullRequest, string, error) {
+	const op = "usecases.RemoveReviewer"
+	log := uc.log.With(slog.String("op", op), slog.String("pr_id", reqDTO.PullRequestID), slog.String("reviewer_id", reqDTO.ReviewerID))
+
+	tx, err := uc.db.BeginTx(ctx)
 	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, "", err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	pr, err := uc.db.GetPRById(ctx, tx, reqDTO.PullRequestID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPRNotFound) {
+			log.Warn("PR not found")
+			err = ErrPRNotFound
+			return nil, "", err
+		}
 		log.Error("error getting PR by id", slog.String("error", err.Error()))
 		return nil, "", err
 	}
+	if pr.Status == models.StatusMerged {
+		log.Warn("cannot change reviewers of merged PR")
+		err = ErrChangeReviewersMergedPR
+		return nil, "", err
+	}
+	if !slices.Contains(pr.Reviewers, reqDTO.ReviewerID) {
+		log.Warn("user is not a reviewer of the PR")
+		err = ErrUserNotReviewerOfPR
+		return nil, "", err
+	}
 
-	log.Debug("pr reassigned successfully")
+	err = uc.db.UnassignPRFromUser(ctx, tx, pr.Id, reqDTO.ReviewerID)
+	if err != nil {
+		log.Error("error unassigning reviewer", slog.String("error", err.Error()))
+		return nil, "", err
+	}
+	err = uc.recordUnassigned(ctx, tx, models.TriggerRemove, pr.Id, reqDTO.ReviewerID)
+	if err != nil {
+		log.Error("error recording PR event", slog.String("error", err.Error()))
+		return nil, "", err
+	}
 
-	return pr, newReviewerId, nil
+	var replacedBy string
+	if pr.Status == models.StatusOpen {
+		replacedBy, err = uc.refillAfterRemoval(ctx, tx, pr, reqDTO.ReviewerID, reqDTO.Backfill)
+		if err != nil {
+			log.Error("error refilling reviewers", slog.String("error", err.Error()))
+			return nil, "", err
+		}
+	}
+
+	pr, err = uc.db.GetPRById(ctx, tx, pr.Id)
+	if err != nil {
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, "", err
+	}
+
+	log.Debug("reviewer removed successfully", slog.String("replaced_by", replacedBy))
+	return pr, replacedBy, nil
+}
+
+// refillAfterRemoval пересчитывает need_more_reviewers открытого PR'а pr после снятия ревьювера removedId,
+// а с backfill сначала подбирает недостающих до квоты команды ревьюверов. Возвращает первого подобранного
+func (uc *Usecases) refillAfterRemoval(ctx context.Context, tx pgx.Tx, pr *models.PullRequest, removedId string, backfill bool) (string, error) {
+	team, err := uc.db.GetTeamByPRId(ctx, tx, pr.Id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			// у автора нет команды, а значит, и квоты ревьюверов
+			return "", nil
+		}
+		return "", err
+	}
+	missing := team.ReviewersPerPR - (len(pr.Reviewers) - 1)
+
+	if !backfill {
+		var reason models.NeedMoreReviewersReason
+		if missing > 0 {
+			reason = models.ReasonReviewerRemoved
+		}
+		return "", uc.setNeedMoreReviewers(ctx, tx, models.TriggerRemove, pr.Id, pr.NeedMoreReviewersReason, reason)
+	}
+
+	a := &assignment{
+		team:    team,
+		prId:    pr.Id,
+		trigger: models.TriggerRemove,
+		count:   max(missing, 0),
+		exclude: []string{removedId},
+	}
+	var replacedBy string
+	if missing > 0 {
+		reviewers, err := uc.assignReviewers(ctx, tx, a)
+		if err != nil {
+			return "", err
+		}
+		if len(reviewers) > 0 {
+			replacedBy = reviewers[0]
+		}
+	}
+
+	return replacedBy, uc.refreshNeedMoreReviewers(ctx, tx, a)
+}
+
+// UpdatePR изменяет название и/или автора PR'а.
+// При смене автора открытого PR'а ревьюверы пересматриваются: новый автор снимается с ревью, а если он из другой команды,
+// то по политике reqDTO.ReviewersPolicy текущие ревьюверы остаются (KEEP) или снимаются (REPLACE).
+// После этого недостающие до квоты команды нового автора ревьюверы подбираются из неё
+func (uc *Usecases) UpdatePR(ctx context.Context, reqDTO *dto.UpdatePRRequest) (*models.PullRequest, error) {
+	const op = "usecases.UpdatePR"
+	log := uc.log.With(slog.String("op", op), slog.String("pr_id", reqDTO.PullRequestID))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	pr, err := uc.db.GetPRById(ctx, tx, reqDTO.PullRequestID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPRNotFound) {
+			log.Warn("PR not found")
+			err = ErrPRNotFound
+			return nil, err
+		}
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	update := &models.PullRequestShort{Id: pr.Id}
+	if reqDTO.Title != nil {
+		update.Title = *reqDTO.Title
+	}
+	authorChanged := reqDTO.AuthorID != nil && *reqDTO.AuthorID != pr.AuthorId
+	var applied []*models.ReviewerRule
+	if authorChanged {
+		if pr.Status == models.StatusMerged {
+			log.Warn("cannot change author of merged PR")
+			err = ErrChangeAuthorMergedPR
+			return nil, err
+		}
+		var author *models.User
+		author, err = uc.db.GetUserById(ctx, *reqDTO.AuthorID)
+		if err != nil {
+			if errors.Is(err, postgres.ErrUserNotFound) {
+				log.Warn("author not found")
+				err = ErrUserNotFound
+				return nil, err
+			}
+			log.Error("error getting user by id", slog.String("error", err.Error()))
+			return nil, err
+		}
+		applied, err = uc.changePRAuthor(ctx, tx, pr, update, author, reqDTO.ReviewersPolicy)
+		if err != nil {
+			if errors.Is(err, ErrAuthorWithoutTeam) {
+				log.Warn("author has no primary team")
+				return nil, err
+			}
+			log.Error("error changing PR author", slog.String("error", err.Error()))
+			return nil, err
+		}
+	} else {
+		err = uc.db.UpdatePR(ctx, tx, update)
+		if err != nil {
+			log.Error("error updating PR", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	pr, err = uc.db.GetPRById(ctx, tx, reqDTO.PullRequestID)
+	if err != nil {
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+	pr.AppliedRules = applied
+
+	log.Debug("PR updated successfully", slog.Bool("author_changed", authorChanged))
+	return pr, nil
+}
+
+// changePRAuthor меняет автора PR'а pr на author вместе с остальными полями update. PR остаётся в своей команде,
+// если новый автор в ней состоит, иначе переходит в его основную. Ревьюверы открытого PR'а пересматриваются по политике policy,
+// на черновиках и закрытых PR'ах ревьюверов нет, они подберутся при переходе в OPEN
+func (uc *Usecases) changePRAuthor(ctx context.Context, tx pgx.Tx, pr *models.PullRequest, update *models.PullRequestShort, author *models.User, policy models.AuthorChangePolicy) ([]*models.ReviewerRule, error) {
+	if !slices.Contains(author.Teams, pr.TeamName) {
+		team, err := uc.prTeam(ctx, tx, author, "")
+		if err != nil {
+			return nil, err
+		}
+		update.TeamId = team.Id
+	}
+	update.AuthorId = author.Id
+
+	// прежнюю команду PR'а нужно узнать до изменения PR'а, nil - PR был без команды
+	oldTeam, err := uc.db.GetTeamByPRId(ctx, tx, pr.Id)
+	if errors.Is(err, postgres.ErrTeamNotFound) {
+		oldTeam, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.db.UpdatePR(ctx, tx, update)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.recordEvents(ctx, tx, models.TriggerAuthorChange, &models.PREvent{
+		PrId: pr.Id,
+		Kind: models.EventAuthorChanged,
+		From: pr.AuthorId,
+		To:   author.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.Status != models.StatusOpen {
+		return nil, nil
+	}
+	return uc.reassignOnAuthorChange(ctx, tx, pr, oldTeam, author.Id, policy)
+}
+
+// reassignOnAuthorChange пересматривает ревьюверов открытого PR'а pr после смены автора на authorId
+func (uc *Usecases) reassignOnAuthorChange(ctx context.Context, tx pgx.Tx, pr *models.PullRequest, oldTeam *models.Team, authorId string, policy models.AuthorChangePolicy) ([]*models.ReviewerRule, error) {
+	team, err := uc.db.GetTeamByPRId(ctx, tx, pr.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := len(pr.Reviewers)
+	if (oldTeam == nil || team.Id != oldTeam.Id) && policy == models.PolicyReplace {
+		var unassigned []string
+		unassigned, err = uc.db.UnassignAllFromPR(ctx, tx, pr.Id)
+		if err != nil {
+			return nil, err
+		}
+		err = uc.recordUnassigned(ctx, tx, models.TriggerAuthorChange, pr.Id, unassigned...)
+		if err != nil {
+			return nil, err
+		}
+		reviewers = 0
+	} else if slices.Contains(pr.Reviewers, authorId) {
+		err = uc.db.UnassignPRFromUser(ctx, tx, pr.Id, authorId)
+		if err != nil {
+			return nil, err
+		}
+		err = uc.recordUnassigned(ctx, tx, models.TriggerAuthorChange, pr.Id, authorId)
+		if err != nil {
+			return nil, err
+		}
+		reviewers--
+	}
+
+	a := &assignment{
+		team:    team,
+		prId:    pr.Id,
+		trigger: models.TriggerAuthorChange,
+		count:   max(team.ReviewersPerPR-reviewers, 0),
+	}
+	if a.count > 0 {
+		_, err = uc.assignReviewers(ctx, tx, a)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = uc.refreshNeedMoreReviewers(ctx, tx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.applied, nil
+}
+
+// ReadyPR переводит черновик PR'а в OPEN и назначает на него ревьюверов
+func (uc *Usecases) ReadyPR(ctx context.Context, prId string) (*models.PullRequest, error) {
+	return uc.changePRStatus(ctx, prId, models.StatusDraft, models.StatusOpen, models.TriggerReady)
+}
+
+// ClosePR закрывает PR без merge и снимает с него всех ревьюверов
+func (uc *Usecases) ClosePR(ctx context.Context, prId string) (*models.PullRequest, error) {
+	const op = "usecases.ClosePR"
+	log := uc.log.With(slog.String("op", op), slog.String("pr_id", prId))
+
+	// закрыть можно и черновик, и открытый PR: переход проверяется по текущему статусу
+	pr, err := uc.db.GetPRById(ctx, nil, prId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return nil, ErrPRNotFound
+		}
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return uc.changePRStatus(ctx, prId, pr.Status, models.StatusClosed, models.TriggerClose)
+}
+
+// ReopenPR переоткрывает закрытый PR и заново назначает на него ревьюверов
+func (uc *Usecases) ReopenPR(ctx context.Context, prId string) (*models.PullRequest, error) {
+	return uc.changePRStatus(ctx, prId, models.StatusClosed, models.StatusOpen, models.TriggerReopen)
+}
+
+// changePRStatus переводит PR из статуса from в статус to по допустимому переходу.
+// При переходе в OPEN ревьюверы назначаются до квоты команды, при переходе в CLOSED - снимаются.
+// trigger - операция для записи о подборе и истории PR'а
+func (uc *Usecases) changePRStatus(ctx context.Context, prId string, from models.Status, to models.Status, trigger models.DecisionTrigger) (*models.PullRequest, error) {
+	const op = "usecases.changePRStatus"
+	log := uc.log.With(slog.String("op", op), slog.String("pr_id", prId), slog.String("to", string(to)))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	pr, err := uc.db.GetPRById(ctx, tx, prId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPRNotFound) {
+			log.Warn("PR not found")
+			err = ErrPRNotFound
+			return nil, err
+		}
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if pr.Status != from || !from.CanTransitionTo(to) {
+		log.Warn("illegal PR status transition", slog.String("status", string(pr.Status)))
+		err = fmt.Errorf("%w: %s -> %s", ErrIllegalTransition, pr.Status, to)
+		return nil, err
+	}
+
+	err = uc.db.SetPRStatus(ctx, tx, prId, from, to)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPRStatusChanged) {
+			log.Warn("PR status changed concurrently")
+			err = fmt.Errorf("%w: %s -> %s", ErrIllegalTransition, from, to)
+			return nil, err
+		}
+		log.Error("error setting PR status", slog.String("error", err.Error()))
+		return nil, err
+	}
+	err = uc.recordStatusChanged(ctx, tx, trigger, prId, from, to)
+	if err != nil {
+		log.Error("error recording PR event", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	var applied []*models.ReviewerRule
+	switch to {
+	case models.StatusOpen:
+		var a *assignment
+		a, err = uc.assignOpenedPR(ctx, tx, prId, trigger)
+		if err != nil {
+			log.Error("error assigning PR to users", slog.String("error", err.Error()))
+			return nil, err
+		}
+		applied = a.applied
+	case models.StatusClosed:
+		err = uc.unassignClosedPR(ctx, tx, pr, trigger)
+		if err != nil {
+			log.Error("error unassigning reviewers of closed PR", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	pr, err = uc.db.GetPRById(ctx, tx, prId)
+	if err != nil {
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+	pr.AppliedRules = applied
+
+	log.Debug("PR status changed successfully", slog.String("from", string(from)))
+	return pr, nil
+}
+
+// unassignClosedPR снимает всех ревьюверов с закрытого PR'а pr и сбрасывает need_more_reviewers
+func (uc *Usecases) unassignClosedPR(ctx context.Context, tx pgx.Tx, pr *models.PullRequest, trigger models.DecisionTrigger) error {
+	unassigned, err := uc.db.UnassignAllFromPR(ctx, tx, pr.Id)
+	if err != nil {
+		return err
+	}
+	err = uc.recordUnassigned(ctx, tx, trigger, pr.Id, unassigned...)
+	if err != nil {
+		return err
+	}
+
+	return uc.setNeedMoreReviewers(ctx, tx, trigger, pr.Id, pr.NeedMoreReviewersReason, "")
+}
+
+// ReviewPR сохраняет решение назначенного ревьювера по открытому PR'у. Ревьювер может менять решение, учитывается последнее
+func (uc *Usecases) ReviewPR(ctx context.Context, reqDTO *dto.ReviewPRRequest) (*models.PullRequest, *models.Review, error) {
+	const op = "usecases.ReviewPR"
+	log := uc.log.With(slog.String("op", op), slog.String("pr_id", reqDTO.PullRequestID), slog.String("reviewer_id", reqDTO.ReviewerID))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	pr, err := uc.db.GetPRById(ctx, tx, reqDTO.PullRequestID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPRNotFound) {
+			log.Warn("PR not found")
+			err = ErrPRNotFound
+			return nil, nil, err
+		}
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+	switch pr.Status {
+	case models.StatusMerged:
+		log.Warn("cannot review merged PR")
+		err = ErrReviewMergedPR
+		return nil, nil, err
+	case models.StatusClosed:
+		log.Warn("cannot review closed PR")
+		err = ErrReviewClosedPR
+		return nil, nil, err
+	case models.StatusDraft:
+		log.Warn("cannot review draft PR")
+		err = ErrReviewDraftPR
+		return nil, nil, err
+	}
+	if !slices.Contains(pr.Reviewers, reqDTO.ReviewerID) {
+		log.Warn("user is not a reviewer of the PR")
+		err = ErrUserNotReviewerOfPR
+		return nil, nil, err
+	}
+
+	review := &models.Review{
+		Id:         uuid.NewString(),
+		PrId:       reqDTO.PullRequestID,
+		ReviewerId: reqDTO.ReviewerID,
+		Decision:   reqDTO.Decision,
+		Comment:    reqDTO.Comment,
+	}
+	err = uc.db.AddReview(ctx, tx, review)
+	if err != nil {
+		log.Error("error adding review", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	pr, err = uc.db.GetPRById(ctx, tx, reqDTO.PullRequestID)
+	if err != nil {
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+
+	log.Debug("PR reviewed successfully", slog.String("decision", string(review.Decision)))
+	return pr, review, nil
+}
+
+// GetAssignmentDecisions возвращает записи о том, как подбирались ревьюверы PR'а, в порядке их принятия
+func (uc *Usecases) GetAssignmentDecisions(ctx context.Context, prId string) ([]*models.AssignmentDecision, error) {
+	const op = "usecases.GetAssignmentDecisions"
+	log := uc.log.With(slog.String("op", op), slog.String("pr_id", prId))
+
+	_, err := uc.db.GetPRById(ctx, nil, prId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return nil, ErrPRNotFound
+		}
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	decisions, err := uc.db.GetAssignmentDecisions(ctx, prId)
+	if err != nil {
+		log.Error("error getting assignment decisions", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Debug("assignment decisions got successfully", slog.Int("decisions_count", len(decisions)))
+	return decisions, nil
+}
+
+// GetPR возвращает PR вместе с автором, его командой и назначенными ревьюверами
+func (uc *Usecases) GetPR(ctx context.Context, prId string) (*models.PRDetails, error) {
+	const op = "usecases.GetPR"
+	log := uc.log.With(slog.String("op", op), slog.String("pr_id", prId))
+
+	pr, err := uc.db.GetPRById(ctx, nil, prId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPRNotFound) {
+			log.Warn("PR not found")
+			return nil, ErrPRNotFound
+		}
+		log.Error("error getting PR by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	users, err := uc.db.GetUsersWithTeamByIds(ctx, nil, append([]string{pr.AuthorId}, pr.Reviewers...))
+	if err != nil {
+		log.Error("error getting users by ids", slog.String("error", err.Error()))
+		return nil, err
+	}
+	byId := make(map[string]*models.User, len(users))
+	for _, user := range users {
+		byId[user.Id] = user
+	}
+
+	details := &models.PRDetails{
+		PR:                pr,
+		NeedMoreReviewers: pr.NeedMoreReviewers,
+		Author:            byId[pr.AuthorId],
+		Reviewers:         make([]*models.User, 0, len(pr.Reviewers)),
+	}
+	for _, reviewerId := range pr.Reviewers {
+		details.Reviewers = append(details.Reviewers, byId[reviewerId])
+	}
+
+	log.Debug("PR got successfully")
+	return details, nil
+}
+
+// ListPRs возвращает страницу списка PR'ов и позицию следующей страницы, nil - страница последняя
+func (uc *Usecases) ListPRs(ctx context.Context, reqDTO *dto.ListPRsRequest) ([]*models.PullRequest, *models.PRCursor, error) {
+	const op = "usecases.ListPRs"
+	log := uc.log.With(slog.String("op", op))
+
+	// берём на один PR больше, чтобы узнать, есть ли следующая страница
+	filter := reqDTO.PRListFilter
+	filter.Limit++
+	prs, err := uc.db.ListPRs(ctx, &filter)
+	if err != nil {
+		log.Error("error listing PRs", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+	if len(prs) <= reqDTO.Limit {
+		log.Debug("PRs listed successfully", slog.Int("prs_count", len(prs)))
+		return prs, nil, nil
+	}
+
+	prs = prs[:reqDTO.Limit]
+	last := prs[len(prs)-1]
+	next := &models.PRCursor{
+		Sort: reqDTO.Sort,
+		Desc: reqDTO.Desc,
+		Id:   last.Id,
+	}
+	switch reqDTO.Sort {
+	case models.PRSortCreatedAt:
+		next.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	case models.PRSortMergedAt:
+		next.Value = last.MergedAt.Format(time.RFC3339Nano)
+	case models.PRSortTitle:
+		next.Value = last.Title
+	}
+
+	log.Debug("PRs listed successfully", slog.Int("prs_count", len(prs)))
+	return prs, next, nil
 }
 
 func (uc *Usecases) GetStatistics(ctx context.Context, reqDTO *dto.StatisticsRequest) (map[string]int, uint64, error) {
@@ -0,0 +1,117 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"pr-review/internal/http/dto"
+	"pr-review/internal/models"
+	"pr-review/internal/repository/postgres"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReviewerRuleNotFound = errors.New("reviewer rule not found")
+	ErrReviewerRuleExists   = errors.New("rule for this author and reviewer already exists in team")
+	ErrAuthorNotInTeam      = errors.New("author is not a member of the team")
+)
+
+func (uc *Usecases) GetReviewerRules(ctx context.Context, teamName string) ([]*models.ReviewerRule, error) {
+	const op = "usecases.GetReviewerRules"
+	log := uc.log.With(slog.String("op", op), slog.String("team_name", teamName))
+
+	team, err := uc.db.GetTeamByName(ctx, nil, teamName)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, ErrTeamNotFound
+		}
+		log.Error("error getting team", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	rules, err := uc.db.GetReviewerRules(ctx, nil, team.Id)
+	if err != nil {
+		log.Error("error getting reviewer rules", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Debug("reviewer rules got successfully", slog.Int("rules_count", len(rules)))
+	return rules, nil
+}
+
+// AddReviewerRule добавляет команде правило для пары автор - ревьювер. Автор должен быть участником команды,
+// ревьювер может быть из любой команды: правило действует и при подборе из владельцев и резервных команд.
+// На уже открытые PR'ы правило не влияет до следующего подбора ревьюверов
+func (uc *Usecases) AddReviewerRule(ctx context.Context, reqDTO *dto.AddReviewerRuleRequest) (*models.ReviewerRule, error) {
+	const op = "usecases.AddReviewerRule"
+	log := uc.log.With(slog.String("op", op), slog.String("team_name", reqDTO.TeamName))
+
+	team, err := uc.db.GetTeamByName(ctx, nil, reqDTO.TeamName)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, ErrTeamNotFound
+		}
+		log.Error("error getting team", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	author, err := uc.db.GetUserById(ctx, reqDTO.AuthorId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrUserNotFound) {
+			log.Warn("author not found")
+			return nil, ErrUserNotFound
+		}
+		log.Error("error getting user by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if !slices.Contains(author.Teams, team.Name) {
+		log.Warn("author is not a member of the team")
+		return nil, ErrAuthorNotInTeam
+	}
+
+	rule := &models.ReviewerRule{
+		Id:         uuid.NewString(),
+		TeamName:   team.Name,
+		Kind:       reqDTO.Kind,
+		AuthorId:   reqDTO.AuthorId,
+		ReviewerId: reqDTO.ReviewerId,
+	}
+	err = uc.db.AddReviewerRule(ctx, team.Id, rule)
+	if err != nil {
+		if errors.Is(err, postgres.ErrReviewerRuleExists) {
+			log.Warn("reviewer rule already exists")
+			return nil, ErrReviewerRuleExists
+		}
+		if errors.Is(err, postgres.ErrUserNotFound) {
+			log.Warn("reviewer not found")
+			return nil, ErrUserNotFound
+		}
+		log.Error("error adding reviewer rule", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Debug("reviewer rule added successfully", slog.String("rule_id", rule.Id))
+	return rule, nil
+}
+
+func (uc *Usecases) DeleteReviewerRule(ctx context.Context, id string) error {
+	const op = "usecases.DeleteReviewerRule"
+	log := uc.log.With(slog.String("op", op), slog.String("rule_id", id))
+
+	err := uc.db.DeleteReviewerRule(ctx, id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrReviewerRuleNotFound) {
+			log.Warn("reviewer rule not found")
+			return ErrReviewerRuleNotFound
+		}
+		log.Error("error deleting reviewer rule", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Debug("reviewer rule deleted successfully")
+	return nil
+}
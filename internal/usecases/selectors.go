@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"pr-review/internal/models"
+	"pr-review/internal/utils"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ReviewerSelector определяет порядок, в котором кандидаты назначаются ревьюверами PR'а.
+// AssignPRToUser назначает первого подходящего кандидата из упорядоченного списка
+type ReviewerSelector interface {
+	// Order возвращает кандидатов в порядке приоритета назначения.
+	// Случайность берётся только из rng, чтобы решение можно было воспроизвести по seed
+	Order(ctx context.Context, tx pgx.Tx, rng *rand.Rand, team *models.Team, candidates []*models.Member) ([]*models.Member, error)
+	// Assigned вызывается после назначения каждого ревьювера из списка, который вернул Order
+	Assigned(ctx context.Context, tx pgx.Tx, team *models.Team, reviewerId string) error
+}
+
+func newSelectors(db Storage) map[models.ReviewerStrategy]ReviewerSelector {
+	return map[models.ReviewerStrategy]ReviewerSelector{
+		models.StrategyRandom:         &randomSelector{},
+		models.StrategyRoundRobin:     &roundRobinSelector{db: db},
+		models.StrategyLeastLoaded:    &leastLoadedSelector{db: db},
+		models.StrategyWeightedRandom: &weightedRandomSelector{db: db},
+	}
+}
+
+// strategy возвращает стратегию команды, а если она не задана - стратегию по умолчанию.
+// Для кандидатов не из одной команды (team == nil) используется случайный выбор
+func (uc *Usecases) strategy(team *models.Team) models.ReviewerStrategy {
+	if team == nil {
+		return models.StrategyRandom
+	}
+	if _, ok := uc.selectors[team.ReviewerStrategy]; ok {
+		return team.ReviewerStrategy
+	}
+	return uc.defaultStrategy
+}
+
+// randomSelector перемешивает кандидатов случайным образом
+type randomSelector struct{}
+
+func (s *randomSelector) Order(_ context.Context, _ pgx.Tx, rng *rand.Rand, _ *models.Team, candidates []*models.Member) ([]*models.Member, error) {
+	ordered := append([]*models.Member(nil), candidates...)
+	utils.Shuffle(rng, ordered)
+	return ordered, nil
+}
+
+func (s *randomSelector) Assigned(context.Context, pgx.Tx, *models.Team, string) error {
+	return nil
+}
+
+// roundRobinSelector назначает участников команды по кругу, начиная со следующего после последнего назначенного
+type roundRobinSelector struct {
+	db Storage
+}
+
+func (s *roundRobinSelector) Order(ctx context.Context, tx pgx.Tx, _ *rand.Rand, team *models.Team, candidates []*models.Member) ([]*models.Member, error) {
+	ordered := append([]*models.Member(nil), candidates...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].Id < ordered[j].Id
+	})
+
+	lastId, err := s.db.GetLastReviewer(ctx, tx, team.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	start := sort.Search(len(ordered), func(i int) bool {
+		return ordered[i].Id > lastId
+	})
+	rotated := make([]*models.Member, 0, len(ordered))
+	rotated = append(rotated, ordered[start:]...)
+	return append(rotated, ordered[:start]...), nil
+}
+
+func (s *roundRobinSelector) Assigned(ctx context.Context, tx pgx.Tx, team *models.Team, reviewerId string) error {
+	return s.db.SetLastReviewer(ctx, tx, team.Id, reviewerId)
+}
+
+// leastLoadedSelector ставит первыми кандидатов с наименьшим количеством открытых ревью, при равенстве - в случайном порядке.
+// Кандидаты блокируются до конца транзакции, чтобы параллельные транзакции считали нагрузку с учётом уже сделанных назначений
+type leastLoadedSelector struct {
+	db Storage
+}
+
+func (s *leastLoadedSelector) Order(ctx context.Context, tx pgx.Tx, rng *rand.Rand, _ *models.Team, candidates []*models.Member) ([]*models.Member, error) {
+	ids := memberIds(candidates)
+	if err := s.db.LockUsers(ctx, tx, ids); err != nil {
+		return nil, err
+	}
+
+	loads, err := s.db.GetOpenReviewsCount(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := append([]*models.Member(nil), candidates...)
+	utils.Shuffle(rng, ordered)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return loads[ordered[i].Id] < loads[ordered[j].Id]
+	})
+	return ordered, nil
+}
+
+func (s *leastLoadedSelector) Assigned(context.Context, pgx.Tx, *models.Team, string) error {
+	return nil
+}
+
+// weightedRandomSelector выбирает кандидатов случайно с весом, обратно пропорциональным количеству открытых ревью
+type weightedRandomSelector struct {
+	db Storage
+}
+
+func (s *weightedRandomSelector) Order(ctx context.Context, tx pgx.Tx, rng *rand.Rand, _ *models.Team, candidates []*models.Member) ([]*models.Member, error) {
+	loads, err := s.db.GetOpenReviewsCount(ctx, tx, memberIds(candidates))
+	if err != nil {
+		return nil, err
+	}
+
+	// взвешенная выборка без возвращения (Efraimidis-Spirakis): ключ u^(1/w), сортировка по убыванию ключа
+	keys := make(map[string]float64, len(candidates))
+	for _, c := range candidates {
+		weight := 1 / float64(1+loads[c.Id])
+		keys[c.Id] = math.Pow(rng.Float64(), 1/weight)
+	}
+
+	ordered := append([]*models.Member(nil), candidates...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return keys[ordered[i].Id] > keys[ordered[j].Id]
+	})
+	return ordered, nil
+}
+
+func (s *weightedRandomSelector) Assigned(context.Context, pgx.Tx, *models.Team, string) error {
+	return nil
+}
+
+func memberIds(members []*models.Member) []string {
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.Id)
+	}
+	return ids
+}
+
+func activeMemberIds(members []*models.Member) []string {
+	ids := make([]string, 0, len(members))
+	for _, m := range members {
+		if m.IsActive {
+			ids = append(ids, m.Id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,192 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"pr-review/internal/models"
+	"pr-review/internal/repository/postgres"
+	"pr-review/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// escalationBatchSize сколько назначений проверяется на нарушение SLA в одной транзакции
+const escalationBatchSize = 100
+
+// GetOverduePRs возвращает открытые PR'ы авторов команды, назначенные ревьюверы которых не приняли решение за SLA команды.
+// Если у команды нет SLA, список пустой
+func (uc *Usecases) GetOverduePRs(ctx context.Context, teamName string) ([]*models.OverduePR, error) {
+	const op = "usecases.GetOverduePRs"
+	log := uc.log.With(slog.String("op", op), slog.String("team_name", teamName))
+
+	team, err := uc.db.GetTeamByName(ctx, nil, teamName)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, ErrTeamNotFound
+		}
+		log.Error("error getting team", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if team.ReviewSLAHours == nil {
+		return make([]*models.OverduePR, 0), nil
+	}
+
+	reviews, err := uc.db.GetUnreviewedAssignments(ctx, team.Id)
+	if err != nil {
+		log.Error("error getting unreviewed assignments", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	now := time.Now()
+	prs := make([]*models.OverduePR, 0)
+	for _, review := range reviews {
+		if !uc.overdue(review, now) {
+			continue
+		}
+		if len(prs) == 0 || prs[len(prs)-1].Id != review.PrId {
+			prs = append(prs, &models.OverduePR{
+				Id:       review.PrId,
+				Title:    review.Title,
+				AuthorId: review.AuthorId,
+				Reviews:  make([]*models.OverdueReview, 0),
+			})
+		}
+		pr := prs[len(prs)-1]
+		pr.Reviews = append(pr.Reviews, review)
+	}
+
+	log.Debug("overdue PRs got successfully", slog.Int("prs_count", len(prs)))
+	return prs, nil
+}
+
+// RunEscalationWorker раз в interval эскалирует ревью, нарушившие SLA команды. Работает до отмены ctx
+func (uc *Usecases) RunEscalationWorker(ctx context.Context, interval time.Duration) {
+	const op = "usecases.RunEscalationWorker"
+	log := uc.log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("escalation worker stopped")
+			return
+		case <-ticker.C:
+			count, err := uc.EscalateOverdueReviews(ctx)
+			if err != nil {
+				log.Error("error escalating overdue reviews", slog.String("error", err.Error()))
+				continue
+			}
+			if count > 0 {
+				log.Info("escalated overdue reviews", slog.Int("reviews_count", count))
+			}
+		}
+	}
+}
+
+// EscalateOverdueReviews эскалирует назначения без решения, нарушившие SLA команды автора PR'а:
+// записывает событие ESCALATED и переназначает ревью другому кандидату так же, как POST /pullRequest/reassign.
+// Если замены нет, ревьювер остаётся, а назначение помечается эскалированным и больше не проверяется.
+// Назначения перебираются пачками, каждая в своей транзакции. Возвращает количество эскалированных назначений
+func (uc *Usecases) EscalateOverdueReviews(ctx context.Context) (int, error) {
+	total := 0
+	afterPrId, afterUserId := uuid.Nil.String(), uuid.Nil.String()
+	for {
+		reviews, escalated, err := uc.escalateBatch(ctx, afterPrId, afterUserId)
+		if err != nil {
+			return total, err
+		}
+		total += escalated
+		if len(reviews) < escalationBatchSize {
+			return total, nil
+		}
+		last := reviews[len(reviews)-1]
+		afterPrId, afterUserId = last.PrId, last.ReviewerId
+	}
+}
+
+func (uc *Usecases) escalateBatch(ctx context.Context, afterPrId string, afterUserId string) ([]*models.OverdueReview, int, error) {
+	const op = "usecases.escalateBatch"
+	log := uc.log.With(slog.String("op", op))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	reviews, err := uc.db.ClaimUnreviewedAssignments(ctx, tx, afterPrId, afterUserId, escalationBatchSize)
+	if err != nil {
+		log.Error("error claiming unreviewed assignments", slog.String("error", err.Error()))
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	escalated := 0
+	for _, review := range reviews {
+		if !uc.overdue(review, now) {
+			continue
+		}
+		err = uc.escalateReview(ctx, tx, review)
+		if err != nil {
+			log.Error("error escalating review", slog.String("pr_id", review.PrId), slog.String("reviewer_id", review.ReviewerId), slog.String("error", err.Error()))
+			return nil, 0, err
+		}
+		escalated++
+	}
+
+	return reviews, escalated, nil
+}
+
+// escalateReview записывает эскалацию назначения и переназначает ревью. Переназначение выполняется в точке сохранения,
+// чтобы при отсутствии замены откатить только снятие ревьювера
+func (uc *Usecases) escalateReview(ctx context.Context, tx pgx.Tx, review *models.OverdueReview) error {
+	err := uc.recordEvents(ctx, tx, models.TriggerEscalation, &models.PREvent{
+		PrId:   review.PrId,
+		Kind:   models.EventEscalated,
+		UserId: review.ReviewerId,
+	})
+	if err != nil {
+		return err
+	}
+
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	_, _, err = uc.reassignReviewer(ctx, sp, review.PrId, review.ReviewerId, models.TriggerEscalation)
+	if err != nil {
+		if rbErr := sp.Rollback(ctx); rbErr != nil {
+			return rbErr
+		}
+		if !errors.Is(err, ErrNoCandidatesToAssign) {
+			return err
+		}
+		return uc.db.MarkEscalated(ctx, tx, review.PrId, review.ReviewerId)
+	}
+
+	return sp.Commit(ctx)
+}
+
+// overdue проверяет, нарушило ли назначение SLA к моменту now, и заполняет срок решения
+func (uc *Usecases) overdue(review *models.OverdueReview, now time.Time) bool {
+	review.Deadline = utils.AddWorkingHours(review.AssignedAt, review.SLAHours, uc.workingHours)
+	return !now.Before(review.Deadline)
+}
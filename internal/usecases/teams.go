@@ -8,14 +8,22 @@ import (
 	"pr-review/internal/models"
 	"pr-review/internal/repository/postgres"
 	"pr-review/internal/utils"
+	"slices"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 var (
-	ErrTeamNotFound     = errors.New("team not found")
-	ErrTeamAlredyExists = errors.New("team_name already exists")
-	ErrUserExists       = errors.New("one or more users with this usernames already exists")
+	ErrTeamNotFound         = errors.New("team not found")
+	ErrFallbackTeamNotFound = errors.New("fallback team not found")
+	ErrFallbackToSelf       = errors.New("team cannot be its own fallback")
+	ErrTeamAlredyExists     = errors.New("team_name already exists")
+	ErrUserExists           = errors.New("one or more users with this usernames already exists")
+	ErrNotTeamMember        = errors.New("user is not a member of the team")
+	ErrTargetTeamNotFound   = errors.New("target team not found")
+	ErrMergeIntoSelf        = errors.New("team cannot be merged into itself")
+	ErrTeamHasOpenPRs       = errors.New("team has open pull requests, set move_members_to")
 )
 
 func (uc *Usecases) GetTeam(ctx context.Context, name string) ([]*models.Member, error) {
@@ -44,9 +52,23 @@ func (uc *Usecases) GetTeam(ctx context.Context, name string) ([]*models.Member,
 
 // CreateTeam создаёт команду по имени, добавляет в неё участников/изменяет данные существующих пользователей по id
 // При изменении isActive у участника на false, все PR'ы снимаются с него и распределяются среди участников его команды
-// Если участников не хватает до двух PR'ов, то выставляется флаг need_more_reviewers
-// Для того, чтобы создание команды и добавление участников объединить в единую атомарную операцию, используется транзакция
+// Порядок выбора новых ревьюверов определяет стратегия команды (reviewer_strategy), по умолчанию - из конфига
+// Если участников не хватает до квоты ревьюверов команды (reviewers_per_pr), то выставляется флаг need_more_reviewers
+// Для того, чтобы создание команды и добавление участников объединить в единую атомарную операцию, используется транзакция.
+// После создания активные участники сразу назначаются на PR'ы, которым не хватает ревьюверов
 func (uc *Usecases) CreateTeam(ctx context.Context, reqDTO *dto.AddTeamRequest) error {
+	err := uc.createTeam(ctx, reqDTO)
+	if err != nil {
+		return err
+	}
+
+	if activated := activeMemberIds(reqDTO.Members); len(activated) > 0 {
+		uc.backfillAfterActivation(ctx, activated)
+	}
+	return nil
+}
+
+func (uc *Usecases) createTeam(ctx context.Context, reqDTO *dto.AddTeamRequest) error {
 	const op = "usecases.CreateTeam"
 	log := uc.log.With(slog.String("op", op), slog.String("name", reqDTO.Name))
 
@@ -54,6 +76,9 @@ func (uc *Usecases) CreateTeam(ctx context.Context, reqDTO *dto.AddTeamRequest)
 	team := &models.Team{
 		Id:   teamId,
 		Name: reqDTO.Name,
+		TeamSettings: models.TeamSettings{
+			ReviewerStrategy: reqDTO.ReviewerStrategy,
+		},
 	}
 
 	tx, err := uc.db.BeginTx(ctx)
@@ -87,79 +112,604 @@ func (uc *Usecases) CreateTeam(ctx context.Context, reqDTO *dto.AddTeamRequest)
 		return err
 	}
 
-	// добавляем/обновляем участников
-	err = uc.db.AddOrUpdateTeamMembers(ctx, tx, teamId, reqDTO.Members)
+	err = uc.addTeamMembers(ctx, tx, teamId, reqDTO.Members)
 	if err != nil {
 		if errors.Is(err, postgres.ErrUserExists) {
 			log.Warn("one of users with this username already exists")
 			return err
 		}
+		if errors.Is(err, ErrUserOffboarded) {
+			log.Warn("one of users is offboarded")
+			return err
+		}
 		log.Error("error adding team members", slog.String("error", err.Error()))
 		return err
 	}
 
-	// переассайниваем PR'ы с каждого неактивного участника
-	for _, member := range reqDTO.Members {
+	return nil
+}
+
+// addTeamMembers добавляет участников в команду/обновляет данные существующих пользователей.
+// Теги не переданных участников не меняются, с неактивных участников снимаются все открытые ревью
+func (uc *Usecases) addTeamMembers(ctx context.Context, tx pgx.Tx, teamId string, members []*models.Member) error {
+	for _, member := range members {
+		member.Tags = utils.NormalizeTags(member.Tags)
+	}
+	err := uc.db.AddOrUpdateTeamMembers(ctx, tx, teamId, members)
+	if err != nil {
+		if errors.Is(err, postgres.ErrUserOffboarded) {
+			return ErrUserOffboarded
+		}
+		return err
+	}
+
+	// неактивные участники проходят тот же сценарий деактивации, что и в POST /users/setIsActive
+	for _, member := range members {
 		if member.IsActive {
 			continue
 		}
 
-		prIds, err := uc.db.UnassignPRsFromUser(ctx, tx, member.Id)
+		_, err = uc.setUserActive(ctx, tx, member.Id, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeTeamMembers исключает пользователей userIds из команды и снимает с них открытые ревью, связанные с ней.
+// Если кто-то из них не состоит в команде, возвращается ErrNotTeamMember
+func (uc *Usecases) removeTeamMembers(ctx context.Context, tx pgx.Tx, teamId string, userIds []string) error {
+	removed, err := uc.db.RemoveTeamMembers(ctx, tx, teamId, userIds)
+	if err != nil {
+		return err
+	}
+	if len(removed) != len(userIds) {
+		return ErrNotTeamMember
+	}
+
+	for _, userId := range removed {
+		_, err = uc.reassignTeamReviews(ctx, tx, userId, teamId, models.TriggerMemberRemoval)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddTeamMembers добавляет участников в существующую команду/обновляет данные уже состоящих в ней.
+// Пользователь из другой команды остаётся и в ней. Неактивные участники снимаются с открытых ревью в той же транзакции,
+// а активные после неё сразу назначаются на PR'ы, которым не хватает ревьюверов
+func (uc *Usecases) AddTeamMembers(ctx context.Context, reqDTO *dto.TeamMembersRequest) ([]*models.Member, error) {
+	roster, err := uc.changeTeamMembers(ctx, "usecases.AddTeamMembers", reqDTO.Name, func(tx pgx.Tx, team *models.Team) error {
+		return uc.addTeamMembers(ctx, tx, team.Id, reqDTO.Members)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if activated := activeMemberIds(reqDTO.Members); len(activated) > 0 {
+		uc.backfillAfterActivation(ctx, activated)
+	}
+	return roster, nil
+}
+
+// RemoveTeamMembers исключает участников из команды, их открытые ревью PR'ов команды переназначаются в той же транзакции.
+// Если команда была для пользователя основной, основной команды у него не остаётся
+func (uc *Usecases) RemoveTeamMembers(ctx context.Context, reqDTO *dto.RemoveTeamMembersRequest) ([]*models.Member, error) {
+	return uc.changeTeamMembers(ctx, "usecases.RemoveTeamMembers", reqDTO.Name, func(tx pgx.Tx, team *models.Team) error {
+		return uc.removeTeamMembers(ctx, tx, team.Id, reqDTO.UserIds)
+	})
+}
+
+// SetTeamMembers заменяет состав команды на переданный (идемпотентно): участники не из списка исключаются,
+// остальные добавляются/обновляются. Всё выполняется в одной транзакции
+func (uc *Usecases) SetTeamMembers(ctx context.Context, reqDTO *dto.TeamMembersRequest) ([]*models.Member, error) {
+	roster, err := uc.changeTeamMembers(ctx, "usecases.SetTeamMembers", reqDTO.Name, func(tx pgx.Tx, team *models.Team) error {
+		current, err := uc.db.GetTeamMembersById(ctx, tx, team.Id)
 		if err != nil {
-			log.Error("error updating user team", slog.String("error", err.Error()))
 			return err
 		}
 
-		for _, prId := range prIds {
-			members, err := uc.db.GetMembers(ctx, tx, prId) // без author_id
+		var stale []string
+		for _, member := range current {
+			if !slices.ContainsFunc(reqDTO.Members, func(m *models.Member) bool { return m.Id == member.Id }) {
+				stale = append(stale, member.Id)
+			}
+		}
+		if len(stale) > 0 {
+			err = uc.removeTeamMembers(ctx, tx, team.Id, stale)
 			if err != nil {
-				log.Error("error updating user team", slog.String("error", err.Error()))
 				return err
 			}
-			members = delMember(members, member.Id)
+		}
+
+		return uc.addTeamMembers(ctx, tx, team.Id, reqDTO.Members)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if activated := activeMemberIds(reqDTO.Members); len(activated) > 0 {
+		uc.backfillAfterActivation(ctx, activated)
+	}
+	return roster, nil
+}
+
+// changeTeamMembers выполняет change над составом команды name в одной транзакции и возвращает новый состав
+func (uc *Usecases) changeTeamMembers(ctx context.Context, op, name string, change func(tx pgx.Tx, team *models.Team) error) (members []*models.Member, err error) {
+	log := uc.log.With(slog.String("op", op), slog.String("name", name))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		} else {
+			log.Debug("successfully changed team members")
+		}
+	}()
+
+	team, err := uc.db.GetTeamByName(ctx, tx, name)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			log.Warn("team not found")
+			err = ErrTeamNotFound
+			return nil, err
+		}
+		log.Error("error getting team", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	err = change(tx, team)
+	if err != nil {
+		if errors.Is(err, ErrNotTeamMember) || errors.Is(err, postgres.ErrUserExists) || errors.Is(err, ErrUserOffboarded) {
+			log.Warn("error changing team members", slog.String("error", err.Error()))
+			return nil, err
+		}
+		log.Error("error changing team members", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	members, err = uc.db.GetTeamMembersById(ctx, tx, team.Id)
+	if err != nil {
+		log.Error("error getting team members", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// RenameTeam меняет имя команды, её участники, настройки и правила не меняются
+func (uc *Usecases) RenameTeam(ctx context.Context, reqDTO *dto.RenameTeamRequest) (*models.Team, error) {
+	const op = "usecases.RenameTeam"
+	log := uc.log.With(slog.String("op", op), slog.String("name", reqDTO.Name), slog.String("new_name", reqDTO.NewName))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	team, err := uc.db.GetTeamByName(ctx, tx, reqDTO.Name)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			log.Warn("team not found")
+			err = ErrTeamNotFound
+			return nil, err
+		}
+		log.Error("error getting team", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	err = uc.db.RenameTeam(ctx, tx, team.Id, reqDTO.NewName)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamAlredyExists) {
+			log.Warn("team already exists")
+			err = ErrTeamAlredyExists
+			return nil, err
+		}
+		log.Error("error renaming team", slog.String("error", err.Error()))
+		return nil, err
+	}
+	team.Name = reqDTO.NewName
+
+	log.Debug("successfully renamed team")
+	return team, nil
+}
+
+// DeleteTeam удаляет команду вместе с её правилами ревьюверов, владением путями и резервными командами.
+// Если задана команда moveMembersTo, участники переводятся в неё с сохранением своих ревью, а need_more_reviewers
+// PR'ов команды пересчитывается по квоте новой команды. Иначе деактивируются участники, не состоящие в других командах,
+// а их открытые ревью переназначаются. Всё выполняется в одной транзакции. Возвращает id переведённых/деактивированных участников
+func (uc *Usecases) DeleteTeam(ctx context.Context, reqDTO *dto.DeleteTeamRequest) ([]string, error) {
+	members, err := uc.deleteTeam(ctx, reqDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	if reqDTO.MoveMembersTo != "" && len(members) > 0 {
+		uc.backfillAfterActivation(ctx, members)
+	}
+	return members, nil
+}
+
+func (uc *Usecases) deleteTeam(ctx context.Context, reqDTO *dto.DeleteTeamRequest) (members []string, err error) {
+	const op = "usecases.DeleteTeam"
+	log := uc.log.With(slog.String("op", op), slog.String("name", reqDTO.Name))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		} else {
+			log.Debug("successfully deleted team")
+		}
+	}()
+
+	team, err := uc.db.GetTeamByName(ctx, tx, reqDTO.Name)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			log.Warn("team not found")
+			err = ErrTeamNotFound
+			return nil, err
+		}
+		log.Error("error getting team", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if reqDTO.MoveMembersTo != "" {
+		var target *models.Team
+		target, err = uc.getTargetTeam(ctx, tx, team, reqDTO.MoveMembersTo)
+		if err != nil {
+			log.Warn("error getting target team", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		members, err = uc.moveTeamMembers(ctx, tx, team, target)
+		if err != nil {
+			log.Error("error moving team members", slog.String("error", err.Error()))
+			return nil, err
+		}
+	} else {
+		var openPRs int
+		openPRs, err = uc.db.CountTeamActivePRs(ctx, tx, team.Id)
+		if err != nil {
+			log.Error("error counting open team PRs", slog.String("error", err.Error()))
+			return nil, err
+		}
+		if openPRs > 0 {
+			log.Warn("team has open PRs", slog.Int("count", openPRs))
+			err = ErrTeamHasOpenPRs
+			return nil, err
+		}
 
-			// перемешаем members, чтобы назначать assignee в случайном порядке
-			utils.Shuffle(members)
+		members, err = uc.db.DeactivateTeamMembers(ctx, tx, team.Id)
+		if err != nil {
+			log.Error("error deactivating team members", slog.String("error", err.Error()))
+			return nil, err
+		}
 
-			newAssignerId, err := uc.db.AssignPRToUser(ctx, tx, prId, members)
+		for _, userId := range members {
+			_, err = uc.reassignReviews(ctx, tx, userId, models.TriggerDeactivation)
 			if err != nil {
-				log.Error("error updating user team", slog.String("error", err.Error()))
-				return err
+				log.Error("error reassigning reviews of deactivated member", slog.String("user_id", userId), slog.String("error", err.Error()))
+				return nil, err
 			}
+		}
+	}
 
-			if newAssignerId == "" { // новый аппрувер для PR'а не был найден, выставляем need_more_reviewers = true
-				err = uc.db.SetNeedMoreReviewers(ctx, tx, prId)
-				if err != nil {
-					if errors.Is(err, postgres.ErrPRNotFound) {
-						log.Warn("PR not found setting need_more_reviewers")
-						return ErrPRNotFound
-					}
-					log.Error("error updating user team", slog.String("error", err.Error()))
-					return err
-				}
+	err = uc.db.DeleteTeam(ctx, tx, team.Id)
+	if err != nil {
+		log.Error("error deleting team", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// MergeTeams сливает команду в другую: участники, правила ревьюверов, владение путями и резервные команды
+// переходят в целевую команду, после чего исходная удаляется. Настройки целевой команды не меняются,
+// need_more_reviewers открытых PR'ов переведённых авторов пересчитывается по её квоте. Возвращает новый состав целевой команды
+func (uc *Usecases) MergeTeams(ctx context.Context, reqDTO *dto.MergeTeamsRequest) ([]*models.Member, error) {
+	members, err := uc.mergeTeams(ctx, reqDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	if activated := activeMemberIds(members); len(activated) > 0 {
+		uc.backfillAfterActivation(ctx, activated)
+	}
+	return members, nil
+}
+
+func (uc *Usecases) mergeTeams(ctx context.Context, reqDTO *dto.MergeTeamsRequest) (members []*models.Member, err error) {
+	const op = "usecases.MergeTeams"
+	log := uc.log.With(slog.String("op", op), slog.String("name", reqDTO.Name), slog.String("into", reqDTO.IntoName))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
 			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		} else {
+			log.Debug("successfully merged teams")
+		}
+	}()
+
+	team, err := uc.db.GetTeamByName(ctx, tx, reqDTO.Name)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			log.Warn("team not found")
+			err = ErrTeamNotFound
+			return nil, err
 		}
+		log.Error("error getting team", slog.String("error", err.Error()))
+		return nil, err
 	}
 
-	return nil
+	target, err := uc.getTargetTeam(ctx, tx, team, reqDTO.IntoName)
+	if err != nil {
+		log.Warn("error getting target team", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	_, err = uc.moveTeamMembers(ctx, tx, team, target)
+	if err != nil {
+		log.Error("error moving team members", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	err = uc.db.TransferTeamRules(ctx, tx, team.Id, target.Id)
+	if err != nil {
+		log.Error("error transferring team rules", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	err = uc.db.DeleteTeam(ctx, tx, team.Id)
+	if err != nil {
+		log.Error("error deleting team", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	members, err = uc.db.GetTeamMembersById(ctx, tx, target.Id)
+	if err != nil {
+		log.Error("error getting team members", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// getTargetTeam возвращает команду name, в которую переводятся участники команды team
+func (uc *Usecases) getTargetTeam(ctx context.Context, tx pgx.Tx, team *models.Team, name string) (*models.Team, error) {
+	target, err := uc.db.GetTeamByName(ctx, tx, name)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			return nil, ErrTargetTeamNotFound
+		}
+		return nil, err
+	}
+	if target.Id == team.Id {
+		return nil, ErrMergeIntoSelf
+	}
+	return target, nil
+}
+
+// moveTeamMembers переводит всех участников команды from в команду to и пересчитывает need_more_reviewers
+// открытых PR'ов команды to. Ревью участников сохраняются
+func (uc *Usecases) moveTeamMembers(ctx context.Context, tx pgx.Tx, from *models.Team, to *models.Team) ([]string, error) {
+	moved, err := uc.db.MoveTeamMembers(ctx, tx, from.Id, to.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := uc.db.RefreshTeamNeedMoreReviewers(ctx, tx, to.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.recordEvents(ctx, tx, models.TriggerMemberMove, changes...)
+	if err != nil {
+		return nil, err
+	}
+
+	return moved, nil
 }
 
-func delMember(members []*models.Member, memberId string) []*models.Member {
-	filteredMembers := make([]*models.Member, 0)
-	for i := range members {
-		if members[i].Id != memberId {
-			filteredMembers = append(filteredMembers, members[i])
+func (uc *Usecases) GetTeamSettings(ctx context.Context, name string) (*models.Team, error) {
+	const op = "usecases.GetTeamSettings"
+	log := uc.log.With(slog.String("op", op), slog.String("name", name))
+
+	team, err := uc.db.GetTeamByName(ctx, nil, name)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, ErrTeamNotFound
 		}
+		log.Error("error getting team", slog.String("error", err.Error()))
+		return nil, err
 	}
-	return filteredMembers
+
+	err = uc.loadFallbackTeams(ctx, nil, team)
+	if err != nil {
+		log.Error("error getting fallback teams", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Debug("successfully got team settings")
+	return team, nil
 }
 
-func onlyActiveMembers(members []*models.Member) []*models.Member {
-	active := make([]*models.Member, 0)
-	for i := 0; i < len(members); i++ {
-		if members[i].IsActive {
-			active = append(active, members[i])
+// UpdateTeamSettings изменяет переданные в запросе настройки команды.
+// После изменения квоты ревьюверов need_more_reviewers у открытых PR'ов команды пересчитывается
+func (uc *Usecases) UpdateTeamSettings(ctx context.Context, reqDTO *dto.TeamSettingsRequest) (*models.Team, error) {
+	const op = "usecases.UpdateTeamSettings"
+	log := uc.log.With(slog.String("op", op), slog.String("name", reqDTO.Name))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		}
+	}()
+
+	team, err := uc.db.GetTeamByName(ctx, tx, reqDTO.Name)
+	if err != nil {
+		if errors.Is(err, postgres.ErrTeamNotFound) {
+			log.Warn("team not found")
+			return nil, ErrTeamNotFound
+		}
+		log.Error("error getting team", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if reqDTO.ReviewerStrategy != nil {
+		team.ReviewerStrategy = *reqDTO.ReviewerStrategy
+	}
+	if reqDTO.ReviewersPerPR != nil {
+		team.ReviewersPerPR = *reqDTO.ReviewersPerPR
+	}
+	if reqDTO.RequiredApprovals != nil {
+		team.RequiredApprovals = *reqDTO.RequiredApprovals
+	}
+	if reqDTO.MaxOpenReviews != nil {
+		team.MaxOpenReviews = reqDTO.MaxOpenReviews
+		if *reqDTO.MaxOpenReviews == 0 {
+			team.MaxOpenReviews = nil
+		}
+	}
+	if reqDTO.ReviewSLAHours != nil {
+		team.ReviewSLAHours = reqDTO.ReviewSLAHours
+		if *reqDTO.ReviewSLAHours == 0 {
+			team.ReviewSLAHours = nil
+		}
+	}
+
+	err = uc.db.UpdateTeamSettings(ctx, tx, team)
+	if err != nil {
+		log.Error("error updating team settings", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if reqDTO.ReviewersPerPR != nil {
+		var changes []*models.PREvent
+		changes, err = uc.db.RefreshTeamNeedMoreReviewers(ctx, tx, team.Id)
+		if err != nil {
+			log.Error("error refreshing need_more_reviewers", slog.String("error", err.Error()))
+			return nil, err
+		}
+		err = uc.recordEvents(ctx, tx, models.TriggerTeamSettings, changes...)
+		if err != nil {
+			log.Error("error recording PR events", slog.String("error", err.Error()))
+			return nil, err
 		}
 	}
-	return active
+
+	if reqDTO.FallbackTeams != nil {
+		fallbackTeamIds := make([]string, 0, len(*reqDTO.FallbackTeams))
+		for _, name := range *reqDTO.FallbackTeams {
+			var fallbackTeam *models.Team
+			fallbackTeam, err = uc.db.GetTeamByName(ctx, tx, name)
+			if err != nil {
+				if errors.Is(err, postgres.ErrTeamNotFound) {
+					log.Warn("fallback team not found", slog.String("fallback_team", name))
+					err = ErrFallbackTeamNotFound
+					return nil, err
+				}
+				log.Error("error getting fallback team", slog.String("error", err.Error()))
+				return nil, err
+			}
+			if fallbackTeam.Id == team.Id {
+				log.Warn("team cannot be its own fallback")
+				err = ErrFallbackToSelf
+				return nil, err
+			}
+			fallbackTeamIds = append(fallbackTeamIds, fallbackTeam.Id)
+		}
+
+		err = uc.db.SetFallbackTeams(ctx, tx, team.Id, fallbackTeamIds)
+		if err != nil {
+			log.Error("error setting fallback teams", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	err = uc.loadFallbackTeams(ctx, tx, team)
+	if err != nil {
+		log.Error("error getting fallback teams", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Debug("successfully updated team settings")
+	return team, nil
+}
+
+// loadFallbackTeams заполняет в настройках команды имена её резервных команд
+func (uc *Usecases) loadFallbackTeams(ctx context.Context, tx pgx.Tx, team *models.Team) error {
+	fallbackTeams, err := uc.db.GetFallbackTeams(ctx, tx, team.Id)
+	if err != nil {
+		return err
+	}
+
+	team.FallbackTeams = make([]string, 0, len(fallbackTeams))
+	for _, fallbackTeam := range fallbackTeams {
+		team.FallbackTeams = append(team.FallbackTeams, fallbackTeam.Name)
+	}
+	return nil
 }
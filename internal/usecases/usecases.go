@@ -2,9 +2,12 @@ package usecases
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
-	"pr-review/internal/http/dto"
+	"pr-review/internal/config"
 	"pr-review/internal/models"
+	"pr-review/internal/utils"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -13,35 +16,129 @@ type Storage interface {
 	BeginTx(ctx context.Context) (pgx.Tx, error)
 
 	UnassignPRsFromUser(ctx context.Context, tx pgx.Tx, userId string) ([]string, error)
+	UnassignTeamPRsFromUser(ctx context.Context, tx pgx.Tx, userId string, teamId string) ([]string, error)
 	UnassignPRFromUser(ctx context.Context, tx pgx.Tx, prId string, userId string) error
-	GetMembers(ctx context.Context, tx pgx.Tx, prId string) ([]*models.Member, error)
 	UserIsReviewerOfPR(ctx context.Context, tx pgx.Tx, prId string, userId string) (bool, error)
-	AssignPRToUser(ctx context.Context, tx pgx.Tx, prId string, members []*models.Member) (string, error)
-	SetNeedMoreReviewers(ctx context.Context, tx pgx.Tx, prId string) error
+	GetTeamMembersById(ctx context.Context, tx pgx.Tx, teamId string) ([]*models.Member, error)
+	AssignPRToUser(ctx context.Context, tx pgx.Tx, prId string, members []*models.Member, source models.AssignmentSource, sourceTeamId string) (string, error)
+	SetNeedMoreReviewers(ctx context.Context, tx pgx.Tx, prId string, reason models.NeedMoreReviewersReason) error
+	GetOpenReviewsCount(ctx context.Context, tx pgx.Tx, userIds []string) (map[string]int, error)
 	GetPRsByUserId(ctx context.Context, id string) ([]*models.PullRequest, error)
+	ListPRs(ctx context.Context, filter *models.PRListFilter) ([]*models.PullRequest, error)
 	CreatePR(ctx context.Context, tx pgx.Tx, pr *models.PullRequestShort) error
 	UpdatePR(ctx context.Context, tx pgx.Tx, pr *models.PullRequestShort) error
 	GetPRById(ctx context.Context, tx pgx.Tx, id string) (*models.PullRequest, error)
 	MergePR(ctx context.Context, tx pgx.Tx, id string) error
+	SetPRStatus(ctx context.Context, tx pgx.Tx, prId string, from models.Status, to models.Status) error
+	UnassignAllFromPR(ctx context.Context, tx pgx.Tx, prId string) ([]string, error)
+	ClaimUnderstaffedPRs(ctx context.Context, tx pgx.Tx, afterId string, limit int, userIds []string) ([]string, error)
+	GetUnreviewedAssignments(ctx context.Context, teamId string) ([]*models.OverdueReview, error)
+	ClaimUnreviewedAssignments(ctx context.Context, tx pgx.Tx, afterPrId string, afterUserId string, limit int) ([]*models.OverdueReview, error)
+	MarkEscalated(ctx context.Context, tx pgx.Tx, prId string, userId string) error
+	SetPRLabels(ctx context.Context, tx pgx.Tx, prId string, labels []string) error
+	GetUncoveredLabels(ctx context.Context, tx pgx.Tx, prId string) ([]string, error)
 	GetStatistics(ctx context.Context, page int, limit int) (map[string]int, uint64, error)
+	AddReview(ctx context.Context, tx pgx.Tx, review *models.Review) error
+	CountApprovals(ctx context.Context, tx pgx.Tx, prId string) (int, error)
 
 	TeamExists(ctx context.Context, name string) (bool, error)
 	CreateTeam(ctx context.Context, tx pgx.Tx, team *models.Team) error
+	GetTeamByPRId(ctx context.Context, tx pgx.Tx, prId string) (*models.Team, error)
+	GetTeamByName(ctx context.Context, tx pgx.Tx, name string) (*models.Team, error)
+	UpdateTeamSettings(ctx context.Context, tx pgx.Tx, team *models.Team) error
+	RefreshTeamNeedMoreReviewers(ctx context.Context, tx pgx.Tx, teamId string) ([]*models.PREvent, error)
+	GetFallbackTeams(ctx context.Context, tx pgx.Tx, teamId string) ([]*models.Team, error)
+	SetFallbackTeams(ctx context.Context, tx pgx.Tx, teamId string, fallbackTeamIds []string) error
+	GetReviewerRules(ctx context.Context, tx pgx.Tx, teamId string) ([]*models.ReviewerRule, error)
+	AddReviewerRule(ctx context.Context, teamId string, rule *models.ReviewerRule) error
+	DeleteReviewerRule(ctx context.Context, id string) error
+	GetLastReviewer(ctx context.Context, tx pgx.Tx, teamId string) (string, error)
+	SetLastReviewer(ctx context.Context, tx pgx.Tx, teamId string, userId string) error
+	RenameTeam(ctx context.Context, tx pgx.Tx, teamId string, name string) error
+	CountTeamActivePRs(ctx context.Context, tx pgx.Tx, teamId string) (int, error)
+	DeleteTeam(ctx context.Context, tx pgx.Tx, teamId string) error
+	TransferTeamRules(ctx context.Context, tx pgx.Tx, fromTeamId string, toTeamId string) error
 
 	GetTeamMembers(ctx context.Context, name string) ([]*models.Member, error)
 	AddOrUpdateTeamMembers(ctx context.Context, tx pgx.Tx, teamId string, members []*models.Member) error
-	UserSetIsActive(ctx context.Context, reqDTO *dto.SetIsActiveRequest) error
+	UserSetIsActive(ctx context.Context, tx pgx.Tx, userId string, isActive bool) error
+	UserSetTags(ctx context.Context, userId string, tags []string) error
+	UserSetMaxOpenReviews(ctx context.Context, userId string, limit int) error
+	UserSetPrimaryTeam(ctx context.Context, userId string, teamName string) error
+	OffboardUser(ctx context.Context, tx pgx.Tx, userId string) error
+	GetOpenPRIdsByAuthor(ctx context.Context, tx pgx.Tx, authorId string) ([]string, error)
+	GetReviewLimits(ctx context.Context, tx pgx.Tx, userIds []string) (map[string]int, error)
 	GetUserById(ctx context.Context, id string) (*models.User, error)
+	LockUsers(ctx context.Context, tx pgx.Tx, userIds []string) error
+	GetUsersIsActive(ctx context.Context, tx pgx.Tx, userIds []string) (map[string]bool, error)
+	GetUserIdByUsername(ctx context.Context, tx pgx.Tx, username string) (string, error)
+	GetUsersByIds(ctx context.Context, tx pgx.Tx, userIds []string) ([]*models.Member, error)
+	GetUsersWithTeamByIds(ctx context.Context, tx pgx.Tx, userIds []string) ([]*models.User, error)
+	RemoveTeamMembers(ctx context.Context, tx pgx.Tx, teamId string, userIds []string) ([]string, error)
+	MoveTeamMembers(ctx context.Context, tx pgx.Tx, fromTeamId string, toTeamId string) ([]string, error)
+	DeactivateTeamMembers(ctx context.Context, tx pgx.Tx, teamId string) ([]string, error)
+
+	GetOwnershipRules(ctx context.Context, tx pgx.Tx) ([]*models.OwnershipRule, error)
+	AddOwnershipRule(ctx context.Context, tx pgx.Tx, rule *models.OwnershipRule) error
+	DeleteOwnershipRule(ctx context.Context, id string) error
+	DeleteOwnershipRules(ctx context.Context, tx pgx.Tx) error
+	AddPRFiles(ctx context.Context, tx pgx.Tx, prId string, files []string) error
+	GetPRFiles(ctx context.Context, tx pgx.Tx, prId string) ([]string, error)
+
+	AddAbsence(ctx context.Context, tx pgx.Tx, absence *models.Absence) (bool, error)
+	GetAbsences(ctx context.Context, userId string) ([]*models.Absence, error)
+	DeleteAbsence(ctx context.Context, id string) (string, error)
+	ClaimStartedAbsences(ctx context.Context, tx pgx.Tx, limit int) ([]*models.Absence, error)
+
+	AddAssignmentDecision(ctx context.Context, tx pgx.Tx, decision *models.AssignmentDecision) error
+	GetAssignmentDecisions(ctx context.Context, prId string) ([]*models.AssignmentDecision, error)
+
+	AddPREvents(ctx context.Context, tx pgx.Tx, events []*models.PREvent) error
+	GetPREvents(ctx context.Context, prId string) ([]*models.PREvent, error)
 }
 
 type Usecases struct {
 	log *slog.Logger
 	db  Storage
+
+	selectors       map[models.ReviewerStrategy]ReviewerSelector
+	defaultStrategy models.ReviewerStrategy
+	// workingHours рабочее время, в котором идёт отсчёт SLA ревью
+	workingHours utils.WorkingHours
 }
 
-func New(log *slog.Logger, db Storage) *Usecases {
+func New(log *slog.Logger, cfg *config.ReviewConfig, db Storage) *Usecases {
+	defaultStrategy := models.ReviewerStrategy(cfg.DefaultStrategy)
+	if !defaultStrategy.Valid() {
+		panic("unknown default reviewer strategy: " + cfg.DefaultStrategy)
+	}
+	if cfg.WorkdayStartHour < 0 || cfg.WorkdayStartHour >= cfg.WorkdayEndHour || cfg.WorkdayEndHour > 24 {
+		panic(fmt.Sprintf("invalid workday hours: %d-%d", cfg.WorkdayStartHour, cfg.WorkdayEndHour))
+	}
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		panic("unknown timezone: " + cfg.Timezone)
+	}
+	// time.NewTicker в фоновых задачах паникует на неположительном интервале
+	for name, interval := range map[string]time.Duration{
+		"absence check":    cfg.AbsenceCheckInterval,
+		"backfill":         cfg.BackfillInterval,
+		"escalation check": cfg.EscalationCheckInterval,
+	} {
+		if interval <= 0 {
+			panic(fmt.Sprintf("invalid %s interval: %s", name, interval))
+		}
+	}
+
 	return &Usecases{
-		log: log,
-		db:  db,
+		log:             log,
+		db:              db,
+		selectors:       newSelectors(db),
+		defaultStrategy: defaultStrategy,
+		workingHours: utils.WorkingHours{
+			StartHour: cfg.WorkdayStartHour,
+			EndHour:   cfg.WorkdayEndHour,
+			Location:  location,
+		},
 	}
 }
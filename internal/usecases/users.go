@@ -3,37 +3,306 @@ package usecases
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"pr-review/internal/http/dto"
 	"pr-review/internal/models"
 	"pr-review/internal/repository/postgres"
+	"pr-review/internal/utils"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
 )
 
 var (
 	ErrUserNotFound = errors.New("resourse not found")
 )
 
-func (uc *Usecases) UserSetIsActive(ctx context.Context, reqDTO *dto.SetIsActiveRequest) (*models.User, error) {
+// UserSetIsActive меняет активность пользователя. Открытые ревью деактивированного пользователя переназначаются
+// в той же транзакции, а активированный сразу назначается на PR'ы, которым не хватает ревьюверов.
+// Возвращает пользователя и снятые с него ревью с заменами
+func (uc *Usecases) UserSetIsActive(ctx context.Context, reqDTO *dto.SetIsActiveRequest) (*models.User, []*models.ReviewReassignment, error) {
+	const op = "usecases.UserSetIsActive"
+	log := uc.log.With(slog.String("op", op), slog.String("user_id", reqDTO.UserId))
+
+	reassigned, err := uc.userSetIsActive(ctx, reqDTO)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if *reqDTO.IsActive {
+		uc.backfillAfterActivation(ctx, []string{reqDTO.UserId})
+	}
+
+	user, err := uc.db.GetUserById(ctx, reqDTO.UserId)
+	if err != nil {
+		log.Error("error getting user by id", slog.String("error", err.Error()))
+		return nil, nil, err
+	}
+	log.Debug("user got successfully", slog.Any("user", user))
+
+	return user, reassigned, nil
+}
+
+func (uc *Usecases) userSetIsActive(ctx context.Context, reqDTO *dto.SetIsActiveRequest) (reassigned []*models.ReviewReassignment, err error) {
 	const op = "usecases.UserSetIsActive"
 	log := uc.log.With(slog.String("op", op), slog.String("user_id", reqDTO.UserId))
 
-	err := uc.db.UserSetIsActive(ctx, reqDTO)
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		} else {
+			log.Debug("set is_active successfully", slog.Int("reassigned", len(reassigned)))
+		}
+	}()
+
+	reassigned, err = uc.setUserActive(ctx, tx, reqDTO.UserId, *reqDTO.IsActive)
+	if err != nil {
+		if errors.Is(err, postgres.ErrUserNotFound) {
+			log.Warn("user not found")
+			err = ErrUserNotFound
+			return nil, err
+		}
+		if errors.Is(err, ErrUserOffboarded) {
+			log.Warn("user is offboarded")
+			return nil, err
+		}
+		log.Error("error setting is_active", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return reassigned, nil
+}
+
+// setUserActive меняет активность пользователя userId. Деактивированный пользователь снимается со всех открытых ревью:
+// на каждый PR подбирается замена из команды PR'а, а если её нет, у PR'а выставляется need_more_reviewers.
+// Это общий сценарий деактивации для POST /users/setIsActive и неактивных участников в POST /team/add.
+// Уволенного пользователя активировать нельзя: ErrUserOffboarded
+func (uc *Usecases) setUserActive(ctx context.Context, tx pgx.Tx, userId string, isActive bool) ([]*models.ReviewReassignment, error) {
+	err := uc.db.UserSetIsActive(ctx, tx, userId, isActive)
+	if err != nil {
+		if errors.Is(err, postgres.ErrUserOffboarded) {
+			return nil, ErrUserOffboarded
+		}
+		return nil, err
+	}
+	if isActive {
+		return []*models.ReviewReassignment{}, nil
+	}
+
+	return uc.reassignReviews(ctx, tx, userId, models.TriggerDeactivation)
+}
+
+// BulkSetIsActive меняет активность сразу нескольких пользователей: списка user_ids или всех участников команды team_name.
+// Все изменения применяются в одной транзакции по сценарию POST /users/setIsActive: если хотя бы одного пользователя
+// нет, не меняется никто. Возвращает результат для каждого пользователя в порядке id
+func (uc *Usecases) BulkSetIsActive(ctx context.Context, reqDTO *dto.BulkSetIsActiveRequest) ([]*models.ActivationChange, error) {
+	changes, err := uc.bulkSetIsActive(ctx, reqDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	if *reqDTO.IsActive {
+		activated := make([]string, 0, len(changes))
+		for _, c := range changes {
+			if c.Changed {
+				activated = append(activated, c.UserId)
+			}
+		}
+		if len(activated) > 0 {
+			uc.backfillAfterActivation(ctx, activated)
+		}
+	}
+
+	return changes, nil
+}
+
+func (uc *Usecases) bulkSetIsActive(ctx context.Context, reqDTO *dto.BulkSetIsActiveRequest) (changes []*models.ActivationChange, err error) {
+	const op = "usecases.BulkSetIsActive"
+	log := uc.log.With(slog.String("op", op), slog.String("team_name", reqDTO.TeamName), slog.Bool("is_active", *reqDTO.IsActive))
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Error("error beginning transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				log.Error("error rolling back transaction", slog.String("error", rbErr.Error()))
+			}
+			return
+		}
+		if cmErr := tx.Commit(ctx); cmErr != nil {
+			log.Error("error committing transaction", slog.String("error", cmErr.Error()))
+			err = cmErr
+		} else {
+			log.Debug("bulk set is_active successfully", slog.Int("users", len(changes)))
+		}
+	}()
+
+	userIds := slices.Clone(reqDTO.UserIds)
+	if reqDTO.TeamName != "" {
+		var team *models.Team
+		team, err = uc.db.GetTeamByName(ctx, tx, reqDTO.TeamName)
+		if err != nil {
+			if errors.Is(err, postgres.ErrTeamNotFound) {
+				log.Warn("team not found")
+				err = ErrTeamNotFound
+				return nil, err
+			}
+			log.Error("error getting team by name", slog.String("error", err.Error()))
+			return nil, err
+		}
+		var members []*models.Member
+		members, err = uc.db.GetTeamMembersById(ctx, tx, team.Id)
+		if err != nil {
+			log.Error("error getting team members", slog.String("error", err.Error()))
+			return nil, err
+		}
+		userIds = make([]string, 0, len(members))
+		for _, m := range members {
+			userIds = append(userIds, m.Id)
+		}
+	}
+	slices.Sort(userIds)
+
+	flags, err := uc.db.GetUsersIsActive(ctx, tx, userIds)
+	if err != nil {
+		log.Error("error locking users", slog.String("error", err.Error()))
+		return nil, err
+	}
+	missing := slices.DeleteFunc(slices.Clone(userIds), func(id string) bool {
+		_, ok := flags[id]
+		return ok
+	})
+	if len(missing) > 0 {
+		log.Warn("users not found", slog.Any("user_ids", missing))
+		err = fmt.Errorf("%w: %s", ErrUserNotFound, strings.Join(missing, ", "))
+		return nil, err
+	}
+
+	changes = make([]*models.ActivationChange, 0, len(userIds))
+	for _, userId := range userIds {
+		change := &models.ActivationChange{
+			UserId:   userId,
+			IsActive: *reqDTO.IsActive,
+			Changed:  flags[userId] != *reqDTO.IsActive,
+		}
+		change.Reassigned, err = uc.setUserActive(ctx, tx, userId, *reqDTO.IsActive)
+		if err != nil {
+			if errors.Is(err, ErrUserOffboarded) {
+				log.Warn("user is offboarded", slog.String("user_id", userId))
+				return nil, err
+			}
+			log.Error("error setting is_active", slog.String("user_id", userId), slog.String("error", err.Error()))
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+func (uc *Usecases) UserSetTags(ctx context.Context, reqDTO *dto.SetTagsRequest) (*models.User, error) {
+	const op = "usecases.UserSetTags"
+	log := uc.log.With(slog.String("op", op), slog.String("user_id", reqDTO.UserId))
+
+	err := uc.db.UserSetTags(ctx, reqDTO.UserId, utils.NormalizeTags(reqDTO.Tags))
 	if err != nil {
 		if errors.Is(err, postgres.ErrUserNotFound) {
-			log.Error("user not found", slog.String("user_id", reqDTO.UserId))
+			log.Warn("user not found")
 			return nil, ErrUserNotFound
 		}
-		log.Error("error setting isActive", slog.String("error", err.Error()))
+		log.Error("error setting tags", slog.String("error", err.Error()))
 		return nil, err
 	}
-	log.Debug("set is_active successfully")
+	log.Debug("set tags successfully")
 
 	user, err := uc.db.GetUserById(ctx, reqDTO.UserId)
 	if err != nil {
 		log.Error("error getting user by id", slog.String("error", err.Error()))
 		return nil, err
 	}
-	log.Debug("user got successfully", slog.Any("user", user))
+
+	return user, nil
+}
+
+// UserSetMaxOpenReviews задаёт ограничение открытых ревью пользователя. Уже назначенные ревью не снимаются
+func (uc *Usecases) UserSetMaxOpenReviews(ctx context.Context, reqDTO *dto.SetMaxOpenReviewsRequest) (*models.User, error) {
+	const op = "usecases.UserSetMaxOpenReviews"
+	log := uc.log.With(slog.String("op", op), slog.String("user_id", reqDTO.UserId))
+
+	err := uc.db.UserSetMaxOpenReviews(ctx, reqDTO.UserId, *reqDTO.MaxOpenReviews)
+	if err != nil {
+		if errors.Is(err, postgres.ErrUserNotFound) {
+			log.Warn("user not found")
+			return nil, ErrUserNotFound
+		}
+		log.Error("error setting max_open_reviews", slog.String("error", err.Error()))
+		return nil, err
+	}
+	log.Debug("set max_open_reviews successfully")
+
+	user, err := uc.db.GetUserById(ctx, reqDTO.UserId)
+	if err != nil {
+		log.Error("error getting user by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UserSetPrimaryTeam делает одну из команд пользователя основной: в ней по умолчанию открываются его PR'ы.
+// Команды уже открытых PR'ов не меняются
+func (uc *Usecases) UserSetPrimaryTeam(ctx context.Context, reqDTO *dto.SetPrimaryTeamRequest) (*models.User, error) {
+	const op = "usecases.UserSetPrimaryTeam"
+	log := uc.log.With(slog.String("op", op), slog.String("user_id", reqDTO.UserId), slog.String("team_name", reqDTO.TeamName))
+
+	user, err := uc.db.GetUserById(ctx, reqDTO.UserId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrUserNotFound) {
+			log.Warn("user not found")
+			return nil, ErrUserNotFound
+		}
+		log.Error("error getting user by id", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if !slices.Contains(user.Teams, reqDTO.TeamName) {
+		log.Warn("user is not a member of the team")
+		return nil, ErrNotTeamMember
+	}
+
+	err = uc.db.UserSetPrimaryTeam(ctx, reqDTO.UserId, reqDTO.TeamName)
+	if err != nil {
+		if errors.Is(err, postgres.ErrUserNotFound) {
+			// пользователя исключили из команды параллельно
+			log.Warn("user is not a member of the team")
+			return nil, ErrNotTeamMember
+		}
+		log.Error("error setting primary team", slog.String("error", err.Error()))
+		return nil, err
+	}
+	log.Debug("set primary team successfully")
+
+	user, err = uc.db.GetUserById(ctx, reqDTO.UserId)
+	if err != nil {
+		log.Error("error getting user by id", slog.String("error", err.Error()))
+		return nil, err
+	}
 
 	return user, nil
 }
@@ -0,0 +1,16 @@
+package utils
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor возвращает контекст, в котором сохранён выполняющий операцию пользователь или сервис
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает сохранённого в контексте выполняющего операцию или пустую строку
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CodeownersLine строка файла CODEOWNERS: шаблон пути и его владельцы
+type CodeownersLine struct {
+	Pattern string
+	Owners  []string
+}
+
+// ParseCodeowners разбирает содержимое файла в формате CODEOWNERS.
+// Пустые строки и комментарии пропускаются, владельцы возвращаются без префикса @
+func ParseCodeowners(content string) ([]*CodeownersLine, error) {
+	lines := make([]*CodeownersLine, 0)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if _, err := CompileOwnersPattern(fields[0]); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+
+		owners := make([]string, 0, len(fields)-1)
+		for _, owner := range fields[1:] {
+			owner = strings.TrimPrefix(owner, "@")
+			// @org/team - берём имя команды без организации
+			if i := strings.LastIndex(owner, "/"); i >= 0 {
+				owner = owner[i+1:]
+			}
+			if owner == "" {
+				return nil, fmt.Errorf("line %d: empty owner", n)
+			}
+			owners = append(owners, owner)
+		}
+
+		lines = append(lines, &CodeownersLine{
+			Pattern: fields[0],
+			Owners:  owners,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// CompileOwnersPattern преобразует шаблон пути в стиле CODEOWNERS в регулярное выражение:
+//   - шаблон без "/" в начале или в середине совпадает на любой глубине;
+//   - "*" и "?" не переходят через "/", "**" совпадает с любым количеством каталогов;
+//   - шаблон, совпавший с каталогом, распространяется на всё его содержимое, кроме шаблонов вида "dir/*"
+func CompileOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	p := pattern
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(strings.TrimSuffix(p, "/"), "/")
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("invalid pattern %q", pattern)
+	}
+
+	var re strings.Builder
+	if anchored {
+		re.WriteString("^")
+	} else {
+		re.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case p[i] == '*':
+			re.WriteString("[^/]*")
+		case p[i] == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(p[i])))
+		}
+	}
+
+	if strings.HasSuffix(p, "/*") {
+		re.WriteString("$")
+	} else {
+		re.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(re.String())
+}
@@ -2,10 +2,29 @@ package utils
 
 import (
 	"math/rand"
+	"slices"
+	"strings"
 )
 
-func Shuffle[T comparable](a []T) {
-	rand.Shuffle(len(a), func(i, j int) {
+// Shuffle перемешивает a генератором rng, при одинаковом состоянии rng порядок получается одинаковым
+func Shuffle[T comparable](rng *rand.Rand, a []T) {
+	rng.Shuffle(len(a), func(i, j int) {
 		a[i], a[j] = a[j], a[i]
 	})
 }
+
+// NormalizeTags приводит теги к нижнему регистру, обрезает пробелы и удаляет повторы.
+// nil остаётся nil, чтобы можно было отличить "теги не переданы" от пустого списка
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,43 @@
+package utils
+
+import "time"
+
+// WorkingHours рабочее время: часы с StartHour до EndHour будних дней в часовом поясе Location
+type WorkingHours struct {
+	StartHour int
+	EndHour   int
+	Location  *time.Location
+}
+
+// AddWorkingHours возвращает момент, когда после start пройдёт hours рабочих часов wh.
+// Время вне рабочего окна, суббота и воскресенье пропускаются, результат - в UTC
+func AddWorkingHours(start time.Time, hours int, wh WorkingHours) time.Time {
+	t := start.In(wh.Location)
+	remaining := time.Duration(hours) * time.Hour
+	for remaining > 0 {
+		year, month, day := t.Date()
+		nextDay := time.Date(year, month, day+1, 0, 0, 0, 0, wh.Location)
+		if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+			t = nextDay
+			continue
+		}
+
+		open := time.Date(year, month, day, wh.StartHour, 0, 0, 0, wh.Location)
+		closing := time.Date(year, month, day, wh.EndHour, 0, 0, 0, wh.Location)
+		if t.Before(open) {
+			t = open
+		}
+		if !t.Before(closing) {
+			t = nextDay
+			continue
+		}
+
+		if left := closing.Sub(t); remaining <= left {
+			return t.Add(remaining).UTC()
+		}
+		remaining -= closing.Sub(t)
+		t = nextDay
+	}
+
+	return t.UTC()
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddWorkingHours(t *testing.T) {
+	// 2024-01-01 - понедельник
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+	office := WorkingHours{StartHour: 9, EndHour: 18, Location: time.UTC}
+	msk := time.FixedZone("MSK", 3*60*60)
+
+	tests := []struct {
+		name  string
+		start time.Time
+		hours int
+		wh    WorkingHours
+		want  time.Time
+	}{
+		{
+			name:  "within working day",
+			start: at(1, 10, 0),
+			hours: 3,
+			wh:    office,
+			want:  at(1, 13, 0),
+		},
+		{
+			name:  "ends exactly at closing",
+			start: at(1, 9, 0),
+			hours: 9,
+			wh:    office,
+			want:  at(1, 18, 0),
+		},
+		{
+			name:  "overnight",
+			start: at(1, 16, 30),
+			hours: 4,
+			wh:    office,
+			want:  at(2, 11, 30),
+		},
+		{
+			name:  "before opening",
+			start: at(1, 7, 0),
+			hours: 1,
+			wh:    office,
+			want:  at(1, 10, 0),
+		},
+		{
+			name:  "after closing",
+			start: at(1, 20, 0),
+			hours: 1,
+			wh:    office,
+			want:  at(2, 10, 0),
+		},
+		{
+			name:  "over weekend",
+			start: at(5, 17, 0),
+			hours: 2,
+			wh:    office,
+			want:  at(8, 10, 0),
+		},
+		{
+			name:  "starts on weekend",
+			start: at(6, 12, 0),
+			hours: 1,
+			wh:    office,
+			want:  at(8, 10, 0),
+		},
+		{
+			name:  "24 working hours are several days",
+			start: at(1, 9, 0),
+			hours: 24,
+			wh:    office,
+			want:  at(3, 15, 0),
+		},
+		{
+			name:  "timezone",
+			start: at(1, 5, 0),
+			hours: 1,
+			wh:    WorkingHours{StartHour: 9, EndHour: 18, Location: msk},
+			want:  at(1, 7, 0),
+		},
+		{
+			name:  "timezone closing is earlier in UTC",
+			start: at(1, 14, 30),
+			hours: 1,
+			wh:    WorkingHours{StartHour: 9, EndHour: 18, Location: msk},
+			want:  at(2, 6, 30),
+		},
+		{
+			name:  "whole weekdays",
+			start: at(5, 20, 0),
+			hours: 5,
+			wh:    WorkingHours{StartHour: 0, EndHour: 24, Location: time.UTC},
+			want:  at(8, 1, 0),
+		},
+		{
+			name:  "zero hours",
+			start: at(6, 12, 0),
+			hours: 0,
+			wh:    office,
+			want:  at(6, 12, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, AddWorkingHours(tt.start, tt.hours, tt.wh))
+		})
+	}
+}
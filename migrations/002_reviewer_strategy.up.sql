@@ -0,0 +1,3 @@
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS reviewer_strategy VARCHAR(32);
+-- последний назначенный ревьювер команды, курсор для стратегии ROUND_ROBIN
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS last_reviewer_id UUID;
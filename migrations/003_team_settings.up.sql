@@ -0,0 +1 @@
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS reviewers_per_pr INT NOT NULL DEFAULT 2 CHECK (reviewers_per_pr > 0);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    fallback_team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    PRIMARY KEY (team_id, fallback_team_id)
+);
+
+-- источник, из которого взят ревьювер: TEAM - команда автора, FALLBACK - резервная команда
+ALTER TABLE pull_requests_users ADD COLUMN IF NOT EXISTS source VARCHAR(16) NOT NULL DEFAULT 'TEAM';
+ALTER TABLE pull_requests_users ADD COLUMN IF NOT EXISTS source_team_id UUID REFERENCES teams(id) ON DELETE SET NULL;
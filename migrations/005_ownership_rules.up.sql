@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS ownership_rules (
+    id UUID PRIMARY KEY,
+    pattern VARCHAR(1024) NOT NULL,
+    -- порядок правил как в CODEOWNERS: при совпадении нескольких правил применяется последнее
+    position BIGSERIAL NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ownership_rule_owners (
+    rule_id UUID NOT NULL REFERENCES ownership_rules(id) ON DELETE CASCADE,
+    team_id UUID REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    CHECK ((team_id IS NULL) <> (user_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS ownership_rule_owners_rule_id_idx ON ownership_rule_owners (rule_id);
+
+CREATE TABLE IF NOT EXISTS pull_request_files (
+    pr_id UUID NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    path VARCHAR(1024) NOT NULL,
+    PRIMARY KEY (pr_id, path)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS assignment_decisions (
+    id UUID PRIMARY KEY,
+    pr_id UUID NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    trigger VARCHAR(32) NOT NULL,
+    seed BIGINT NOT NULL,
+    -- шаги подбора: источник, стратегия, кандидаты в порядке стратегии, исключённые кандидаты с причиной, назначенные
+    steps JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    position BIGSERIAL NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS assignment_decisions_pr_id_idx ON assignment_decisions (pr_id);
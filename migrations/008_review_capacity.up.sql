@@ -0,0 +1,5 @@
+-- ограничение количества открытых ревью: у пользователя, а если не задано - по умолчанию его команды. NULL - без ограничения
+ALTER TABLE users ADD COLUMN IF NOT EXISTS max_open_reviews INT CHECK (max_open_reviews > 0);
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS max_open_reviews INT CHECK (max_open_reviews > 0);
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS need_more_reviewers_reason VARCHAR(32);
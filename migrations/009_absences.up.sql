@@ -0,0 +1,14 @@
+-- периоды отсутствия пользователей: внутри периода пользователь считается неактивным
+CREATE TABLE IF NOT EXISTS absences (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    -- когда с пользователя были сняты открытые ревью, NULL - период ещё не начался или не обработан
+    reassigned_at TIMESTAMPTZ,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS absences_user_id_idx ON absences (user_id, ends_at);
+CREATE INDEX IF NOT EXISTS absences_pending_idx ON absences (starts_at) WHERE reassigned_at IS NULL;
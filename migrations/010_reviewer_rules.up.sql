@@ -0,0 +1,10 @@
+-- правила команды для пары автор - ревьювер: EXCLUDE - никогда не назначать, INCLUDE - назначать всегда
+CREATE TABLE IF NOT EXISTS reviewer_rules (
+    id UUID PRIMARY KEY,
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('EXCLUDE', 'INCLUDE')),
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (team_id, author_id, reviewer_id),
+    CHECK (author_id <> reviewer_id)
+);
@@ -0,0 +1,15 @@
+-- решения ревьюверов по PR'у, текущим считается последнее решение ревьювера
+CREATE TABLE IF NOT EXISTS reviews (
+    id UUID PRIMARY KEY,
+    pr_id UUID NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    decision VARCHAR(32) NOT NULL CHECK (decision IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    position BIGSERIAL NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS reviews_pr_id_user_id_idx ON reviews (pr_id, user_id, position);
+
+-- сколько одобрений нужно для merge, 0 - без ограничения
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS required_approvals INT NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
@@ -0,0 +1,2 @@
+-- DRAFT - ревьюверы не назначаются, пока PR не готов к ревью; CLOSED - PR закрыт без merge
+INSERT INTO statuses (name) VALUES ('DRAFT'), ('CLOSED') ON CONFLICT (name) DO NOTHING;
@@ -0,0 +1,15 @@
+-- история изменений PR'а: назначения и снятия ревьюверов, смены статуса, автора и need_more_reviewers
+CREATE TABLE IF NOT EXISTS pr_events (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id UUID NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL,
+    -- ревьювер для ASSIGNED/UNASSIGNED, пользователь может быть уже удалён, поэтому без внешнего ключа
+    user_id UUID,
+    from_value VARCHAR(64),
+    to_value VARCHAR(64),
+    reason VARCHAR(32) NOT NULL,
+    actor VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS pr_events_pr_id_idx ON pr_events (pr_id, id);
@@ -0,0 +1,2 @@
+-- воркер дозаполнения ревьюверов перебирает PR'ы с need_more_reviewers по id
+CREATE INDEX IF NOT EXISTS pull_requests_need_more_reviewers_idx ON pull_requests (id) WHERE need_more_reviewers;
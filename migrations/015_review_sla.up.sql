@@ -0,0 +1,6 @@
+-- когда ревьювер назначен и когда его назначение эскалировано по SLA команды
+ALTER TABLE pull_requests_users ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE pull_requests_users ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMPTZ;
+
+-- SLA ревью в рабочих часах, NULL - без SLA
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS review_sla_hours INT CHECK (review_sla_hours > 0);
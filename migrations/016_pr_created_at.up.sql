@@ -0,0 +1,14 @@
+-- время создания PR'а для списка PR'ов; у существующих PR'ов берётся из истории, если она есть
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+UPDATE pull_requests pr SET created_at = e.created_at
+FROM (
+    SELECT pr_id, MIN(created_at) AS created_at FROM pr_events
+    WHERE kind = 'CREATED'
+    GROUP BY pr_id
+) e
+WHERE e.pr_id = pr.id;
+
+-- курсорная пагинация списка PR'ов по времени создания и merge
+CREATE INDEX IF NOT EXISTS pull_requests_created_at_idx ON pull_requests (created_at, id);
+CREATE INDEX IF NOT EXISTS pull_requests_merged_at_idx ON pull_requests (merged_at, id) WHERE merged_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- пользователь, удалённый из команды, остаётся без команды, но сохраняет историю ревью и авторства
+ALTER TABLE users ALTER COLUMN team_id DROP NOT NULL;
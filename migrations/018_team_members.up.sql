@@ -0,0 +1,21 @@
+-- пользователь может состоять в нескольких командах, users.team_id - его основная команда (может быть не задана)
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS team_members_user_id_idx ON team_members (user_id);
+
+UPDATE users SET team_id = NULL WHERE team_id IS NOT NULL AND team_id NOT IN (SELECT id FROM teams);
+INSERT INTO team_members (team_id, user_id)
+SELECT team_id, id FROM users WHERE team_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users ADD CONSTRAINT users_team_id_fkey FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE SET NULL;
+
+-- команда PR'а: основная команда автора или явно указанная при создании, из неё подбираются ревьюверы
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS team_id UUID REFERENCES teams(id) ON DELETE SET NULL;
+UPDATE pull_requests pr SET team_id = u.team_id FROM users u WHERE pr.author_id = u.id AND pr.team_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS pull_requests_team_id_idx ON pull_requests (team_id);
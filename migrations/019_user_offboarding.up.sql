@@ -0,0 +1,2 @@
+-- время offboarding'а пользователя, NULL - не уволен. Строка пользователя остаётся ради истории и статистики
+ALTER TABLE users ADD COLUMN IF NOT EXISTS offboarded_at TIMESTAMPTZ;